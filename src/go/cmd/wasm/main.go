@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"syscall/js"
+	"time"
+	_ "time/tzdata" // risk config time zones; browsers have no zoneinfo database
 
 	"uar/pkg/engine"
 	"uar/pkg/parser"
+	"uar/pkg/report"
+	"uar/pkg/report/export"
 	"uar/pkg/schema"
 )
 
@@ -15,6 +21,19 @@ import (
 
 var globalSoTIndex *engine.SoTIndex
 
+// globalRiskPolicy is the risk policy selected with uarLoadRiskPolicy for
+// uarMergeResults in this WASM instance; nil means the default policy.
+var globalRiskPolicy *engine.RiskPolicy
+
+// globalSoDMatrix is the segregation-of-duties matrix selected with uarLoadSoDMatrix;
+// nil means no SoD checks.
+var globalSoDMatrix *engine.SoDMatrix
+
+// globalRiskConfig holds the dormancy thresholds, privilege catalog, processing
+// timestamp, and time zone selected with uarLoadRiskConfig; the zero value uses the
+// defaults.
+var globalRiskConfig engine.RiskConfig
+
 // parseSoT handles the uarParseSoT JS function call.
 // args[0] = Uint8Array (CSV bytes)
 // args[1] = string (column map JSON)
@@ -74,6 +93,10 @@ func loadSoTIndex(this js.Value, args []js.Value) interface{} {
 // args[0] = Uint8Array (CSV bytes)
 // args[1] = string (system name)
 // args[2] = string (column map JSON)
+// args[3] = string (optional override table JSON of reviewer-confirmed links)
+// args[4] = string (optional ClassifierConfig JSON for non-human account detection)
+// args[5] = string (optional JSON array of ConflictCheck for field conflict detection)
+// args[6] = string (optional MatchRules JSON or YAML: alias domains, nicknames, ID normalization)
 // PRECONDITION: loadSoTIndex() must have been called first in this worker.
 func parseSatellite(this js.Value, args []js.Value) interface{} {
 	if globalSoTIndex == nil {
@@ -98,17 +121,510 @@ func parseSatellite(this js.Value, args []js.Value) interface{} {
 		return string(errJSON)
 	}
 
+	var opts engine.JoinOptions
+	if len(args) > 3 && args[3].Type() == js.TypeString {
+		opts.Overrides, err = engine.ParseOverrideTable([]byte(args[3].String()))
+		if err != nil {
+			errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+			return string(errJSON)
+		}
+	}
+	if len(args) > 4 && args[4].Type() == js.TypeString && args[4].String() != "" {
+		var cfg engine.ClassifierConfig
+		if err := json.Unmarshal([]byte(args[4].String()), &cfg); err != nil {
+			errJSON, _ := json.Marshal(map[string]string{"error": "invalid classifier config JSON: " + err.Error()})
+			return string(errJSON)
+		}
+		opts.Classifier, err = engine.NewAccountClassifier(cfg)
+		if err != nil {
+			errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+			return string(errJSON)
+		}
+	}
+
+	if opts.ConflictChecks, err = conflictChecksArg(args, 5); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+	if len(args) > 6 && args[6].Type() == js.TypeString && args[6].String() != "" {
+		opts.MatchRules, err = engine.ParseMatchRules([]byte(args[6].String()))
+		if err != nil {
+			errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+			return string(errJSON)
+		}
+	}
+
 	mapped := schema.NormalizeSatellite(records, systemName, columnMapJSON)
-	result := engine.JoinAgainstSoT(globalSoTIndex, mapped, systemName)
+	result := engine.JoinAgainstSoT(globalSoTIndex, mapped, systemName, opts)
+
+	resultJSON, _ := json.Marshal(result)
+	return string(resultJSON)
+}
+
+// conflictChecksArg parses the optional JSON array of ConflictCheck at args[i]. A
+// missing or empty argument gives nil, which uses the default checks.
+func conflictChecksArg(args []js.Value, i int) ([]engine.ConflictCheck, error) {
+	if len(args) <= i || args[i].Type() != js.TypeString || args[i].String() == "" {
+		return nil, nil
+	}
+	var checks []engine.ConflictCheck
+	if err := json.Unmarshal([]byte(args[i].String()), &checks); err != nil {
+		return nil, fmt.Errorf("invalid conflict checks JSON: %w", err)
+	}
+	if err := engine.ValidateConflictChecks(checks); err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+// resolveCandidate handles the uarResolveCandidate JS function call.
+// Called when a reviewer picks one of a fuzzy or ambiguous match's candidates.
+// args[0] = string (MatchedRecord JSON, including its candidates)
+// args[1] = string (canonical ID of the chosen candidate)
+// args[2] = string (optional JSON array of ConflictCheck, as passed to uarParseSatellite)
+// PRECONDITION: loadSoTIndex() or parseSoT() must have been called first in this worker.
+// Returns: JSON string of the re-resolved MatchedRecord.
+func resolveCandidate(this js.Value, args []js.Value) interface{} {
+	if globalSoTIndex == nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "SoT index not loaded — call loadSoTIndex() first"})
+		return string(errJSON)
+	}
+
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "resolveCandidate requires 2 arguments: matchedRecordJSON and canonicalId"})
+		return string(errJSON)
+	}
+
+	var rec engine.MatchedRecord
+	if err := json.Unmarshal([]byte(args[0].String()), &rec); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid matched record JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	checks, err := conflictChecksArg(args, 2)
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	resolved, err := engine.ResolveCandidate(globalSoTIndex, rec, args[1].String(), checks)
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	resultJSON, _ := json.Marshal(resolved)
+	return string(resultJSON)
+}
+
+// claimOrphan handles the uarClaimOrphan JS function call.
+// Called when a reviewer claims an orphan for one of its near-miss suggestions.
+// args[0] = string (OrphanRecord JSON, including its suggestions)
+// args[1] = string (canonical ID of the chosen suggestion)
+// args[2] = string (optional JSON array of ConflictCheck, as passed to uarParseSatellite)
+// PRECONDITION: loadSoTIndex() or parseSoT() must have been called first in this worker.
+// Returns: JSON string of the resulting MatchedRecord.
+func claimOrphan(this js.Value, args []js.Value) interface{} {
+	if globalSoTIndex == nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "SoT index not loaded — call loadSoTIndex() first"})
+		return string(errJSON)
+	}
+
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "claimOrphan requires 2 arguments: orphanRecordJSON and canonicalId"})
+		return string(errJSON)
+	}
+
+	var orphan engine.OrphanRecord
+	if err := json.Unmarshal([]byte(args[0].String()), &orphan); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid orphan record JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	checks, err := conflictChecksArg(args, 2)
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
 
+	claimed, err := engine.ClaimOrphan(globalSoTIndex, orphan, args[1].String(), checks)
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	resultJSON, _ := json.Marshal(claimed)
+	return string(resultJSON)
+}
+
+// linkTransitive handles the uarLinkTransitive JS function call.
+// Called once all satellite workers have returned their join results.
+// args[0] = string (JSON array of JoinResult, one per satellite file)
+// args[1] = string (optional JSON array of ConflictCheck, as passed to uarParseSatellite)
+// Returns: JSON string with "results" (the updated JoinResult array) and "linked"
+// (the number of orphans linked through the cross-system identity graph).
+func linkTransitive(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "linkTransitive requires 1 argument: joinResultsJSON"})
+		return string(errJSON)
+	}
+
+	var results []*engine.JoinResult
+	if err := json.Unmarshal([]byte(args[0].String()), &results); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid join results JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	checks, err := conflictChecksArg(args, 1)
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	linked := engine.LinkTransitiveIdentities(results, checks)
+
+	result := map[string]interface{}{
+		"results": results,
+		"linked":  linked,
+	}
 	resultJSON, _ := json.Marshal(result)
 	return string(resultJSON)
 }
 
+// mergeResults handles the uarMergeResults JS function call.
+// Called once all satellite workers have returned their join results. Scoring uses
+// the policy, SoD matrix, and risk config loaded in this WASM instance.
+// args[0] = string (serialized SoT index JSON)
+// args[1] = string (JSON array of JoinResult, one per satellite file)
+// args[2] = number (optional processing timestamp in Unix ms; defaults to now)
+// args[3] = string (optional JSON array of ConflictCheck, as passed to uarParseSatellite)
+// Returns: JSON string of the MasterReport.
+func mergeResults(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "mergeResults requires 2 arguments: serializedIndex and joinResultsJSON"})
+		return string(errJSON)
+	}
+
+	index, err := engine.DeserializeSoTIndex([]byte(args[0].String()))
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	var results []*engine.JoinResult
+	if err := json.Unmarshal([]byte(args[1].String()), &results); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid join results JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	processingTimestamp := time.Now().UnixMilli()
+	if len(args) >= 3 && args[2].Type() == js.TypeNumber {
+		processingTimestamp = int64(args[2].Float())
+	}
+
+	checks, err := conflictChecksArg(args, 3)
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	masterReport, err := report.MergeResults(index, results, processingTimestamp, report.MergeOptions{
+		RiskPolicy:     globalRiskPolicy,
+		Risk:           globalRiskConfig,
+		SoDMatrix:      globalSoDMatrix,
+		ConflictChecks: checks,
+	})
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	resultJSON, _ := json.Marshal(masterReport)
+	return string(resultJSON)
+}
+
+// buildCampaign handles the uarBuildCampaign JS function call.
+// Assigns a reviewer to every account in a merged report and groups them into
+// per-reviewer work packages.
+// args[0] = string (serialized SoT index JSON)
+// args[1] = string (MasterReport JSON from uarMergeResults)
+// args[2] = string (optional campaign config as JSON or YAML; empty assigns managers)
+// Returns: JSON string of the MasterReport with its campaign set.
+func buildCampaign(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "buildCampaign requires 2 arguments: serializedIndex and reportJSON"})
+		return string(errJSON)
+	}
+
+	index, err := engine.DeserializeSoTIndex([]byte(args[0].String()))
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	var masterReport report.MasterReport
+	if err := json.Unmarshal([]byte(args[1].String()), &masterReport); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	var configText string
+	if len(args) >= 3 && args[2].Type() == js.TypeString {
+		configText = args[2].String()
+	}
+	cfg, err := report.ParseCampaignConfig([]byte(configText))
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	report.BuildCampaign(&masterReport, index, *cfg)
+
+	resultJSON, _ := json.Marshal(masterReport)
+	return string(resultJSON)
+}
+
+// diffReports handles the uarDiffReports JS function call.
+// Compares the previous review cycle's report, with its campaign decisions, to the
+// current one.
+// args[0] = string (previous MasterReport JSON)
+// args[1] = string (current MasterReport JSON)
+// Returns: JSON string of the ReportDiff.
+func diffReports(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "diffReports requires 2 arguments: previousReportJSON and currentReportJSON"})
+		return string(errJSON)
+	}
+
+	var previous, current report.MasterReport
+	if err := json.Unmarshal([]byte(args[0].String()), &previous); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid previous report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+	if err := json.Unmarshal([]byte(args[1].String()), &current); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid current report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	resultJSON, _ := json.Marshal(report.Diff(&previous, &current))
+	return string(resultJSON)
+}
+
+// carryForward handles the uarCarryForward JS function call.
+// Copies the previous cycle's decisions onto unchanged accounts in the current
+// report's campaign and marks changed accounts for re-review.
+// args[0] = string (previous MasterReport JSON, with its campaign decisions)
+// args[1] = string (current MasterReport JSON from uarBuildCampaign)
+// Returns: JSON string of the current MasterReport with the decisions applied.
+func carryForward(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "carryForward requires 2 arguments: previousReportJSON and currentReportJSON"})
+		return string(errJSON)
+	}
+
+	var previous, current report.MasterReport
+	if err := json.Unmarshal([]byte(args[0].String()), &previous); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid previous report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+	if err := json.Unmarshal([]byte(args[1].String()), &current); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid current report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	if _, err := report.CarryForward(&previous, &current); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+
+	resultJSON, _ := json.Marshal(current)
+	return string(resultJSON)
+}
+
+// verifyRemediation handles the uarVerifyRemediation JS function call.
+// Checks that access revoked in the previous cycle is gone from the current exports.
+// args[0] = string (previous MasterReport JSON, with its campaign decisions)
+// args[1] = string (current MasterReport JSON)
+// args[2] = string (optional earlier RemediationReport JSON, to detect re-grants)
+// Returns: JSON string of the RemediationReport.
+func verifyRemediation(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "verifyRemediation requires 2 arguments: previousReportJSON and currentReportJSON"})
+		return string(errJSON)
+	}
+
+	var previous, current report.MasterReport
+	if err := json.Unmarshal([]byte(args[0].String()), &previous); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid previous report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+	if err := json.Unmarshal([]byte(args[1].String()), &current); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid current report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	var prior *report.RemediationReport
+	if len(args) >= 3 && args[2].Type() == js.TypeString && args[2].String() != "" {
+		prior = &report.RemediationReport{}
+		if err := json.Unmarshal([]byte(args[2].String()), prior); err != nil {
+			errJSON, _ := json.Marshal(map[string]string{"error": "invalid remediation report JSON: " + err.Error()})
+			return string(errJSON)
+		}
+	}
+
+	resultJSON, _ := json.Marshal(report.VerifyRemediation(&previous, &current, prior))
+	return string(resultJSON)
+}
+
+// exportReport handles the uarExportReport JS function call.
+// args[0] = string (MasterReport JSON)
+// args[1] = string ("xlsx", "csv", or "jsonl")
+// args[2] = string (optional table name for csv and jsonl; defaults to "All Entries")
+// Returns: Uint8Array of the XLSX workbook, or the CSV or JSON Lines text.
+func exportReport(this js.Value, args []js.Value) interface{} {
+	if len(args) < 2 {
+		errJSON, _ := json.Marshal(map[string]string{"error": "exportReport requires 2 arguments: reportJSON and format"})
+		return string(errJSON)
+	}
+
+	var masterReport report.MasterReport
+	if err := json.Unmarshal([]byte(args[0].String()), &masterReport); err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "invalid report JSON: " + err.Error()})
+		return string(errJSON)
+	}
+
+	format := args[1].String()
+	if format == "xlsx" {
+		var buf bytes.Buffer
+		if err := export.WriteXLSX(&buf, &masterReport); err != nil {
+			errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+			return string(errJSON)
+		}
+		out := js.Global().Get("Uint8Array").New(buf.Len())
+		js.CopyBytesToJS(out, buf.Bytes())
+		return out
+	}
+
+	tableName := export.TableEntries
+	if len(args) >= 3 && args[2].Type() == js.TypeString && args[2].String() != "" {
+		tableName = args[2].String()
+	}
+	var table *export.Table
+	for _, t := range export.Tables(&masterReport) {
+		if t.Name == tableName {
+			table = &t
+			break
+		}
+	}
+	if table == nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": "unknown table: " + tableName})
+		return string(errJSON)
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "csv":
+		err = export.WriteCSV(&buf, *table)
+	case "jsonl":
+		err = export.WriteJSONL(&buf, *table)
+	default:
+		errJSON, _ := json.Marshal(map[string]string{"error": "unknown export format: " + format})
+		return string(errJSON)
+	}
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+	return buf.String()
+}
+
+// defaultRiskPolicy handles the uarDefaultRiskPolicy JS function call.
+// Returns: the built-in risk policy as YAML text, as a starting point for editing.
+func defaultRiskPolicy(this js.Value, args []js.Value) interface{} {
+	return engine.DefaultRiskPolicyText
+}
+
+// loadRiskPolicy handles the uarLoadRiskPolicy JS function call.
+// args[0] = string (risk policy as JSON or YAML; empty restores the default policy)
+// Returns: JSON string of the parsed policy, or an error if the policy is invalid.
+func loadRiskPolicy(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 || args[0].String() == "" {
+		globalRiskPolicy = nil
+		resultJSON, _ := json.Marshal(engine.DefaultRiskPolicy())
+		return string(resultJSON)
+	}
+
+	policy, err := engine.ParseRiskPolicy([]byte(args[0].String()))
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+	globalRiskPolicy = policy
+
+	resultJSON, _ := json.Marshal(policy)
+	return string(resultJSON)
+}
+
+// loadSoDMatrix handles the uarLoadSoDMatrix JS function call.
+// args[0] = string (SoD rule matrix as JSON or YAML; empty disables SoD checks)
+// Returns: JSON string of the parsed matrix, or an error if a rule is invalid.
+func loadSoDMatrix(this js.Value, args []js.Value) interface{} {
+	if len(args) < 1 || args[0].String() == "" {
+		globalSoDMatrix = nil
+		return `{"rules":[]}`
+	}
+
+	matrix, err := engine.ParseSoDMatrix([]byte(args[0].String()))
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+	globalSoDMatrix = matrix
+
+	resultJSON, _ := json.Marshal(matrix)
+	return string(resultJSON)
+}
+
+// loadRiskConfig handles the uarLoadRiskConfig JS function call.
+// Setting peerOutliers in the config enables peer-group outlier findings in
+// uarMergeResults.
+// args[0] = string (risk config as JSON or YAML; empty restores the defaults)
+// Returns: JSON string of the parsed config, or an error if the config is invalid.
+func loadRiskConfig(this js.Value, args []js.Value) interface{} {
+	text := ""
+	if len(args) >= 1 {
+		text = args[0].String()
+	}
+
+	cfg, err := engine.ParseRiskConfig([]byte(text))
+	if err != nil {
+		errJSON, _ := json.Marshal(map[string]string{"error": err.Error()})
+		return string(errJSON)
+	}
+	globalRiskConfig = *cfg
+
+	resultJSON, _ := json.Marshal(cfg)
+	return string(resultJSON)
+}
+
 func main() {
 	js.Global().Set("uarParseSoT", js.FuncOf(parseSoT))
 	js.Global().Set("uarLoadSoTIndex", js.FuncOf(loadSoTIndex))
 	js.Global().Set("uarParseSatellite", js.FuncOf(parseSatellite))
+	js.Global().Set("uarResolveCandidate", js.FuncOf(resolveCandidate))
+	js.Global().Set("uarLinkTransitive", js.FuncOf(linkTransitive))
+	js.Global().Set("uarClaimOrphan", js.FuncOf(claimOrphan))
+	js.Global().Set("uarMergeResults", js.FuncOf(mergeResults))
+	js.Global().Set("uarBuildCampaign", js.FuncOf(buildCampaign))
+	js.Global().Set("uarDiffReports", js.FuncOf(diffReports))
+	js.Global().Set("uarCarryForward", js.FuncOf(carryForward))
+	js.Global().Set("uarVerifyRemediation", js.FuncOf(verifyRemediation))
+	js.Global().Set("uarExportReport", js.FuncOf(exportReport))
+	js.Global().Set("uarDefaultRiskPolicy", js.FuncOf(defaultRiskPolicy))
+	js.Global().Set("uarLoadRiskPolicy", js.FuncOf(loadRiskPolicy))
+	js.Global().Set("uarLoadSoDMatrix", js.FuncOf(loadSoDMatrix))
+	js.Global().Set("uarLoadRiskConfig", js.FuncOf(loadRiskConfig))
 
 	// Block forever — WASM module stays alive
 	select {}
@@ -2,4 +2,7 @@ module uar
 
         go 1.23
 
-        require golang.org/x/text v0.21.0
+        require (
+        	golang.org/x/text v0.21.0
+        	gopkg.in/yaml.v3 v3.0.1
+        )
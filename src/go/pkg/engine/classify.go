@@ -0,0 +1,239 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"uar/pkg/schema"
+)
+
+// AccountClass is the kind of principal behind a satellite account.
+type AccountClass string
+
+const (
+	AccountHuman      AccountClass = "human"
+	AccountService    AccountClass = "service"
+	AccountShared     AccountClass = "shared"
+	AccountBreakGlass AccountClass = "break_glass"
+)
+
+// ClassifierConfig configures AccountClassifier. Patterns are case-insensitive regular
+// expressions tested against the user ID, the email local part, and the display name.
+type ClassifierConfig struct {
+	BreakGlassPatterns []string `json:"breakGlassPatterns"`
+	ServicePatterns    []string `json:"servicePatterns"`
+	SharedPatterns     []string `json:"sharedPatterns"`
+	// AccountTypes maps lowercase values of the satellite account-type column to a class.
+	AccountTypes map[string]AccountClass `json:"accountTypes"`
+	// FlagNonPersonNames classifies accounts whose display name does not look like a
+	// person's name (fewer than two alphabetic words, or any digits) as service accounts.
+	// It is off by default: many systems put a user ID such as "jdoe" in the display
+	// name, and those accounts must stay orphans for identity-graph linking and
+	// near-miss suggestions.
+	FlagNonPersonNames bool `json:"flagNonPersonNames"`
+}
+
+// DefaultClassifierConfig is used when no classifier configuration is supplied.
+var DefaultClassifierConfig = ClassifierConfig{
+	BreakGlassPatterns: []string{
+		`break[-_. ]?glass`,
+		`fire[-_. ]?call`,
+		`(^|[-_. ])emergency([-_. ]|$)`,
+	},
+	ServicePatterns: []string{
+		`^svc[-_.]`,
+		`[-_.]svc$`,
+		`(^|[-_. ])(service|bot|daemon|automation|deploy|terraform|jenkins|ansible|monitoring|backup|batch|cron|scheduler|integration|sync|api)([-_. ]|$)`,
+	},
+	SharedPatterns: []string{
+		`^(admin|administrator|root|support|helpdesk|info|team|shared|generic|test|testuser|training|guest|noreply|no-reply|sales|ops)$`,
+		`(^|[-_. ])shared([-_. ]|$)`,
+	},
+	AccountTypes: map[string]AccountClass{
+		"human":            AccountHuman,
+		"user":             AccountHuman,
+		"person":           AccountHuman,
+		"member":           AccountHuman,
+		"service":          AccountService,
+		"service account":  AccountService,
+		"service_account":  AccountService,
+		"serviceprincipal": AccountService,
+		"application":      AccountService,
+		"system":           AccountService,
+		"machine":          AccountService,
+		"shared":           AccountShared,
+		"generic":          AccountShared,
+		"break glass":      AccountBreakGlass,
+		"break_glass":      AccountBreakGlass,
+		"breakglass":       AccountBreakGlass,
+		"emergency":        AccountBreakGlass,
+	},
+}
+
+// AccountClassification is the classifier's verdict for one account.
+type AccountClassification struct {
+	Class  AccountClass `json:"class"`
+	Reason string       `json:"reason"`
+}
+
+// AccountClassifier marks satellite accounts as human, service, shared/generic, or break-glass.
+type AccountClassifier struct {
+	breakGlass         []*regexp.Regexp
+	service            []*regexp.Regexp
+	shared             []*regexp.Regexp
+	accountTypes       map[string]AccountClass
+	flagNonPersonNames bool
+}
+
+// defaultClassifier is built from DefaultClassifierConfig and used when JoinOptions
+// carries no classifier.
+var defaultClassifier = mustNewAccountClassifier(DefaultClassifierConfig)
+
+// NewAccountClassifier compiles a classifier configuration.
+func NewAccountClassifier(cfg ClassifierConfig) (*AccountClassifier, error) {
+	c := &AccountClassifier{
+		accountTypes:       make(map[string]AccountClass, len(cfg.AccountTypes)),
+		flagNonPersonNames: cfg.FlagNonPersonNames,
+	}
+
+	var err error
+	if c.breakGlass, err = compilePatterns(cfg.BreakGlassPatterns); err != nil {
+		return nil, fmt.Errorf("break-glass pattern: %w", err)
+	}
+	if c.service, err = compilePatterns(cfg.ServicePatterns); err != nil {
+		return nil, fmt.Errorf("service pattern: %w", err)
+	}
+	if c.shared, err = compilePatterns(cfg.SharedPatterns); err != nil {
+		return nil, fmt.Errorf("shared pattern: %w", err)
+	}
+
+	for value, class := range cfg.AccountTypes {
+		switch class {
+		case AccountHuman, AccountService, AccountShared, AccountBreakGlass:
+		default:
+			return nil, fmt.Errorf("account type %q: unknown class %q", value, class)
+		}
+		c.accountTypes[strings.ToLower(strings.TrimSpace(value))] = class
+	}
+
+	return c, nil
+}
+
+// mustNewAccountClassifier is NewAccountClassifier for configurations known to be valid.
+func mustNewAccountClassifier(cfg ClassifierConfig) *AccountClassifier {
+	c, err := NewAccountClassifier(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Classify determines the account class. Checks run in order:
+//  1. Account-type column, when its value is in the configured table
+//  2. Break-glass patterns
+//  3. Service patterns
+//  4. Shared/generic patterns
+//  5. Display name that does not look like a person (if enabled)
+//
+// Anything else is human.
+func (c *AccountClassifier) Classify(sat schema.SatelliteRecord) AccountClassification {
+	if c == nil {
+		c = defaultClassifier
+	}
+
+	if sat.AccountType != "" {
+		if class, ok := c.accountTypes[strings.ToLower(sat.AccountType)]; ok {
+			return AccountClassification{Class: class, Reason: "account type " + sat.AccountType}
+		}
+	}
+
+	fields := accountNameFields(sat)
+	checks := []struct {
+		class    AccountClass
+		patterns []*regexp.Regexp
+	}{
+		{AccountBreakGlass, c.breakGlass},
+		{AccountService, c.service},
+		{AccountShared, c.shared},
+	}
+	for _, check := range checks {
+		for _, re := range check.patterns {
+			for _, f := range fields {
+				if re.MatchString(f) {
+					return AccountClassification{
+						Class:  check.class,
+						Reason: fmt.Sprintf("%q matches pattern %s", f, re.String()),
+					}
+				}
+			}
+		}
+	}
+
+	if c.flagNonPersonNames && sat.DisplayName != "" && !isPersonLikeName(sat.DisplayName) {
+		return AccountClassification{
+			Class:  AccountService,
+			Reason: fmt.Sprintf("display name %q is not person-like", sat.DisplayName),
+		}
+	}
+
+	return AccountClassification{Class: AccountHuman}
+}
+
+// accountNameFields returns the lowercase identifiers a classifier pattern is tested against.
+func accountNameFields(sat schema.SatelliteRecord) []string {
+	var fields []string
+	if uid := strings.ToLower(strings.TrimSpace(sat.UserId)); uid != "" {
+		fields = append(fields, uid)
+	}
+	if email := strings.ToLower(strings.TrimSpace(sat.Email)); email != "" {
+		if at := strings.IndexByte(email, '@'); at > 0 {
+			email = email[:at]
+		}
+		fields = append(fields, email)
+	}
+	if name := strings.ToLower(strings.TrimSpace(sat.DisplayName)); name != "" {
+		fields = append(fields, name)
+	}
+	return fields
+}
+
+// isPersonLikeName reports whether a display name has at least two alphabetic words
+// and no digits, after normalization.
+func isPersonLikeName(displayName string) bool {
+	normalized := schema.NormalizeName(displayName)
+	for _, r := range normalized {
+		if unicode.IsDigit(r) {
+			return false
+		}
+	}
+
+	words := 0
+	for _, w := range strings.Fields(normalized) {
+		alpha := true
+		for _, r := range w {
+			if !unicode.IsLetter(r) && r != '\'' && r != '-' && r != '.' {
+				alpha = false
+				break
+			}
+		}
+		if alpha {
+			words++
+		}
+	}
+	return words >= 2
+}
+
+// compilePatterns compiles case-insensitive regular expressions.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
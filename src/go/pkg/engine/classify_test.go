@@ -0,0 +1,63 @@
+package engine
+
+import (
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestAccountClassifierClassify(t *testing.T) {
+	flagging := DefaultClassifierConfig
+	flagging.FlagNonPersonNames = true
+
+	tests := []struct {
+		name string
+		cfg  *ClassifierConfig // nil uses the default classifier
+		sat  schema.SatelliteRecord
+		want AccountClass
+	}{
+		{"person", nil, schema.SatelliteRecord{UserId: "jdoe", Email: "jane.doe@acme.com", DisplayName: "Jane Doe"}, AccountHuman},
+		{"user ID as display name stays human", nil, schema.SatelliteRecord{UserId: "jdoe", DisplayName: "jdoe"}, AccountHuman},
+		{"account type column wins", nil, schema.SatelliteRecord{UserId: "svc-build", AccountType: "User"}, AccountHuman},
+		{"service account type", nil, schema.SatelliteRecord{UserId: "jdoe", AccountType: "Service Account"}, AccountService},
+		{"service prefix", nil, schema.SatelliteRecord{UserId: "svc_backup"}, AccountService},
+		{"bot in email local part", nil, schema.SatelliteRecord{Email: "deploy-bot@acme.com"}, AccountService},
+		{"break-glass before service", nil, schema.SatelliteRecord{UserId: "breakglass-svc"}, AccountBreakGlass},
+		{"shared mailbox", nil, schema.SatelliteRecord{Email: "helpdesk@acme.com"}, AccountShared},
+		{"name words are not substrings", nil, schema.SatelliteRecord{DisplayName: "Sally Apiary"}, AccountHuman},
+		{"non-person name when enabled", &flagging, schema.SatelliteRecord{UserId: "x1", DisplayName: "Printer 4"}, AccountService},
+		{"one-word name when enabled", &flagging, schema.SatelliteRecord{UserId: "jdoe", DisplayName: "jdoe"}, AccountService},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c *AccountClassifier
+			if tt.cfg != nil {
+				var err error
+				if c, err = NewAccountClassifier(*tt.cfg); err != nil {
+					t.Fatalf("NewAccountClassifier() error = %v", err)
+				}
+			}
+			got := c.Classify(tt.sat)
+			if got.Class != tt.want {
+				t.Errorf("Classify(%+v) = %s (%s), want %s", tt.sat, got.Class, got.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewAccountClassifierErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ClassifierConfig
+	}{
+		{"bad service pattern", ClassifierConfig{ServicePatterns: []string{"("}}},
+		{"unknown account class", ClassifierConfig{AccountTypes: map[string]AccountClass{"robot": "robot"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAccountClassifier(tt.cfg); err == nil {
+				t.Error("NewAccountClassifier() error = nil, want an error")
+			}
+		})
+	}
+}
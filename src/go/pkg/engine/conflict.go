@@ -1,43 +1,219 @@
 package engine
 
 import (
+	"fmt"
 	"strings"
 
 	"uar/pkg/schema"
 )
 
+// ConflictMode controls how a field's SoT and satellite values are compared.
+type ConflictMode string
+
+const (
+	ConflictExact           ConflictMode = "exact"
+	ConflictCaseInsensitive ConflictMode = "case_insensitive"
+	ConflictNormalizedName  ConflictMode = "normalized_name"
+	ConflictFuzzy           ConflictMode = "fuzzy"
+	// ConflictStatus compares employment status against account status by whether
+	// each side means the person or account is current (e.g. "active" vs "disabled").
+	ConflictStatus ConflictMode = "status"
+)
+
+// ConflictPolicy is how a detected conflict is resolved.
+type ConflictPolicy string
+
+const (
+	ResolveSoTWins       ConflictPolicy = "sot_wins"
+	ResolveSatelliteWins ConflictPolicy = "satellite_wins"
+	ResolveFlagOnly      ConflictPolicy = "flag_only"
+)
+
+// ConflictCheck configures conflict detection for one field present on both sides.
+// Supported fields: displayName, department, manager, email, title, status.
+type ConflictCheck struct {
+	Field     string       `json:"field"`
+	Mode      ConflictMode `json:"mode"`
+	Threshold float64      `json:"threshold,omitempty"` // fuzzy mode: minimum similarity to agree
+	// Severity is the level of the finding a disagreement adds to the entry's risk
+	// (see ConflictFindings); INFO or empty flags the conflict without scoring it.
+	Severity   RiskLevel      `json:"severity"`
+	Resolution ConflictPolicy `json:"resolution"`
+}
+
+// DefaultConflictChecks is used when no conflict checks are configured.
+// The displayName check matches the original case-insensitive, sot_wins behavior.
+var DefaultConflictChecks = []ConflictCheck{
+	{Field: "displayName", Mode: ConflictCaseInsensitive, Severity: RiskLow, Resolution: ResolveSoTWins},
+	{Field: "email", Mode: ConflictCaseInsensitive, Severity: RiskMedium, Resolution: ResolveSoTWins},
+	{Field: "department", Mode: ConflictCaseInsensitive, Severity: RiskLow, Resolution: ResolveSoTWins},
+	{Field: "manager", Mode: ConflictNormalizedName, Severity: RiskLow, Resolution: ResolveSoTWins},
+	{Field: "title", Mode: ConflictFuzzy, Threshold: fuzzyMatchThreshold, Severity: RiskInfo, Resolution: ResolveSoTWins},
+	{Field: "status", Mode: ConflictStatus, Severity: RiskMedium, Resolution: ResolveFlagOnly},
+}
+
 // FieldConflict represents a disagreement between SoT and satellite data for a field.
-// Resolution is always "sot_wins" — the SoT value takes precedence.
 type FieldConflict struct {
-	Field          string `json:"field"`
-	SoTValue       string `json:"sotValue"`
-	SatelliteValue string `json:"satelliteValue"`
-	Resolution     string `json:"resolution"` // always "sot_wins"
+	Field          string         `json:"field"`
+	SoTValue       string         `json:"sotValue"`
+	SatelliteValue string         `json:"satelliteValue"`
+	Mode           ConflictMode   `json:"mode"`
+	Severity       RiskLevel      `json:"severity"`
+	Resolution     ConflictPolicy `json:"resolution"`
+	// ResolvedValue is the value that wins under Resolution; empty for flag_only.
+	ResolvedValue string `json:"resolvedValue,omitempty"`
+}
+
+// ValidateConflictChecks reports the first unsupported field, mode, severity, or policy.
+func ValidateConflictChecks(checks []ConflictCheck) error {
+	for i, c := range checks {
+		if _, ok := conflictFieldValues(c.Field, &schema.SoTRecord{}, schema.SatelliteRecord{}); !ok {
+			return fmt.Errorf("conflict check %d: unsupported field %q", i, c.Field)
+		}
+		switch c.Mode {
+		case ConflictExact, ConflictCaseInsensitive, ConflictNormalizedName, ConflictStatus:
+		case ConflictFuzzy:
+			if c.Threshold <= 0 || c.Threshold > 1 {
+				return fmt.Errorf("conflict check %d: fuzzy threshold must be in (0, 1]", i)
+			}
+		default:
+			return fmt.Errorf("conflict check %d: unknown mode %q", i, c.Mode)
+		}
+		if _, ok := riskLevelRank[c.Severity]; !ok && c.Severity != "" {
+			return fmt.Errorf("conflict check %d: unknown severity %q", i, c.Severity)
+		}
+		switch c.Resolution {
+		case ResolveSoTWins, ResolveSatelliteWins, ResolveFlagOnly:
+		default:
+			return fmt.Errorf("conflict check %d: unknown resolution %q", i, c.Resolution)
+		}
+	}
+	return nil
 }
 
-// DetectConflicts compares shared fields between a SoT record and a satellite record.
-// If values differ (case-insensitive), a conflict entry is created.
-// Compared fields: displayName, department.
+// DetectConflicts compares shared fields between a SoT record and a satellite record
+// using DefaultConflictChecks.
 func DetectConflicts(sot *schema.SoTRecord, sat schema.SatelliteRecord) []FieldConflict {
+	return DetectConflictsWith(sot, sat, DefaultConflictChecks)
+}
+
+// DetectConflictsWith runs the given conflict checks. A field is only compared when
+// both sides carry a value; a nil checks slice falls back to DefaultConflictChecks.
+func DetectConflictsWith(sot *schema.SoTRecord, sat schema.SatelliteRecord, checks []ConflictCheck) []FieldConflict {
+	if checks == nil {
+		checks = DefaultConflictChecks
+	}
+
 	var conflicts []FieldConflict
 
-	// Compare displayName
-	if sat.DisplayName != "" && sot.DisplayName != "" {
-		if !strings.EqualFold(sot.DisplayName, sat.DisplayName) {
-			conflicts = append(conflicts, FieldConflict{
-				Field:          "displayName",
-				SoTValue:       sot.DisplayName,
-				SatelliteValue: sat.DisplayName,
-				Resolution:     "sot_wins",
-			})
+	for _, check := range checks {
+		values, ok := conflictFieldValues(check.Field, sot, sat)
+		if !ok || values[0] == "" || values[1] == "" {
+			continue
 		}
-	}
+		sotValue, satValue := values[0], values[1]
 
-	// Compare department — satellite records may not have department,
-	// but if they do and it differs, flag it.
-	// We check using a mapped field on the satellite side. Since SatelliteRecord
-	// doesn't have a Department field, we skip this unless we extend the struct.
-	// For now, displayName is the primary conflict detection field.
+		if valuesAgree(check, sotValue, satValue) {
+			continue
+		}
+
+		conflict := FieldConflict{
+			Field:          check.Field,
+			SoTValue:       sotValue,
+			SatelliteValue: satValue,
+			Mode:           check.Mode,
+			Severity:       check.Severity,
+			Resolution:     check.Resolution,
+		}
+		switch check.Resolution {
+		case ResolveSoTWins:
+			conflict.ResolvedValue = sotValue
+		case ResolveSatelliteWins:
+			conflict.ResolvedValue = satValue
+		}
+		conflicts = append(conflicts, conflict)
+	}
 
 	return conflicts
 }
+
+// FindingFieldConflict is the finding code ConflictFindings reports.
+const FindingFieldConflict = "field_conflict"
+
+// conflictSeverityScores are the finding scores for each conflict severity, at the
+// bottom of each level's DefaultLevelThresholds band.
+var conflictSeverityScores = map[RiskLevel]int{
+	RiskCritical: 90,
+	RiskHigh:     70,
+	RiskMedium:   40,
+	RiskLow:      10,
+}
+
+// ConflictFindings turns field conflicts into risk findings at each check's Severity,
+// so a disagreement the configuration marks as risky raises the entry's score.
+// INFO conflicts are reported on the entry but produce no finding.
+func ConflictFindings(conflicts []FieldConflict) []RiskFinding {
+	var findings []RiskFinding
+	for _, c := range conflicts {
+		score, ok := conflictSeverityScores[c.Severity]
+		if !ok {
+			continue
+		}
+		findings = append(findings, RiskFinding{
+			Code:        FindingFieldConflict,
+			Level:       c.Severity,
+			Score:       score,
+			Description: fmt.Sprintf("%s differs from the SoT: %q vs %q", c.Field, c.SatelliteValue, c.SoTValue),
+		})
+	}
+	return findings
+}
+
+// conflictFieldValues returns the SoT and satellite values for a field name.
+// The status field pairs SoT employment status with satellite account status.
+func conflictFieldValues(field string, sot *schema.SoTRecord, sat schema.SatelliteRecord) ([2]string, bool) {
+	switch field {
+	case "displayName":
+		return [2]string{sot.DisplayName, sat.DisplayName}, true
+	case "email":
+		return [2]string{sot.Email, sat.Email}, true
+	case "department":
+		return [2]string{sot.Department, sat.Department}, true
+	case "manager":
+		return [2]string{sot.Manager, sat.Manager}, true
+	case "title":
+		return [2]string{sot.Title, sat.Title}, true
+	case "status":
+		return [2]string{sot.EmploymentStatus, sat.AccountStatus}, true
+	}
+	return [2]string{}, false
+}
+
+// valuesAgree compares two non-empty values under a check's mode.
+func valuesAgree(check ConflictCheck, sotValue, satValue string) bool {
+	switch check.Mode {
+	case ConflictExact:
+		return sotValue == satValue
+	case ConflictNormalizedName:
+		return schema.NormalizeName(sotValue) == schema.NormalizeName(satValue)
+	case ConflictFuzzy:
+		a := strings.ToLower(strings.TrimSpace(sotValue))
+		b := strings.ToLower(strings.TrimSpace(satValue))
+		return similarity(a, b) >= check.Threshold
+	case ConflictStatus:
+		return IsCurrentStatus(sotValue) == IsCurrentStatus(satValue)
+	default:
+		return strings.EqualFold(strings.TrimSpace(sotValue), strings.TrimSpace(satValue))
+	}
+}
+
+// IsCurrentStatus reports whether an employment or account status means the person
+// or account is current. Anything not recognized as ended or disabled counts as current.
+func IsCurrentStatus(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "terminated", "inactive", "disabled", "deactivated", "deprovisioned",
+		"suspended", "locked", "false", "no", "0":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestDetectConflictsWith(t *testing.T) {
+	sot := &schema.SoTRecord{
+		DisplayName:      "José García",
+		Email:            "jgarcia@acme.com",
+		Department:       "Finance",
+		Manager:          "Pat Lee",
+		Title:            "Senior Accountant",
+		EmploymentStatus: "Terminated",
+	}
+
+	tests := []struct {
+		name      string
+		sat       schema.SatelliteRecord
+		checks    []ConflictCheck
+		want      []string // conflicting fields, in check order
+		wantValue string   // ResolvedValue of the first conflict
+	}{
+		{
+			name:      "nil checks use the defaults",
+			sat:       schema.SatelliteRecord{DisplayName: "jose garcia", Department: "Sales", AccountStatus: "active"},
+			want:      []string{"displayName", "department", "status"},
+			wantValue: "José García",
+		},
+		{
+			name:   "empty checks detect nothing",
+			sat:    schema.SatelliteRecord{Department: "Sales"},
+			checks: []ConflictCheck{},
+		},
+		{
+			name:      "exact mode is case-sensitive",
+			sat:       schema.SatelliteRecord{Department: "finance"},
+			checks:    []ConflictCheck{{Field: "department", Mode: ConflictExact, Resolution: ResolveSatelliteWins}},
+			want:      []string{"department"},
+			wantValue: "finance",
+		},
+		{
+			name:   "case-insensitive mode ignores case",
+			sat:    schema.SatelliteRecord{Department: "FINANCE"},
+			checks: []ConflictCheck{{Field: "department", Mode: ConflictCaseInsensitive, Resolution: ResolveSoTWins}},
+		},
+		{
+			name:   "normalized name mode ignores accents",
+			sat:    schema.SatelliteRecord{DisplayName: "Jose Garcia"},
+			checks: []ConflictCheck{{Field: "displayName", Mode: ConflictNormalizedName, Resolution: ResolveSoTWins}},
+		},
+		{
+			name:   "fuzzy mode tolerates small differences",
+			sat:    schema.SatelliteRecord{Title: "Senior Acountant"},
+			checks: []ConflictCheck{{Field: "title", Mode: ConflictFuzzy, Threshold: 0.85, Resolution: ResolveSoTWins}},
+		},
+		{
+			name:   "fuzzy mode flags different titles",
+			sat:    schema.SatelliteRecord{Title: "Engineer"},
+			checks: []ConflictCheck{{Field: "title", Mode: ConflictFuzzy, Threshold: 0.85, Resolution: ResolveFlagOnly}},
+			want:   []string{"title"},
+		},
+		{
+			name:   "status mode compares current against ended",
+			sat:    schema.SatelliteRecord{AccountStatus: "disabled"},
+			checks: []ConflictCheck{{Field: "status", Mode: ConflictStatus, Resolution: ResolveFlagOnly}},
+		},
+		{
+			name:   "empty satellite value is not a conflict",
+			sat:    schema.SatelliteRecord{Manager: ""},
+			checks: []ConflictCheck{{Field: "manager", Mode: ConflictExact, Resolution: ResolveSoTWins}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts := DetectConflictsWith(sot, tt.sat, tt.checks)
+			var fields []string
+			for _, c := range conflicts {
+				fields = append(fields, c.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Fatalf("DetectConflictsWith() fields = %v, want %v", fields, tt.want)
+			}
+			if len(conflicts) > 0 && conflicts[0].ResolvedValue != tt.wantValue {
+				t.Errorf("ResolvedValue = %q, want %q", conflicts[0].ResolvedValue, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestValidateConflictChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   ConflictCheck
+		wantErr bool
+	}{
+		{name: "valid", check: ConflictCheck{Field: "email", Mode: ConflictExact, Severity: RiskHigh, Resolution: ResolveSoTWins}},
+		{name: "severity may be omitted", check: ConflictCheck{Field: "email", Mode: ConflictExact, Resolution: ResolveFlagOnly}},
+		{name: "unknown field", check: ConflictCheck{Field: "phone", Mode: ConflictExact, Resolution: ResolveSoTWins}, wantErr: true},
+		{name: "unknown mode", check: ConflictCheck{Field: "email", Mode: "soundex", Resolution: ResolveSoTWins}, wantErr: true},
+		{name: "fuzzy without threshold", check: ConflictCheck{Field: "title", Mode: ConflictFuzzy, Resolution: ResolveSoTWins}, wantErr: true},
+		{name: "fuzzy threshold above 1", check: ConflictCheck{Field: "title", Mode: ConflictFuzzy, Threshold: 1.5, Resolution: ResolveSoTWins}, wantErr: true},
+		{name: "unknown severity", check: ConflictCheck{Field: "email", Mode: ConflictExact, Severity: "SEVERE", Resolution: ResolveSoTWins}, wantErr: true},
+		{name: "unknown resolution", check: ConflictCheck{Field: "email", Mode: ConflictExact, Resolution: "merge"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConflictChecks([]ConflictCheck{tt.check})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConflictChecks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := ValidateConflictChecks(DefaultConflictChecks); err != nil {
+		t.Errorf("ValidateConflictChecks(DefaultConflictChecks) = %v, want nil", err)
+	}
+}
+
+func TestConflictFindings(t *testing.T) {
+	conflicts := []FieldConflict{
+		{Field: "email", Severity: RiskHigh},
+		{Field: "title", Severity: RiskInfo},
+		{Field: "department", Severity: ""},
+		{Field: "displayName", Severity: RiskLow},
+	}
+
+	findings := ConflictFindings(conflicts)
+
+	if len(findings) != 2 {
+		t.Fatalf("ConflictFindings() = %d findings, want 2 (INFO and empty severity score nothing)", len(findings))
+	}
+	if findings[0].Code != FindingFieldConflict || findings[0].Level != RiskHigh || findings[0].Score != 70 {
+		t.Errorf("findings[0] = %+v, want a HIGH field_conflict scoring 70", findings[0])
+	}
+	if findings[1].Level != RiskLow || findings[1].Score != 10 {
+		t.Errorf("findings[1] = %+v, want a LOW finding scoring 10", findings[1])
+	}
+}
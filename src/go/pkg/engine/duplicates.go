@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"strings"
+
+	"uar/pkg/schema"
+)
+
+// DuplicateKind classifies a duplicate identity finding.
+type DuplicateKind string
+
+const (
+	// DuplicateSoTKey is two or more SoT rows sharing an email or employee ID.
+	// BuildSoTIndex indexes only the first, so the others are unreachable by key.
+	DuplicateSoTKey DuplicateKind = "sot_key_collision"
+	// DuplicateSatelliteAccounts is two or more distinct accounts in the same
+	// satellite system that resolve to the same SoT person.
+	DuplicateSatelliteAccounts DuplicateKind = "satellite_multiple_accounts"
+	// DuplicateRehire is a terminated and a non-terminated SoT row for the same human,
+	// sharing an email or employee ID.
+	DuplicateRehire DuplicateKind = "rehire"
+	// DuplicatePossibleRehire is a terminated and a non-terminated SoT row sharing only
+	// a name. They may be a rehire or two people with the same name.
+	DuplicatePossibleRehire DuplicateKind = "possible_rehire"
+)
+
+// DuplicateFinding describes a set of records that appear to be the same identity.
+type DuplicateFinding struct {
+	Kind             DuplicateKind            `json:"kind"`
+	Key              string                   `json:"key"` // email | employeeId | name | canonicalId
+	Value            string                   `json:"value"`
+	System           string                   `json:"system,omitempty"`
+	RiskLevel        RiskLevel                `json:"riskLevel"`
+	Description      string                   `json:"description"`
+	SoTRecords       []*schema.SoTRecord      `json:"sotRecords,omitempty"`
+	SatelliteRecords []schema.SatelliteRecord `json:"satelliteRecords,omitempty"`
+}
+
+// DetectSoTDuplicates reports SoT key collisions and rehires.
+// Records sharing an email or employee ID are a collision unless one is terminated and
+// another is not, in which case they are reported as a rehire. Records sharing only a
+// normalized name are reported as a possible rehire when their statuses differ that
+// way, at a lower risk level, since distinct people legitimately share names; other
+// name groups are not reported. Each group of records is reported once, in input order.
+func DetectSoTDuplicates(records []*schema.SoTRecord) []DuplicateFinding {
+	var findings []DuplicateFinding
+	reported := make(map[string]bool)
+
+	keyFuncs := []struct {
+		key   string
+		value func(*schema.SoTRecord) string
+	}{
+		{"email", func(r *schema.SoTRecord) string { return strings.ToLower(r.Email) }},
+		{"employeeId", func(r *schema.SoTRecord) string { return r.EmployeeID }},
+		{"name", func(r *schema.SoTRecord) string { return r.NormalizedName }},
+	}
+
+	for _, kf := range keyFuncs {
+		order, groups := groupSoTRecords(records, kf.value)
+		for _, value := range order {
+			group := groups[value]
+			if len(group) < 2 {
+				continue
+			}
+
+			groupID := sotGroupID(group)
+			if reported[groupID] {
+				continue
+			}
+
+			if isRehireGroup(group) {
+				reported[groupID] = true
+				finding := DuplicateFinding{
+					Kind:        DuplicateRehire,
+					Key:         kf.key,
+					Value:       value,
+					RiskLevel:   RiskMedium,
+					Description: "terminated and active SoT records for the same " + kf.key,
+					SoTRecords:  group,
+				}
+				if kf.key == "name" {
+					finding.Kind = DuplicatePossibleRehire
+					finding.RiskLevel = RiskLow
+					finding.Description = "terminated and active SoT records share a name; confirm whether they are the same person"
+				}
+				findings = append(findings, finding)
+				continue
+			}
+
+			if kf.key == "name" {
+				continue
+			}
+
+			reported[groupID] = true
+			findings = append(findings, DuplicateFinding{
+				Kind:        DuplicateSoTKey,
+				Key:         kf.key,
+				Value:       value,
+				RiskLevel:   RiskHigh,
+				Description: "multiple SoT records share this " + kf.key + "; only the first is matched",
+				SoTRecords:  group,
+			})
+		}
+	}
+
+	return findings
+}
+
+// DetectSatelliteDuplicates reports distinct accounts within one satellite system that
+// matched the same SoT person. Multiple rows for the same account (e.g. one per role)
+// are not duplicates. Findings are ordered by each person's first matched row.
+func DetectSatelliteDuplicates(result *JoinResult) []DuplicateFinding {
+	type accountGroup struct {
+		system   string
+		accounts map[string]bool
+		records  []schema.SatelliteRecord
+	}
+
+	var order []string
+	groups := make(map[string]*accountGroup)
+
+	for _, m := range result.Matched {
+		if m.SoT == nil || m.SoT.CanonicalID == "" {
+			continue
+		}
+		groupKey := m.Satellite.SourceFile + "\x00" + m.SoT.CanonicalID
+		g, ok := groups[groupKey]
+		if !ok {
+			g = &accountGroup{system: m.Satellite.SourceFile, accounts: make(map[string]bool)}
+			groups[groupKey] = g
+			order = append(order, groupKey)
+		}
+		g.accounts[satelliteAccountKey(m.Satellite)] = true
+		g.records = append(g.records, m.Satellite)
+	}
+
+	var findings []DuplicateFinding
+	for _, groupKey := range order {
+		g := groups[groupKey]
+		if len(g.accounts) < 2 {
+			continue
+		}
+		canonicalID := groupKey[strings.IndexByte(groupKey, 0)+1:]
+		findings = append(findings, DuplicateFinding{
+			Kind:             DuplicateSatelliteAccounts,
+			Key:              "canonicalId",
+			Value:            canonicalID,
+			System:           g.system,
+			RiskLevel:        RiskMedium,
+			Description:      "multiple accounts in " + g.system + " resolve to the same SoT person",
+			SatelliteRecords: g.records,
+		})
+	}
+
+	return findings
+}
+
+// satelliteAccountKey identifies a satellite account independent of how many rows it has.
+// Prefers email, then user ID, then display name.
+func satelliteAccountKey(sat schema.SatelliteRecord) string {
+	if sat.Email != "" {
+		return "email:" + strings.ToLower(sat.Email)
+	}
+	if sat.UserId != "" {
+		return "userId:" + strings.ToLower(sat.UserId)
+	}
+	return "name:" + strings.ToLower(sat.DisplayName)
+}
+
+// groupSoTRecords groups records by a key function, skipping empty keys.
+// Returns the keys in first-seen order alongside the groups.
+func groupSoTRecords(records []*schema.SoTRecord, keyFn func(*schema.SoTRecord) string) ([]string, map[string][]*schema.SoTRecord) {
+	var order []string
+	groups := make(map[string][]*schema.SoTRecord)
+	for _, rec := range records {
+		key := keyFn(rec)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], rec)
+	}
+	return order, groups
+}
+
+// sotGroupID builds an identity for a group of SoT records so the same set of
+// records found under several keys is only reported once.
+func sotGroupID(group []*schema.SoTRecord) string {
+	parts := make([]string, len(group))
+	for i, rec := range group {
+		parts[i] = rec.CanonicalID + "|" + rec.EmployeeID + "|" + rec.NormalizedName
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// isRehireGroup reports whether a group holds both a terminated and a non-terminated
+// record, by lifecycle state.
+func isRehireGroup(group []*schema.SoTRecord) bool {
+	terminated, current := false, false
+	for _, rec := range group {
+		if rec.LifecycleState == schema.LifecycleTerminated {
+			terminated = true
+		} else {
+			current = true
+		}
+	}
+	return terminated && current
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+// sotRecord returns a SoT record in the given lifecycle state.
+func sotRecord(email, employeeID, name string, state schema.LifecycleState) *schema.SoTRecord {
+	canonicalID := email
+	if canonicalID == "" {
+		canonicalID = employeeID
+	}
+	return &schema.SoTRecord{
+		CanonicalID:    canonicalID,
+		EmployeeID:     employeeID,
+		Email:          email,
+		DisplayName:    name,
+		NormalizedName: schema.NormalizeName(name),
+		LifecycleState: state,
+	}
+}
+
+func TestDetectSoTDuplicates(t *testing.T) {
+	active, terminated := schema.LifecycleActive, schema.LifecycleTerminated
+
+	type want struct {
+		kind  DuplicateKind
+		key   string
+		level RiskLevel
+	}
+	tests := []struct {
+		name    string
+		records []*schema.SoTRecord
+		want    []want
+	}{
+		{
+			name: "shared email",
+			records: []*schema.SoTRecord{
+				sotRecord("jane@acme.com", "E1", "Jane Doe", active),
+				sotRecord("JANE@acme.com", "E2", "Jane Smith", active),
+			},
+			want: []want{{DuplicateSoTKey, "email", RiskHigh}},
+		},
+		{
+			name: "rehire with the same employee ID is reported once",
+			records: []*schema.SoTRecord{
+				sotRecord("jane.old@acme.com", "E1", "Jane Doe", terminated),
+				sotRecord("jane@acme.com", "E1", "Jane Doe", active),
+			},
+			want: []want{{DuplicateRehire, "employeeId", RiskMedium}},
+		},
+		{
+			name: "shared name across lifecycle states is a possible rehire",
+			records: []*schema.SoTRecord{
+				sotRecord("alex.kim@acme.com", "E2", "Alex Kim", terminated),
+				sotRecord("akim@acme.com", "E3", "Alex Kim", active),
+			},
+			want: []want{{DuplicatePossibleRehire, "name", RiskLow}},
+		},
+		{
+			name: "shared name among active people is not reported",
+			records: []*schema.SoTRecord{
+				sotRecord("alex.kim@acme.com", "E2", "Alex Kim", active),
+				sotRecord("akim@acme.com", "E3", "Alex Kim", active),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := DetectSoTDuplicates(tt.records)
+			if len(findings) != len(tt.want) {
+				t.Fatalf("got %d findings %+v, want %d", len(findings), findings, len(tt.want))
+			}
+			for i, f := range findings {
+				if f.Kind != tt.want[i].kind || f.Key != tt.want[i].key || f.RiskLevel != tt.want[i].level {
+					t.Errorf("finding %d = %s/%s/%s, want %+v", i, f.Kind, f.Key, f.RiskLevel, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectSatelliteDuplicates(t *testing.T) {
+	jane := sotRecord("jane@acme.com", "E1", "Jane Doe", schema.LifecycleActive)
+
+	tests := []struct {
+		name     string
+		accounts []schema.SatelliteRecord
+		want     int
+	}{
+		{
+			name: "two accounts for one person",
+			accounts: []schema.SatelliteRecord{
+				{Email: "jane@acme.com", SourceFile: "okta", SourceRow: 1},
+				{UserId: "jdoe-admin", SourceFile: "okta", SourceRow: 2},
+			},
+			want: 1,
+		},
+		{
+			name: "one account on several rows",
+			accounts: []schema.SatelliteRecord{
+				{Email: "jane@acme.com", Role: "user", SourceFile: "okta", SourceRow: 1},
+				{Email: "Jane@acme.com", Role: "admin", SourceFile: "okta", SourceRow: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &JoinResult{System: "okta"}
+			for _, sat := range tt.accounts {
+				result.Matched = append(result.Matched, MatchedRecord{SoT: jane, Satellite: sat, MatchType: "exact_email"})
+			}
+			findings := DetectSatelliteDuplicates(result)
+			if len(findings) != tt.want {
+				t.Fatalf("got %d findings %+v, want %d", len(findings), findings, tt.want)
+			}
+			if tt.want > 0 && (findings[0].Kind != DuplicateSatelliteAccounts || findings[0].Value != jane.CanonicalID) {
+				t.Errorf("finding = %+v, want satellite_multiple_accounts for %s", findings[0], jane.CanonicalID)
+			}
+		})
+	}
+}
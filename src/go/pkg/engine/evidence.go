@@ -0,0 +1,70 @@
+package engine
+
+// MatchEvidence records one step of the join cascade for a satellite record:
+// which key was compared, the normalized forms on both sides, the score, any
+// normalization rules applied, and the outcome. Every matched and orphan record
+// carries one entry per cascade step, including steps that were skipped.
+type MatchEvidence struct {
+	Key                 string   `json:"key"` // email | employeeId | name | override | reviewer | identity_graph
+	SatelliteValue      string   `json:"satelliteValue,omitempty"`
+	SatelliteNormalized string   `json:"satelliteNormalized,omitempty"`
+	SoTValue            string   `json:"sotValue,omitempty"`
+	SoTNormalized       string   `json:"sotNormalized,omitempty"`
+	Score               float64  `json:"score"`
+	RulesApplied        []string `json:"rulesApplied,omitempty"`
+	Outcome             string   `json:"outcome"` // matched | ambiguous | no_match | skipped
+	Reason              string   `json:"reason,omitempty"`
+}
+
+// Evidence outcomes.
+const (
+	EvidenceMatched   = "matched"
+	EvidenceAmbiguous = "ambiguous"
+	EvidenceNoMatch   = "no_match"
+	EvidenceSkipped   = "skipped"
+)
+
+// Normalization rules recorded in MatchEvidence.RulesApplied.
+const (
+	RuleEmailCaseFold       = "email_case_fold"
+	RuleEmailAliasDomain    = "email_alias_domain"
+	RuleEmployeeIDNormalize = "employee_id_normalize"
+	RuleNameNormalize       = "name_normalize"
+	RuleNickname            = "nickname"
+	RuleNameSimilarity      = "levenshtein_similarity"
+	RuleReviewerChoice      = "reviewer_selection"
+	RuleIdentityGraph       = "identity_graph"
+	RuleManualOverride      = "manual_override"
+)
+
+// cascadeKeys lists the join cascade steps in the order they are attempted.
+// The override step only appears in evidence when an override applies.
+var cascadeKeys = []string{"override", "email", "employeeId", "name"}
+
+// skipRemainingSteps appends a skipped entry for every cascade step after the
+// one that resolved the record.
+func skipRemainingSteps(evidence []MatchEvidence, resolvedBy string) []MatchEvidence {
+	after := false
+	for _, key := range cascadeKeys {
+		if after {
+			evidence = append(evidence, MatchEvidence{
+				Key:     key,
+				Outcome: EvidenceSkipped,
+				Reason:  "resolved by " + resolvedBy,
+			})
+		}
+		if key == resolvedBy {
+			after = true
+		}
+	}
+	return evidence
+}
+
+// emptyKeyEvidence records a cascade step skipped because the satellite has no value for it.
+func emptyKeyEvidence(key string) MatchEvidence {
+	return MatchEvidence{
+		Key:     key,
+		Outcome: EvidenceSkipped,
+		Reason:  "satellite " + key + " is empty",
+	}
+}
@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"strings"
+
+	"uar/pkg/schema"
+)
+
+// maxTransitiveHops bounds how many account-to-account edges a transitive link may
+// traverse. Longer chains are more likely to join unrelated people through a shared
+// generic identifier.
+const maxTransitiveHops = 3
+
+// anchorMatchTypes are the direct matches trusted to anchor a transitive path. Fuzzy
+// name matches are excluded until a reviewer confirms them (reviewer_selected), so an
+// unconfirmed guess cannot be propagated to accounts in other systems.
+var anchorMatchTypes = map[string]bool{
+	"manual_override":   true,
+	"exact_email":       true,
+	"exact_id":          true,
+	"reviewer_selected": true,
+}
+
+// genericUserIDs are user IDs and email local parts held by shared mailboxes and
+// role accounts in many systems. They are not graph keys: "admin@acme.com" in Okta
+// and an IAM user "admin" are not evidence of the same person.
+var genericUserIDs = map[string]bool{
+	"admin": true, "administrator": true, "root": true, "support": true, "helpdesk": true,
+	"info": true, "team": true, "shared": true, "generic": true, "test": true,
+	"testuser": true, "training": true, "guest": true, "noreply": true, "no-reply": true,
+	"sales": true, "ops": true, "it": true, "security": true, "hr": true,
+	"billing": true, "contact": true, "office": true, "user": true,
+}
+
+// IdentityHop is one account on a transitive identity path.
+type IdentityHop struct {
+	System  string `json:"system"`
+	Account string `json:"account"`
+	// Via is the identifier shared with the previous hop; empty for the starting account.
+	Via string `json:"via,omitempty"`
+}
+
+// identityNode is a satellite account in the cross-system identity graph.
+type identityNode struct {
+	system  string
+	account string
+	sot     *schema.SoTRecord // set when the account matched the SoT directly
+	ids     []string
+}
+
+// LinkTransitiveIdentities builds an identity graph across the join results of one run
+// and links orphans that reach a SoT person through accounts in other systems.
+//
+// Nodes are satellite accounts; edges connect accounts in different systems that share
+// an identifier (email, user ID, or an email local part equal to a user ID). Generic
+// user IDs such as "admin" are not identifiers. Only accounts matched directly against
+// the SoT with one of anchorMatchTypes anchor a path; fuzzy matches are plain nodes.
+// An orphan is linked when the nearest anchored accounts, at most maxTransitiveHops
+// away, all resolve to the same SoT person; if they disagree the orphan is left alone.
+//
+// Linked orphans move to Matched with matchType "transitive" and the path recorded.
+// Their field conflicts are detected with checks, which should be the ConflictChecks
+// the results were joined with; nil uses DefaultConflictChecks. Results are modified
+// in place. Returns the number of records linked.
+func LinkTransitiveIdentities(results []*JoinResult, checks []ConflictCheck) int {
+	var nodes []*identityNode
+	nodeByKey := make(map[string]int)
+	idToNodes := make(map[string][]int)
+
+	addNode := func(sat schema.SatelliteRecord, sot *schema.SoTRecord) int {
+		account := satelliteAccountKey(sat)
+		key := sat.SourceFile + "\x00" + account
+		if i, ok := nodeByKey[key]; ok {
+			if nodes[i].sot == nil {
+				nodes[i].sot = sot
+			}
+			return i
+		}
+		n := &identityNode{system: sat.SourceFile, account: account, sot: sot, ids: accountIdentifiers(sat)}
+		nodes = append(nodes, n)
+		i := len(nodes) - 1
+		nodeByKey[key] = i
+		for _, id := range n.ids {
+			idToNodes[id] = append(idToNodes[id], i)
+		}
+		return i
+	}
+
+	for _, jr := range results {
+		for _, m := range jr.Matched {
+			if m.MatchType == "transitive" || m.SoT == nil {
+				continue
+			}
+			if anchorMatchTypes[m.MatchType] {
+				addNode(m.Satellite, m.SoT)
+			} else {
+				addNode(m.Satellite, nil)
+			}
+		}
+		for _, o := range jr.Orphans {
+			addNode(o.Satellite, nil)
+		}
+	}
+
+	linked := 0
+	for _, jr := range results {
+		remaining := make([]OrphanRecord, 0, len(jr.Orphans))
+		for _, o := range jr.Orphans {
+			start := nodeByKey[o.Satellite.SourceFile+"\x00"+satelliteAccountKey(o.Satellite)]
+			sot, path := resolveThroughGraph(nodes, idToNodes, start)
+			if sot == nil {
+				remaining = append(remaining, o)
+				continue
+			}
+
+			evidence := append(append([]MatchEvidence(nil), o.AttemptedMatches...), MatchEvidence{
+				Key:                 "identity_graph",
+				SatelliteValue:      path[0].Account,
+				SatelliteNormalized: path[1].Via,
+				SoTValue:            sot.DisplayName,
+				SoTNormalized:       sot.CanonicalID,
+				Score:               1.0,
+				RulesApplied:        []string{RuleIdentityGraph},
+				Outcome:             EvidenceMatched,
+				Reason:              "linked through " + path[len(path)-1].System + " account " + path[len(path)-1].Account,
+			})
+
+			jr.Matched = append(jr.Matched, MatchedRecord{
+				SoT:            sot,
+				Satellite:      o.Satellite,
+				MatchType:      "transitive",
+				Conflicts:      DetectConflictsWith(sot, o.Satellite, checks),
+				Evidence:       evidence,
+				Path:           path,
+				Classification: o.Classification,
+			})
+			jr.Stats.Transitive++
+			jr.Stats.Orphans--
+			linked++
+		}
+		jr.Orphans = remaining
+	}
+
+	return linked
+}
+
+// resolveThroughGraph runs a breadth-first search from an orphan account and returns
+// the SoT record reached by the nearest anchored accounts, with the path to the first
+// of them. Returns nil when nothing is reachable or the nearest anchors disagree.
+func resolveThroughGraph(nodes []*identityNode, idToNodes map[string][]int, start int) (*schema.SoTRecord, []IdentityHop) {
+	type visit struct {
+		parent int
+		via    string
+	}
+
+	visited := map[int]visit{start: {parent: -1}}
+	frontier := []int{start}
+
+	for depth := 0; depth < maxTransitiveHops && len(frontier) > 0; depth++ {
+		var next []int
+		var anchors []int
+		for _, cur := range frontier {
+			for _, id := range nodes[cur].ids {
+				for _, nb := range idToNodes[id] {
+					if _, seen := visited[nb]; seen || nodes[nb].system == nodes[cur].system {
+						continue
+					}
+					visited[nb] = visit{parent: cur, via: id}
+					if nodes[nb].sot != nil {
+						anchors = append(anchors, nb)
+					} else {
+						next = append(next, nb)
+					}
+				}
+			}
+		}
+
+		if len(anchors) > 0 {
+			sot := nodes[anchors[0]].sot
+			for _, a := range anchors[1:] {
+				if nodes[a].sot.CanonicalID != sot.CanonicalID {
+					return nil, nil
+				}
+			}
+
+			var path []IdentityHop
+			for n := anchors[0]; n != -1; n = visited[n].parent {
+				path = append([]IdentityHop{{
+					System:  nodes[n].system,
+					Account: nodes[n].account,
+					Via:     visited[n].via,
+				}}, path...)
+			}
+			return sot, path
+		}
+
+		frontier = next
+	}
+
+	return nil, nil
+}
+
+// accountIdentifiers returns the identifiers a satellite account can share with
+// accounts in other systems. An email's local part is also emitted as a user ID so
+// that an IAM user "jdoe" links to an Okta account "jdoe@acme.com". Generic user IDs
+// are dropped.
+func accountIdentifiers(sat schema.SatelliteRecord) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if strings.HasPrefix(id, "userId:") && genericUserIDs[strings.TrimPrefix(id, "userId:")] {
+			return
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	addEmail := func(email string) {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			return
+		}
+		add("email:" + email)
+		if at := strings.IndexByte(email, '@'); at > 0 {
+			add("userId:" + email[:at])
+		}
+	}
+
+	addEmail(sat.Email)
+	if uid := strings.ToLower(strings.TrimSpace(sat.UserId)); uid != "" {
+		if strings.Contains(uid, "@") {
+			addEmail(uid)
+		} else {
+			add("userId:" + uid)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestLinkTransitiveIdentities(t *testing.T) {
+	sot := BuildSoTIndex([]*schema.SoTRecord{
+		{CanonicalID: "jdoe@acme.com", EmployeeID: "E1", DisplayName: "Jane Doe", NormalizedName: "jane doe", Email: "jdoe@acme.com"},
+		{CanonicalID: "sam@acme.com", EmployeeID: "E2", DisplayName: "Sam Ortiz", NormalizedName: "sam ortiz", Email: "sam@acme.com"},
+		{CanonicalID: "sam@example.org", EmployeeID: "E3", DisplayName: "Sam Patel", NormalizedName: "sam patel", Email: "sam@example.org"},
+	})
+	okta := []schema.SatelliteRecord{
+		{Email: "jdoe@acme.com", DisplayName: "Jane Doe", SourceFile: "okta.csv", SourceRow: 1},
+		{Email: "sam@acme.com", DisplayName: "Sam Ortiz", SourceFile: "okta.csv", SourceRow: 2},
+	}
+	github := []schema.SatelliteRecord{
+		{Email: "sam@example.org", DisplayName: "Sam Patel", SourceFile: "github.csv", SourceRow: 1},
+	}
+	onlyDepartment := []ConflictCheck{{Field: "department", Mode: ConflictCaseInsensitive, Severity: RiskLow, Resolution: ResolveSoTWins}}
+
+	tests := []struct {
+		name          string
+		account       schema.SatelliteRecord
+		checks        []ConflictCheck
+		wantID        string // empty when the orphan must stay unlinked
+		wantPath      int
+		wantConflicts []string
+	}{
+		{
+			// An IAM user named after its user ID must stay a human orphan so the
+			// graph can link it through the Okta email's local part.
+			name:          "IAM user ID links through email local part",
+			account:       schema.SatelliteRecord{UserId: "jdoe", DisplayName: "jdoe", SourceFile: "aws_iam.csv", SourceRow: 1},
+			wantID:        "jdoe@acme.com",
+			wantPath:      2,
+			wantConflicts: []string{"displayName"},
+		},
+		{
+			name:    "configured conflict checks apply to the link",
+			account: schema.SatelliteRecord{UserId: "jdoe", DisplayName: "jdoe", SourceFile: "aws_iam.csv", SourceRow: 1},
+			checks:  onlyDepartment,
+			wantID:  "jdoe@acme.com",
+			// The displayName check is not configured, so there is no conflict
+			wantPath: 2,
+		},
+		{
+			name:    "anchors for different people leave the orphan alone",
+			account: schema.SatelliteRecord{UserId: "sam", DisplayName: "sam", SourceFile: "aws_iam.csv", SourceRow: 2},
+		},
+		{
+			name:    "accounts in the same system do not link",
+			account: schema.SatelliteRecord{UserId: "jdoe", DisplayName: "jdoe", SourceFile: "okta.csv", SourceRow: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			satellites := map[string][]schema.SatelliteRecord{"okta": okta, "github": github}
+			system := strings.TrimSuffix(tt.account.SourceFile, ".csv")
+			satellites[system] = append(append([]schema.SatelliteRecord(nil), satellites[system]...), tt.account)
+
+			var results []*JoinResult
+			var jr *JoinResult
+			for _, name := range []string{"okta", "github", "aws_iam"} {
+				results = append(results, JoinAgainstSoT(sot, satellites[name], name, JoinOptions{}))
+				if name == system {
+					jr = results[len(results)-1]
+				}
+			}
+			if len(jr.Orphans) != 1 {
+				t.Fatalf("join left %d orphans, want the account as the only orphan (non-human: %d)", len(jr.Orphans), len(jr.NonHuman))
+			}
+
+			linked := LinkTransitiveIdentities(results, tt.checks)
+
+			if tt.wantID == "" {
+				if linked != 0 || len(jr.Orphans) != 1 {
+					t.Fatalf("linked %d accounts, want the orphan left alone", linked)
+				}
+				return
+			}
+			if linked != 1 || len(jr.Orphans) != 0 || jr.Stats.Transitive != 1 {
+				t.Fatalf("linked %d, orphans %d, transitive %d; want one transitive link", linked, len(jr.Orphans), jr.Stats.Transitive)
+			}
+			m := jr.Matched[len(jr.Matched)-1]
+			if m.MatchType != "transitive" || m.SoT.CanonicalID != tt.wantID {
+				t.Errorf("linked to %s as %s, want %s as transitive", m.SoT.CanonicalID, m.MatchType, tt.wantID)
+			}
+			if len(m.Path) != tt.wantPath {
+				t.Errorf("path = %+v, want %d hops", m.Path, tt.wantPath)
+			}
+			var fields []string
+			for _, c := range m.Conflicts {
+				fields = append(fields, c.Field)
+			}
+			if len(fields) != len(tt.wantConflicts) || (len(fields) > 0 && fields[0] != tt.wantConflicts[0]) {
+				t.Errorf("conflicts = %v, want %v", fields, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestLinkTransitiveIdentitiesAnchors(t *testing.T) {
+	jane := &schema.SoTRecord{CanonicalID: "jane@acme.com", EmployeeID: "E1", DisplayName: "Jane Doe", Email: "jane@acme.com"}
+
+	tests := []struct {
+		name       string
+		matchType  string
+		oktaEmail  string
+		iamUser    string
+		wantLinked bool
+	}{
+		{name: "exact email anchors", matchType: "exact_email", oktaEmail: "jdoe@acme.com", iamUser: "jdoe", wantLinked: true},
+		{name: "manual override anchors", matchType: "manual_override", oktaEmail: "jdoe@acme.com", iamUser: "jdoe", wantLinked: true},
+		{name: "reviewer selection anchors", matchType: "reviewer_selected", oktaEmail: "jdoe@acme.com", iamUser: "jdoe", wantLinked: true},
+		{name: "unconfirmed fuzzy name does not anchor", matchType: "fuzzy_name", oktaEmail: "jdoe@acme.com", iamUser: "jdoe"},
+		{name: "ambiguous fuzzy match does not anchor", matchType: "fuzzy_ambiguous", oktaEmail: "jdoe@acme.com", iamUser: "jdoe"},
+		{name: "generic local part is not a key", matchType: "exact_email", oktaEmail: "admin@acme.com", iamUser: "admin"},
+		{name: "generic local part is case-insensitive", matchType: "exact_email", oktaEmail: "Support@acme.com", iamUser: "SUPPORT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			okta := &JoinResult{Matched: []MatchedRecord{{
+				SoT:       jane,
+				Satellite: schema.SatelliteRecord{Email: tt.oktaEmail, DisplayName: "Jane Doe", SourceFile: "okta.csv", SourceRow: 1},
+				MatchType: tt.matchType,
+			}}}
+			iam := &JoinResult{
+				Orphans: []OrphanRecord{{Satellite: schema.SatelliteRecord{UserId: tt.iamUser, SourceFile: "aws_iam.csv", SourceRow: 1}}},
+				Stats:   JoinStats{Orphans: 1},
+			}
+
+			linked := LinkTransitiveIdentities([]*JoinResult{okta, iam}, nil)
+
+			if got := linked == 1; got != tt.wantLinked {
+				t.Fatalf("LinkTransitiveIdentities() linked %d, want linked = %v", linked, tt.wantLinked)
+			}
+			if tt.wantLinked && (len(iam.Matched) != 1 || iam.Matched[0].SoT != jane) {
+				t.Errorf("IAM account matched = %+v, want a transitive link to %s", iam.Matched, jane.CanonicalID)
+			}
+			if !tt.wantLinked && len(iam.Orphans) != 1 {
+				t.Errorf("IAM orphans = %d, want the account left as an orphan", len(iam.Orphans))
+			}
+		})
+	}
+}
@@ -9,9 +9,13 @@ import (
 
 // JoinResult contains the outcome of joining satellite records against the SoT index.
 type JoinResult struct {
+	System  string          `json:"system"`
 	Matched []MatchedRecord `json:"matched"`
 	Orphans []OrphanRecord  `json:"orphans"`
-	Stats   JoinStats       `json:"stats"`
+	// NonHuman holds unmatched service, shared/generic, and break-glass accounts.
+	// They are kept apart from Orphans, which are unknown humans.
+	NonHuman []OrphanRecord `json:"nonHuman"`
+	Stats    JoinStats      `json:"stats"`
 }
 
 // MatchedRecord represents a satellite record that was successfully matched to a SoT record.
@@ -20,22 +24,60 @@ type MatchedRecord struct {
 	Satellite schema.SatelliteRecord `json:"satellite"`
 	MatchType string                `json:"matchType"`
 	Conflicts []FieldConflict       `json:"conflicts"`
+	// Candidates lists every SoT record the fuzzy name step considered, best
+	// score first. Only populated for fuzzy_name and fuzzy_ambiguous matches.
+	Candidates []MatchCandidate `json:"candidates,omitempty"`
+	// Evidence explains how the join cascade reached this match, one entry per step.
+	Evidence []MatchEvidence `json:"evidence"`
+	// Path lists the accounts traversed for a transitive match, starting with this one.
+	Path []IdentityHop `json:"path,omitempty"`
+	// Override is the reviewer-confirmed link behind a manual_override match.
+	Override *LinkOverride `json:"override,omitempty"`
+	// Classification is the account class (human, service, shared, break_glass).
+	Classification AccountClassification `json:"classification"`
+}
+
+// MatchCandidate is a SoT record considered during fuzzy name matching,
+// with its similarity to the satellite's normalized name.
+type MatchCandidate struct {
+	CanonicalID string  `json:"canonicalId"`
+	EmployeeID  string  `json:"employeeId"`
+	DisplayName string  `json:"displayName"`
+	Department  string  `json:"department"`
+	Score       float64 `json:"score"`
+	// Key names the identifier that nearly matched for near-miss suggestions
+	// (name, emailLocal, userId); empty for fuzzy name candidates.
+	Key string `json:"key,omitempty"`
 }
 
 // OrphanRecord represents a satellite record with no SoT match.
 type OrphanRecord struct {
 	Satellite        schema.SatelliteRecord `json:"satellite"`
-	AttemptedMatches []string               `json:"attemptedMatches"`
+	AttemptedMatches []MatchEvidence        `json:"attemptedMatches"`
+	// Override is set when a reviewer confirmed the account is not a person,
+	// or is a service account.
+	Override *LinkOverride `json:"override,omitempty"`
+	// Owner is the SoT person a service_account override names as the account's
+	// owner; nil when there is no such override or the owner is not in the SoT.
+	Owner *schema.SoTRecord `json:"owner,omitempty"`
+	// Classification is the account class (human, service, shared, break_glass).
+	Classification AccountClassification `json:"classification"`
+	// Suggestions lists SoT records that fell below the match thresholds, best first,
+	// so a reviewer can claim the right owner. Only populated for human orphans.
+	Suggestions []MatchCandidate `json:"suggestions,omitempty"`
 }
 
 // JoinStats contains aggregate statistics about the join operation.
 type JoinStats struct {
 	TotalProcessed int `json:"totalProcessed"`
+	ManualOverride int `json:"manualOverride"`
 	ExactEmail     int `json:"exactEmail"`
 	ExactID        int `json:"exactId"`
 	FuzzyName      int `json:"fuzzyName"`
 	Ambiguous      int `json:"ambiguous"`
+	Transitive     int `json:"transitive"`
 	Orphans        int `json:"orphans"`
+	NonHuman       int `json:"nonHuman"`
 }
 
 // Fuzzy match thresholds
@@ -45,117 +87,289 @@ const (
 	maxFuzzyCandidates   = 10
 )
 
+// JoinOptions carries optional inputs to JoinAgainstSoT.
+// The zero value runs the plain join cascade.
+type JoinOptions struct {
+	// Overrides holds reviewer-confirmed links consulted before the cascade.
+	Overrides *OverrideTable
+	// Classifier marks non-human accounts; nil uses DefaultClassifierConfig.
+	Classifier *AccountClassifier
+	// ConflictChecks configures field conflict detection; nil uses DefaultConflictChecks.
+	ConflictChecks []ConflictCheck
+	// MaxSuggestions caps near-miss suggestions per orphan; 0 uses DefaultMaxSuggestions.
+	MaxSuggestions int
+	// MatchRules enables alias domains, nicknames, and employee ID normalization;
+	// nil applies none of them.
+	MatchRules *MatchRules
+}
+
 // JoinAgainstSoT performs the join cascade from Section 6.3 of the design doc.
 // For each satellite record, it attempts matching in order:
-//   1. Exact email match (case-insensitive)
-//   2. Exact employeeId match
-//   3. Fuzzy name match (normalized Levenshtein, threshold 0.85, gap 0.10)
-//   4. No match -> orphan
-func JoinAgainstSoT(index *SoTIndex, satellites []schema.SatelliteRecord, systemName string) *JoinResult {
+//   0. Reviewer-confirmed override (see OverrideTable)
+//   1. Exact email match (case-insensitive), then with alias domains replaced
+//   2. Exact employeeId match, then by normalized ID
+//   3. Fuzzy name match (normalized Levenshtein, threshold 0.85, gap 0.10), then
+//      with the first name's nickname replaced
+//   4. No match -> orphan, or non-human if the classifier marks the account as
+//      service, shared/generic, or break-glass
+func JoinAgainstSoT(index *SoTIndex, satellites []schema.SatelliteRecord, systemName string, opts JoinOptions) *JoinResult {
 	result := &JoinResult{
-		Matched: make([]MatchedRecord, 0),
-		Orphans: make([]OrphanRecord, 0),
+		System:   systemName,
+		Matched:  make([]MatchedRecord, 0),
+		Orphans:  make([]OrphanRecord, 0),
+		NonHuman: make([]OrphanRecord, 0),
 	}
+	rules := newRuleIndex(index, opts.MatchRules)
 
 	for _, sat := range satellites {
-		var attemptedMatches []string
+		evidence := make([]MatchEvidence, 0, len(cascadeKeys))
+
+		// Step 0: Reviewer-confirmed override
+		if ov := opts.Overrides.Lookup(sat); ov != nil {
+			var sotRec *schema.SoTRecord
+			if ov.Kind != OverrideNotAPerson {
+				sotRec = findByCanonicalID(index, ov.CanonicalID)
+			}
+			step := overrideEvidence(ov, sat, sotRec)
+
+			switch {
+			case ov.Kind == OverridePerson && sotRec != nil:
+				result.Matched = append(result.Matched, MatchedRecord{
+					SoT:       sotRec,
+					Satellite: sat,
+					MatchType: "manual_override",
+					Conflicts: DetectConflictsWith(sotRec, sat, opts.ConflictChecks),
+					Evidence:  skipRemainingSteps(append(evidence, step), "override"),
+					Override:  ov,
+				})
+				result.Stats.ManualOverride++
+				result.Stats.TotalProcessed++
+				continue
+
+			case ov.Kind == OverrideNotAPerson || ov.Kind == OverrideServiceAccount:
+				// Confirmed non-person, or a service account. Either way the cascade must
+				// not attach it to a person; a service account's owner is linked separately.
+				class := opts.Classifier.Classify(sat)
+				if class.Class == AccountHuman {
+					class = AccountClassification{Class: AccountService, Reason: "reviewer confirmed " + string(ov.Kind)}
+				}
+				if ov.Kind == OverrideServiceAccount && sotRec == nil {
+					step.Reason += "; owner " + ov.CanonicalID + " not found in SoT"
+				}
+				result.NonHuman = append(result.NonHuman, OrphanRecord{
+					Satellite:        sat,
+					AttemptedMatches: skipRemainingSteps(append(evidence, step), "override"),
+					Override:         ov,
+					Owner:            sotRec,
+					Classification:   class,
+				})
+				result.Stats.NonHuman++
+				result.Stats.TotalProcessed++
+				continue
+			}
+
+			// The confirmed person is no longer in the SoT — fall through to the cascade.
+			step.Reason += "; canonical ID " + ov.CanonicalID + " not found in SoT"
+			evidence = append(evidence, step)
+		}
 
 		// Step 1: Exact email match (case-insensitive)
 		if sat.Email != "" {
 			emailKey := strings.ToLower(sat.Email)
-			attemptedMatches = append(attemptedMatches, "email:"+emailKey)
-			if sotRec, ok := index.ByEmail[emailKey]; ok {
-				conflicts := DetectConflicts(sotRec, sat)
+			step := MatchEvidence{
+				Key:                 "email",
+				SatelliteValue:      sat.Email,
+				SatelliteNormalized: emailKey,
+				RulesApplied:        []string{RuleEmailCaseFold},
+				Outcome:             EvidenceNoMatch,
+			}
+			sotRec, ok := index.ByEmail[emailKey]
+			aliased := false
+			if !ok {
+				// Alias domains only apply once the plain address finds nobody
+				if key, rec := rules.email(emailKey); rec != nil || key != emailKey {
+					step.SatelliteNormalized = key
+					step.RulesApplied = append(step.RulesApplied, RuleEmailAliasDomain)
+					sotRec, ok, aliased = rec, rec != nil, true
+				}
+			}
+			if ok {
+				step.SoTValue = sotRec.Email
+				step.SoTNormalized = emailKey
+				if aliased {
+					step.SoTNormalized = step.SatelliteNormalized
+				}
+				step.Score = 1.0
+				step.Outcome = EvidenceMatched
+				conflicts := DetectConflictsWith(sotRec, sat, opts.ConflictChecks)
 				result.Matched = append(result.Matched, MatchedRecord{
 					SoT:       sotRec,
 					Satellite: sat,
 					MatchType: "exact_email",
 					Conflicts: conflicts,
+					Evidence:  skipRemainingSteps(append(evidence, step), "email"),
 				})
 				result.Stats.ExactEmail++
 				result.Stats.TotalProcessed++
 				continue
 			}
+			evidence = append(evidence, step)
+		} else {
+			evidence = append(evidence, emptyKeyEvidence("email"))
 		}
 
 		// Step 2: Exact employeeId match
 		if sat.UserId != "" {
-			attemptedMatches = append(attemptedMatches, "employeeId:"+sat.UserId)
-			if sotRec, ok := index.ByEmployeeID[sat.UserId]; ok {
-				conflicts := DetectConflicts(sotRec, sat)
+			step := MatchEvidence{
+				Key:                 "employeeId",
+				SatelliteValue:      sat.UserId,
+				SatelliteNormalized: sat.UserId,
+				Outcome:             EvidenceNoMatch,
+			}
+			sotRec, ok := index.ByEmployeeID[sat.UserId]
+			if !ok && rules.byID != nil {
+				key, rec := rules.employeeID(sat.UserId)
+				step.SatelliteNormalized = key
+				step.RulesApplied = []string{RuleEmployeeIDNormalize}
+				sotRec, ok = rec, rec != nil
+			}
+			if ok {
+				step.SoTValue = sotRec.EmployeeID
+				step.SoTNormalized = sotRec.EmployeeID
+				if step.RulesApplied != nil {
+					step.SoTNormalized = normalizeEmployeeID(sotRec.EmployeeID)
+				}
+				step.Score = 1.0
+				step.Outcome = EvidenceMatched
+				conflicts := DetectConflictsWith(sotRec, sat, opts.ConflictChecks)
 				result.Matched = append(result.Matched, MatchedRecord{
 					SoT:       sotRec,
 					Satellite: sat,
 					MatchType: "exact_id",
 					Conflicts: conflicts,
+					Evidence:  skipRemainingSteps(append(evidence, step), "employeeId"),
 				})
 				result.Stats.ExactID++
 				result.Stats.TotalProcessed++
 				continue
 			}
+			evidence = append(evidence, step)
+		} else {
+			evidence = append(evidence, emptyKeyEvidence("employeeId"))
 		}
 
 		// Step 3: Fuzzy name match
 		if sat.DisplayName != "" {
 			normalizedSatName := schema.NormalizeName(sat.DisplayName)
-			attemptedMatches = append(attemptedMatches, "name:"+normalizedSatName)
+			step := MatchEvidence{
+				Key:                 "name",
+				SatelliteValue:      sat.DisplayName,
+				SatelliteNormalized: normalizedSatName,
+				RulesApplied:        []string{RuleNameNormalize, RuleNameSimilarity},
+				Outcome:             EvidenceNoMatch,
+				Reason:              "no candidate at or above similarity threshold",
+			}
 
-			matched := fuzzyNameMatch(index, normalizedSatName, sat, result)
+			matched := fuzzyNameMatch(index, normalizedSatName, sat, result, opts.ConflictChecks)
+			if expanded := opts.MatchRules.expandNickname(normalizedSatName); !matched && expanded != normalizedSatName {
+				step.SatelliteNormalized = expanded
+				step.RulesApplied = append(step.RulesApplied, RuleNickname)
+				matched = fuzzyNameMatch(index, expanded, sat, result, opts.ConflictChecks)
+			}
 			if matched {
+				// The fuzzy step appended the match; attach the name evidence to it.
+				m := &result.Matched[len(result.Matched)-1]
+				step.SoTValue = m.SoT.DisplayName
+				step.SoTNormalized = m.SoT.NormalizedName
+				step.Score = m.Candidates[0].Score
+				step.Outcome = EvidenceMatched
+				step.Reason = ""
+				if m.MatchType == "fuzzy_ambiguous" {
+					step.Outcome = EvidenceAmbiguous
+					step.Reason = "multiple candidates within ambiguity gap"
+				}
+				m.Evidence = append(evidence, step)
 				result.Stats.TotalProcessed++
 				continue
 			}
+			evidence = append(evidence, step)
+		} else {
+			evidence = append(evidence, emptyKeyEvidence("name"))
 		}
 
-		// Step 4: No match -> orphan
-		result.Orphans = append(result.Orphans, OrphanRecord{
+		// Step 4: No match -> orphan or non-human account
+		orphan := OrphanRecord{
 			Satellite:        sat,
-			AttemptedMatches: attemptedMatches,
-		})
-		result.Stats.Orphans++
+			AttemptedMatches: evidence,
+			Classification:   opts.Classifier.Classify(sat),
+		}
+		if orphan.Classification.Class != AccountHuman {
+			result.NonHuman = append(result.NonHuman, orphan)
+			result.Stats.NonHuman++
+		} else {
+			orphan.Suggestions = NearMissCandidates(index, sat, opts.MaxSuggestions)
+			result.Orphans = append(result.Orphans, orphan)
+			result.Stats.Orphans++
+		}
 		result.Stats.TotalProcessed++
 	}
 
+	for i := range result.Matched {
+		result.Matched[i].Classification = opts.Classifier.Classify(result.Matched[i].Satellite)
+	}
+
 	return result
 }
 
+// scoredCandidate pairs a SoT record with its name similarity score.
+type scoredCandidate struct {
+	record *schema.SoTRecord
+	score  float64
+}
+
 // fuzzyNameMatch attempts to match a satellite record by normalized name.
 // Returns true if a match (including ambiguous) was made, false if orphan.
-func fuzzyNameMatch(index *SoTIndex, normalizedSatName string, sat schema.SatelliteRecord, result *JoinResult) bool {
+func fuzzyNameMatch(index *SoTIndex, normalizedSatName string, sat schema.SatelliteRecord, result *JoinResult, checks []ConflictCheck) bool {
 	candidates, ok := index.ByName[normalizedSatName]
 	if !ok || len(candidates) == 0 {
 		// Try a broader search across all names in the index
-		return fuzzyNameBroadSearch(index, normalizedSatName, sat, result)
+		return fuzzyNameBroadSearch(index, normalizedSatName, sat, result, checks)
 	}
 
-	if len(candidates) > maxFuzzyCandidates {
-		// Too many candidates — flag as ambiguous without scoring
-		topNames := make([]string, 0, 3)
-		for i, c := range candidates {
-			if i >= 3 {
-				break
-			}
-			topNames = append(topNames, c.DisplayName)
+	scored := make([]scoredCandidate, len(candidates))
+	for i, c := range candidates {
+		scored[i] = scoredCandidate{
+			record: c,
+			score:  similarity(normalizedSatName, c.NormalizedName),
 		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	if len(candidates) > maxFuzzyCandidates {
+		// Too many candidates — flag as ambiguous without picking a winner
 		result.Matched = append(result.Matched, MatchedRecord{
-			SoT:       candidates[0],
-			Satellite: sat,
-			MatchType: "fuzzy_ambiguous",
-			Conflicts: DetectConflicts(candidates[0], sat),
+			SoT:        scored[0].record,
+			Satellite:  sat,
+			MatchType:  "fuzzy_ambiguous",
+			Conflicts:  DetectConflictsWith(scored[0].record, sat, checks),
+			Candidates: toMatchCandidates(scored),
 		})
 		result.Stats.Ambiguous++
 		return true
 	}
 
-	if len(candidates) == 1 {
-		score := similarity(normalizedSatName, candidates[0].NormalizedName)
-		if score >= fuzzyMatchThreshold {
-			conflicts := DetectConflicts(candidates[0], sat)
+	if len(scored) == 1 {
+		if scored[0].score >= fuzzyMatchThreshold {
+			conflicts := DetectConflictsWith(scored[0].record, sat, checks)
 			result.Matched = append(result.Matched, MatchedRecord{
-				SoT:       candidates[0],
-				Satellite: sat,
-				MatchType: "fuzzy_name",
-				Conflicts: conflicts,
+				SoT:        scored[0].record,
+				Satellite:  sat,
+				MatchType:  "fuzzy_name",
+				Conflicts:  conflicts,
+				Candidates: toMatchCandidates(scored),
 			})
 			result.Stats.FuzzyName++
 			return true
@@ -163,33 +377,16 @@ func fuzzyNameMatch(index *SoTIndex, normalizedSatName string, sat schema.Satell
 		return false
 	}
 
-	// Multiple candidates — score and sort
-	type scoredCandidate struct {
-		record *schema.SoTRecord
-		score  float64
-	}
-
-	scored := make([]scoredCandidate, len(candidates))
-	for i, c := range candidates {
-		scored[i] = scoredCandidate{
-			record: c,
-			score:  similarity(normalizedSatName, c.NormalizedName),
-		}
-	}
-
-	sort.Slice(scored, func(i, j int) bool {
-		return scored[i].score > scored[j].score
-	})
-
 	if scored[0].score >= fuzzyMatchThreshold {
 		if scored[0].score-scored[1].score >= fuzzyAmbiguityGap {
 			// Clear winner
-			conflicts := DetectConflicts(scored[0].record, sat)
+			conflicts := DetectConflictsWith(scored[0].record, sat, checks)
 			result.Matched = append(result.Matched, MatchedRecord{
-				SoT:       scored[0].record,
-				Satellite: sat,
-				MatchType: "fuzzy_name",
-				Conflicts: conflicts,
+				SoT:        scored[0].record,
+				Satellite:  sat,
+				MatchType:  "fuzzy_name",
+				Conflicts:  conflicts,
+				Candidates: toMatchCandidates(scored),
 			})
 			result.Stats.FuzzyName++
 			return true
@@ -197,10 +394,11 @@ func fuzzyNameMatch(index *SoTIndex, normalizedSatName string, sat schema.Satell
 
 		// Ambiguous — too close to call
 		result.Matched = append(result.Matched, MatchedRecord{
-			SoT:       scored[0].record,
-			Satellite: sat,
-			MatchType: "fuzzy_ambiguous",
-			Conflicts: DetectConflicts(scored[0].record, sat),
+			SoT:        scored[0].record,
+			Satellite:  sat,
+			MatchType:  "fuzzy_ambiguous",
+			Conflicts:  DetectConflictsWith(scored[0].record, sat, checks),
+			Candidates: toMatchCandidates(scored),
 		})
 		result.Stats.Ambiguous++
 		return true
@@ -211,27 +409,25 @@ func fuzzyNameMatch(index *SoTIndex, normalizedSatName string, sat schema.Satell
 
 // fuzzyNameBroadSearch performs a broader fuzzy search across all names in the index
 // when an exact normalized name lookup fails. This handles typos and minor variations.
-func fuzzyNameBroadSearch(index *SoTIndex, normalizedSatName string, sat schema.SatelliteRecord, result *JoinResult) bool {
+func fuzzyNameBroadSearch(index *SoTIndex, normalizedSatName string, sat schema.SatelliteRecord, result *JoinResult, checks []ConflictCheck) bool {
 	if normalizedSatName == "" {
 		return false
 	}
 
-	type scoredCandidate struct {
-		record *schema.SoTRecord
-		score  float64
-	}
-
 	var topCandidates []scoredCandidate
 
-	for _, candidates := range index.ByName {
-		for _, c := range candidates {
-			score := similarity(normalizedSatName, c.NormalizedName)
-			if score >= fuzzyMatchThreshold {
-				topCandidates = append(topCandidates, scoredCandidate{
-					record: c,
-					score:  score,
-				})
-			}
+	// Scan records in SoT order rather than ranging over ByName so that ties
+	// resolve to the same record on every run.
+	for _, c := range index.Records {
+		if c.NormalizedName == "" {
+			continue
+		}
+		score := similarity(normalizedSatName, c.NormalizedName)
+		if score >= fuzzyMatchThreshold {
+			topCandidates = append(topCandidates, scoredCandidate{
+				record: c,
+				score:  score,
+			})
 		}
 	}
 
@@ -239,17 +435,18 @@ func fuzzyNameBroadSearch(index *SoTIndex, normalizedSatName string, sat schema.
 		return false
 	}
 
-	sort.Slice(topCandidates, func(i, j int) bool {
+	sort.SliceStable(topCandidates, func(i, j int) bool {
 		return topCandidates[i].score > topCandidates[j].score
 	})
 
 	if len(topCandidates) == 1 {
-		conflicts := DetectConflicts(topCandidates[0].record, sat)
+		conflicts := DetectConflictsWith(topCandidates[0].record, sat, checks)
 		result.Matched = append(result.Matched, MatchedRecord{
-			SoT:       topCandidates[0].record,
-			Satellite: sat,
-			MatchType: "fuzzy_name",
-			Conflicts: conflicts,
+			SoT:        topCandidates[0].record,
+			Satellite:  sat,
+			MatchType:  "fuzzy_name",
+			Conflicts:  conflicts,
+			Candidates: toMatchCandidates(topCandidates),
 		})
 		result.Stats.FuzzyName++
 		return true
@@ -258,12 +455,13 @@ func fuzzyNameBroadSearch(index *SoTIndex, normalizedSatName string, sat schema.
 	// Multiple candidates
 	if topCandidates[0].score-topCandidates[1].score >= fuzzyAmbiguityGap {
 		// Clear winner
-		conflicts := DetectConflicts(topCandidates[0].record, sat)
+		conflicts := DetectConflictsWith(topCandidates[0].record, sat, checks)
 		result.Matched = append(result.Matched, MatchedRecord{
-			SoT:       topCandidates[0].record,
-			Satellite: sat,
-			MatchType: "fuzzy_name",
-			Conflicts: conflicts,
+			SoT:        topCandidates[0].record,
+			Satellite:  sat,
+			MatchType:  "fuzzy_name",
+			Conflicts:  conflicts,
+			Candidates: toMatchCandidates(topCandidates),
 		})
 		result.Stats.FuzzyName++
 		return true
@@ -271,11 +469,27 @@ func fuzzyNameBroadSearch(index *SoTIndex, normalizedSatName string, sat schema.
 
 	// Ambiguous
 	result.Matched = append(result.Matched, MatchedRecord{
-		SoT:       topCandidates[0].record,
-		Satellite: sat,
-		MatchType: "fuzzy_ambiguous",
-		Conflicts: DetectConflicts(topCandidates[0].record, sat),
+		SoT:        topCandidates[0].record,
+		Satellite:  sat,
+		MatchType:  "fuzzy_ambiguous",
+		Conflicts:  DetectConflictsWith(topCandidates[0].record, sat, checks),
+		Candidates: toMatchCandidates(topCandidates),
 	})
 	result.Stats.Ambiguous++
 	return true
 }
+
+// toMatchCandidates converts scored candidates into the reviewer-facing candidate list.
+func toMatchCandidates(scored []scoredCandidate) []MatchCandidate {
+	candidates := make([]MatchCandidate, 0, len(scored))
+	for _, sc := range scored {
+		candidates = append(candidates, MatchCandidate{
+			CanonicalID: sc.record.CanonicalID,
+			EmployeeID:  sc.record.EmployeeID,
+			DisplayName: sc.record.DisplayName,
+			Department:  sc.record.Department,
+			Score:       sc.score,
+		})
+	}
+	return candidates
+}
@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+// testSoT returns a small SoT index: two people share a name, one has no email.
+func testSoT() *SoTIndex {
+	return BuildSoTIndex([]*schema.SoTRecord{
+		{CanonicalID: "jane@acme.com", EmployeeID: "E1", DisplayName: "Jane Doe", NormalizedName: "jane doe", Email: "jane@acme.com", Department: "Finance"},
+		{CanonicalID: "alex.kim@acme.com", EmployeeID: "E2", DisplayName: "Alex Kim", NormalizedName: "alex kim", Email: "alex.kim@acme.com", Department: "Sales"},
+		{CanonicalID: "akim@acme.com", EmployeeID: "E3", DisplayName: "Alex Kim", NormalizedName: "alex kim", Email: "akim@acme.com", Department: "Legal"},
+		{CanonicalID: "E4", EmployeeID: "E4", DisplayName: "Priya Raman", NormalizedName: "priya raman"},
+	})
+}
+
+func TestJoinAgainstSoT(t *testing.T) {
+	tests := []struct {
+		name           string
+		sat            schema.SatelliteRecord
+		wantType       string // empty for an orphan
+		wantID         string
+		wantCandidates []string
+	}{
+		{
+			name:     "exact email ignores case",
+			sat:      schema.SatelliteRecord{Email: "JANE@acme.com", DisplayName: "J. Doe"},
+			wantType: "exact_email",
+			wantID:   "jane@acme.com",
+		},
+		{
+			name:     "exact employee ID",
+			sat:      schema.SatelliteRecord{UserId: "E4"},
+			wantType: "exact_id",
+			wantID:   "E4",
+		},
+		{
+			name:           "fuzzy name lists its candidate",
+			sat:            schema.SatelliteRecord{UserId: "praman", DisplayName: "Priya Raman"},
+			wantType:       "fuzzy_name",
+			wantID:         "E4",
+			wantCandidates: []string{"E4"},
+		},
+		{
+			name:           "shared name is ambiguous with every candidate",
+			sat:            schema.SatelliteRecord{UserId: "ak", DisplayName: "Alex Kim"},
+			wantType:       "fuzzy_ambiguous",
+			wantID:         "alex.kim@acme.com",
+			wantCandidates: []string{"alex.kim@acme.com", "akim@acme.com"},
+		},
+		{
+			name: "unknown person is an orphan",
+			sat:  schema.SatelliteRecord{Email: "nobody@acme.com", DisplayName: "Zed Quinn"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JoinAgainstSoT(testSoT(), []schema.SatelliteRecord{tt.sat}, "okta", JoinOptions{})
+			if result.Stats.TotalProcessed != 1 {
+				t.Errorf("TotalProcessed = %d, want 1", result.Stats.TotalProcessed)
+			}
+
+			if tt.wantType == "" {
+				if len(result.Orphans) != 1 || len(result.Matched) != 0 {
+					t.Fatalf("got %d matched and %d orphans, want one orphan", len(result.Matched), len(result.Orphans))
+				}
+				if got := len(result.Orphans[0].AttemptedMatches); got != 3 {
+					t.Errorf("orphan has %d evidence steps, want 3", got)
+				}
+				return
+			}
+
+			if len(result.Matched) != 1 {
+				t.Fatalf("got %d matches, want 1", len(result.Matched))
+			}
+			m := result.Matched[0]
+			if m.MatchType != tt.wantType || m.SoT.CanonicalID != tt.wantID {
+				t.Errorf("matched %s as %s, want %s as %s", m.SoT.CanonicalID, m.MatchType, tt.wantID, tt.wantType)
+			}
+			if len(m.Candidates) != len(tt.wantCandidates) {
+				t.Fatalf("got %d candidates, want %v", len(m.Candidates), tt.wantCandidates)
+			}
+			for i, id := range tt.wantCandidates {
+				if m.Candidates[i].CanonicalID != id {
+					t.Errorf("candidate %d = %s, want %s", i, m.Candidates[i].CanonicalID, id)
+				}
+			}
+		})
+	}
+}
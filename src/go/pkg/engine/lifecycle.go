@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"time"
+
+	"uar/pkg/schema"
+)
+
+// lifecycleFacts are the lifecycle state and date gaps lifecycle rules compare. Each
+// gap is only set when both of its dates are known and the gap is a finding
+// candidate.
+type lifecycleFacts struct {
+	// state is the SoT lifecycle state, or pre_hire for an active or unrecognized
+	// status whose start date is still in the future.
+	state                     schema.LifecycleState
+	hasDaysUntilStart         bool
+	daysUntilStart            int // processing time to a future start date
+	hasLoginBeforeStart       bool
+	loginBeforeStartDays      int // login to the start date, for a login before it
+	hasDaysPastContractEnd    bool
+	daysPastContractEnd       int // contract end to the processing time, once it has passed
+	hasLoginAfterContractEnd  bool
+	loginAfterContractEndDays int // contract end to a login on or after the end cutoff
+}
+
+// newLifecycleFacts computes the lifecycle state and date gaps for a record, reading
+// dates without an offset in loc. A contract end date without a time of day covers
+// that whole day.
+func newLifecycleFacts(sot *schema.SoTRecord, sat schema.SatelliteRecord, processingTimestamp int64, loc *time.Location) lifecycleFacts {
+	var facts lifecycleFacts
+	if sot == nil {
+		return facts
+	}
+	facts.state = sot.LifecycleState
+
+	processedAt := time.UnixMilli(processingTimestamp)
+	loginTime, hasLogin := parseTimestamp(sat.LastLogin, loc)
+
+	if start, ok := parseTimestamp(sot.StartDate, loc); ok {
+		if start.After(processedAt) {
+			facts.hasDaysUntilStart = true
+			facts.daysUntilStart = daysBetween(processedAt, start)
+			if facts.state == "" || facts.state == schema.LifecycleActive {
+				facts.state = schema.LifecyclePreHire
+			}
+		}
+		if hasLogin && loginTime.Before(start) {
+			facts.hasLoginBeforeStart = true
+			facts.loginBeforeStartDays = daysBetween(loginTime, start)
+		}
+	}
+
+	if end, ok := parseTimestamp(sot.ContractEndDate, loc); ok {
+		cutoff := dayCutoff(end)
+		if !processedAt.Before(cutoff) {
+			facts.hasDaysPastContractEnd = true
+			facts.daysPastContractEnd = daysBetween(end, processedAt)
+		}
+		if hasLogin && !loginTime.Before(cutoff) {
+			facts.hasLoginAfterContractEnd = true
+			facts.loginAfterContractEndDays = daysBetween(end, loginTime)
+		}
+	}
+
+	return facts
+}
@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"uar/pkg/schema"
+)
+
+func TestNewLifecycleFacts(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	tests := []struct {
+		name string
+		sot  *schema.SoTRecord
+		sat  schema.SatelliteRecord
+		want lifecycleFacts
+	}{
+		{
+			name: "no SoT record",
+		},
+		{
+			name: "active with past start date",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleActive, StartDate: "2025-01-06"},
+			sat:  schema.SatelliteRecord{LastLogin: "2026-05-30"},
+			want: lifecycleFacts{state: schema.LifecycleActive},
+		},
+		{
+			name: "future start date makes an active person pre-hire",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleActive, StartDate: "2026-06-21"},
+			want: lifecycleFacts{state: schema.LifecyclePreHire, hasDaysUntilStart: true, daysUntilStart: 20},
+		},
+		{
+			name: "future start date makes an unrecognized status pre-hire",
+			sot:  &schema.SoTRecord{StartDate: "2026-06-21"},
+			want: lifecycleFacts{state: schema.LifecyclePreHire, hasDaysUntilStart: true, daysUntilStart: 20},
+		},
+		{
+			name: "future start date keeps a leave state",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleLeave, StartDate: "2026-06-21"},
+			want: lifecycleFacts{state: schema.LifecycleLeave, hasDaysUntilStart: true, daysUntilStart: 20},
+		},
+		{
+			name: "login before the start date",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecyclePreHire, StartDate: "2026-06-21"},
+			sat:  schema.SatelliteRecord{LastLogin: "2026-05-30"},
+			want: lifecycleFacts{state: schema.LifecyclePreHire, hasDaysUntilStart: true, daysUntilStart: 20, hasLoginBeforeStart: true, loginBeforeStartDays: 22},
+		},
+		{
+			name: "contract end in the future",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleActive, ContractEndDate: "2026-06-30"},
+			sat:  schema.SatelliteRecord{LastLogin: "2026-05-30"},
+			want: lifecycleFacts{state: schema.LifecycleActive},
+		},
+		{
+			name: "contract end date covers that whole day",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleActive, ContractEndDate: "2026-06-01"},
+			sat:  schema.SatelliteRecord{LastLogin: "2026-05-31T18:00:00Z"},
+			want: lifecycleFacts{state: schema.LifecycleActive},
+		},
+		{
+			name: "login after the contract ended",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleActive, ContractEndDate: "2026-05-01"},
+			sat:  schema.SatelliteRecord{LastLogin: "2026-05-11T09:00:00Z"},
+			want: lifecycleFacts{state: schema.LifecycleActive, hasDaysPastContractEnd: true, daysPastContractEnd: 31, hasLoginAfterContractEnd: true, loginAfterContractEndDays: 10},
+		},
+		{
+			name: "unparseable dates are ignored",
+			sot:  &schema.SoTRecord{LifecycleState: schema.LifecycleActive, StartDate: "next month", ContractEndDate: "TBD"},
+			sat:  schema.SatelliteRecord{LastLogin: "2026-05-30"},
+			want: lifecycleFacts{state: schema.LifecycleActive},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newLifecycleFacts(tt.sot, tt.sat, now, time.UTC)
+			if got != tt.want {
+				t.Errorf("newLifecycleFacts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLifecycleRules(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	person := func(state schema.LifecycleState, start, contractEnd string) *schema.SoTRecord {
+		return &schema.SoTRecord{CanonicalID: "jane@acme.com", LifecycleState: state, StartDate: start, ContractEndDate: contractEnd}
+	}
+
+	tests := []struct {
+		name      string
+		sot       *schema.SoTRecord
+		sat       schema.SatelliteRecord
+		wantCodes []string
+		wantGap   int
+	}{
+		{
+			name: "joiner provisioned within the window",
+			sot:  person(schema.LifecycleActive, "2026-06-08", ""),
+			sat:  schema.SatelliteRecord{AccountStatus: "active"},
+		},
+		{
+			name:      "joiner provisioned too early",
+			sot:       person(schema.LifecycleActive, "2026-06-21", ""),
+			sat:       schema.SatelliteRecord{AccountStatus: "active"},
+			wantCodes: []string{"pre_hire_provisioned"},
+			wantGap:   20,
+		},
+		{
+			name:      "joiner logged in before the start date",
+			sot:       person(schema.LifecyclePreHire, "2026-06-08", ""),
+			sat:       schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-05-28"},
+			wantCodes: []string{"pre_hire_login"},
+			wantGap:   11,
+		},
+		{
+			name:      "mover on leave logged in",
+			sot:       person(schema.LifecycleLeave, "", ""),
+			sat:       schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-05-28"},
+			wantCodes: []string{"leave_login"},
+		},
+		{
+			name: "mover on leave without recent logins",
+			sot:  person(schema.LifecycleLeave, "", ""),
+			sat:  schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-04-01"},
+		},
+		{
+			name:      "suspended with an active account",
+			sot:       person(schema.LifecycleSuspended, "", ""),
+			sat:       schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-04-01"},
+			wantCodes: []string{"suspended_active"},
+		},
+		{
+			name:      "suspended and logged in",
+			sot:       person(schema.LifecycleSuspended, "", ""),
+			sat:       schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-05-28"},
+			wantCodes: []string{"suspended_login", "suspended_active"},
+		},
+		{
+			name: "leaver contract ended within the grace window",
+			sot:  person(schema.LifecycleActive, "", "2026-05-28"),
+			sat:  schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-05-20"},
+		},
+		{
+			name:      "leaver contract ended and account still active",
+			sot:       person(schema.LifecycleActive, "", "2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-04-30"},
+			wantCodes: []string{"contract_expired_active"},
+			wantGap:   31,
+		},
+		{
+			name:      "leaver logged in after the contract ended",
+			sot:       person(schema.LifecycleActive, "", "2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "disabled", LastLogin: "2026-05-04T10:00:00Z"},
+			wantCodes: []string{"contract_expired_login"},
+			wantGap:   3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := DefaultRiskPolicy().Evaluate(tt.sot, tt.sat, "exact_email", RiskConfig{ProcessingTimestamp: now})
+
+			var codes []string
+			for _, f := range findings {
+				codes = append(codes, f.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Fatalf("Evaluate() codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if len(findings) > 0 && findings[0].GapDays != tt.wantGap {
+				t.Errorf("GapDays = %d, want %d", findings[0].GapDays, tt.wantGap)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"uar/pkg/schema"
+)
+
+// MatchRules configures the optional normalization rules of the join cascade. Each
+// rule is only tried after the plain comparison for its step finds nothing, and is
+// recorded in MatchEvidence.RulesApplied when it is used. The zero value applies none.
+type MatchRules struct {
+	// AliasDomains maps an email domain to the domain it is an alias of, such as
+	// "corp.example.com" to "example.com". Emails on both sides are compared with
+	// alias domains replaced.
+	AliasDomains map[string]string `json:"aliasDomains,omitempty" yaml:"aliasDomains"`
+	// Nicknames maps a nickname to the formal first name the SoT uses, such as "bob"
+	// to "robert". A satellite name is tried again with its nickname replaced.
+	Nicknames map[string]string `json:"nicknames,omitempty" yaml:"nicknames"`
+	// NormalizeIDs compares employee IDs ignoring case, separators, and the leading
+	// zeros of the number, so "E-00123" matches "e123".
+	NormalizeIDs bool `json:"normalizeIds,omitempty" yaml:"normalizeIds"`
+}
+
+// ParseMatchRules parses match rules from JSON or YAML. Domains and nicknames are
+// lowercased.
+func ParseMatchRules(data []byte) (*MatchRules, error) {
+	var rules MatchRules
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return &rules, nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &rules); err != nil {
+			return nil, fmt.Errorf("failed to parse match rules JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal([]byte(trimmed), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse match rules YAML: %w", err)
+	}
+
+	domains := make(map[string]string, len(rules.AliasDomains))
+	for alias, domain := range rules.AliasDomains {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if alias == "" || domain == "" || strings.Contains(alias+domain, "@") {
+			return nil, fmt.Errorf("alias domain %q: domains must be non-empty and without @", alias)
+		}
+		domains[alias] = domain
+	}
+	rules.AliasDomains = domains
+
+	nicknames := make(map[string]string, len(rules.Nicknames))
+	for nickname, name := range rules.Nicknames {
+		nickname = schema.NormalizeName(nickname)
+		name = schema.NormalizeName(name)
+		if nickname == "" || name == "" || strings.Contains(nickname+name, " ") {
+			return nil, fmt.Errorf("nickname %q: nicknames and names must be single words", nickname)
+		}
+		nicknames[nickname] = name
+	}
+	rules.Nicknames = nicknames
+
+	return &rules, nil
+}
+
+// aliasEmail returns a lowercase email with its domain replaced when the domain is
+// an alias.
+func (r *MatchRules) aliasEmail(email string) string {
+	at := strings.LastIndexByte(email, '@')
+	if r == nil || at < 0 {
+		return email
+	}
+	if domain, ok := r.AliasDomains[email[at+1:]]; ok {
+		return email[:at+1] + domain
+	}
+	return email
+}
+
+// expandNickname returns a normalized name with its first word replaced when it is
+// a nickname.
+func (r *MatchRules) expandNickname(normalizedName string) string {
+	if r == nil {
+		return normalizedName
+	}
+	first, rest, _ := strings.Cut(normalizedName, " ")
+	name, ok := r.Nicknames[first]
+	if !ok {
+		return normalizedName
+	}
+	if rest == "" {
+		return name
+	}
+	return name + " " + rest
+}
+
+// normalizeEmployeeID lowercases an ID, drops separators, and strips the leading
+// zeros of the number after any letter prefix: "E-00123" gives "e123".
+func normalizeEmployeeID(id string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(id) {
+		if r == '-' || r == '_' || r == '.' || unicode.IsSpace(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+
+	prefix := strings.IndexFunc(s, unicode.IsDigit)
+	if prefix < 0 {
+		return s
+	}
+	number := strings.TrimLeft(s[prefix:], "0")
+	if number == "" || !unicode.IsDigit(rune(number[0])) {
+		number = "0" + number
+	}
+	return s[:prefix] + number
+}
+
+// ruleIndex holds the SoT lookups for the configured match rules, built once per join.
+// A key shared by several SoT records maps to nil and never matches.
+type ruleIndex struct {
+	rules   *MatchRules
+	byEmail map[string]*schema.SoTRecord
+	byID    map[string]*schema.SoTRecord
+}
+
+// newRuleIndex builds the lookups the rules need; nil rules build none.
+func newRuleIndex(index *SoTIndex, rules *MatchRules) *ruleIndex {
+	ri := &ruleIndex{rules: rules}
+	if rules == nil {
+		return ri
+	}
+
+	add := func(m map[string]*schema.SoTRecord, key string, rec *schema.SoTRecord) {
+		if key == "" {
+			return
+		}
+		if prev, ok := m[key]; ok && prev != rec {
+			m[key] = nil
+			return
+		}
+		m[key] = rec
+	}
+	if len(rules.AliasDomains) > 0 {
+		ri.byEmail = make(map[string]*schema.SoTRecord)
+		for _, rec := range index.Records {
+			add(ri.byEmail, rules.aliasEmail(strings.ToLower(rec.Email)), rec)
+		}
+	}
+	if rules.NormalizeIDs {
+		ri.byID = make(map[string]*schema.SoTRecord)
+		for _, rec := range index.Records {
+			add(ri.byID, normalizeEmployeeID(rec.EmployeeID), rec)
+		}
+	}
+	return ri
+}
+
+// email looks up a lowercase satellite email with alias domains replaced. It returns
+// the compared form and the SoT record, if exactly one has that form.
+func (ri *ruleIndex) email(emailKey string) (string, *schema.SoTRecord) {
+	if ri.byEmail == nil {
+		return emailKey, nil
+	}
+	key := ri.rules.aliasEmail(emailKey)
+	return key, ri.byEmail[key]
+}
+
+// employeeID looks up a satellite user ID by its normalized form. It returns the
+// compared form and the SoT record, if exactly one has that form.
+func (ri *ruleIndex) employeeID(id string) (string, *schema.SoTRecord) {
+	if ri.byID == nil {
+		return id, nil
+	}
+	key := normalizeEmployeeID(id)
+	return key, ri.byID[key]
+}
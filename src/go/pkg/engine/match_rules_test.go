@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestNormalizeEmployeeID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"E-00123", "e123"},
+		{"e123", "e123"},
+		{"00123", "123"},
+		{"000", "0"},
+		{" AB_0042 ", "ab42"},
+		{"jdoe", "jdoe"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeEmployeeID(tt.id); got != tt.want {
+			t.Errorf("normalizeEmployeeID(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestParseMatchRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		check   func(*MatchRules) bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			check: func(r *MatchRules) bool { return len(r.AliasDomains) == 0 && !r.NormalizeIDs },
+		},
+		{
+			name:  "json lowercases domains",
+			input: `{"aliasDomains": {"Corp.Example.com": "Example.com"}, "normalizeIds": true}`,
+			check: func(r *MatchRules) bool {
+				return r.AliasDomains["corp.example.com"] == "example.com" && r.NormalizeIDs
+			},
+		},
+		{
+			name:  "yaml nicknames",
+			input: "nicknames:\n  Bob: Robert\n",
+			check: func(r *MatchRules) bool { return r.Nicknames["bob"] == "robert" },
+		},
+		{name: "domain with at sign", input: `{"aliasDomains": {"a@b.com": "b.com"}}`, wantErr: true},
+		{name: "multi-word nickname", input: `{"nicknames": {"bobby jo": "roberta"}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseMatchRules([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMatchRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !tt.check(rules) {
+				t.Errorf("ParseMatchRules() = %+v", rules)
+			}
+		})
+	}
+}
+
+func TestJoinAgainstSoTMatchRules(t *testing.T) {
+	sot := []*schema.SoTRecord{
+		{CanonicalID: "jane@example.com", EmployeeID: "E00123", DisplayName: "Jane Doe", NormalizedName: "jane doe", Email: "jane@example.com"},
+		{CanonicalID: "robert@example.com", EmployeeID: "E00456", DisplayName: "Robert Smith", NormalizedName: "robert smith", Email: "robert@example.com"},
+	}
+	rules := &MatchRules{
+		AliasDomains: map[string]string{"corp.example.com": "example.com"},
+		Nicknames:    map[string]string{"bob": "robert"},
+		NormalizeIDs: true,
+	}
+
+	tests := []struct {
+		name      string
+		sat       schema.SatelliteRecord
+		rules     *MatchRules
+		wantID    string
+		wantType  string
+		wantKey   string
+		wantRule  string
+		wantSatNF string
+	}{
+		{
+			name:      "alias domain",
+			sat:       schema.SatelliteRecord{Email: "Jane@Corp.Example.com"},
+			rules:     rules,
+			wantID:    "jane@example.com",
+			wantType:  "exact_email",
+			wantKey:   "email",
+			wantRule:  RuleEmailAliasDomain,
+			wantSatNF: "jane@example.com",
+		},
+		{
+			name:      "normalized employee ID",
+			sat:       schema.SatelliteRecord{UserId: "e-123"},
+			rules:     rules,
+			wantID:    "jane@example.com",
+			wantType:  "exact_id",
+			wantKey:   "employeeId",
+			wantRule:  RuleEmployeeIDNormalize,
+			wantSatNF: "e123",
+		},
+		{
+			name:      "nickname",
+			sat:       schema.SatelliteRecord{DisplayName: "Bob Smith"},
+			rules:     rules,
+			wantID:    "robert@example.com",
+			wantType:  "fuzzy_name",
+			wantKey:   "name",
+			wantRule:  RuleNickname,
+			wantSatNF: "robert smith",
+		},
+		{
+			name:  "no rules",
+			sat:   schema.SatelliteRecord{Email: "jane@corp.example.com", UserId: "e-123", DisplayName: "Bob Smith"},
+			rules: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JoinAgainstSoT(BuildSoTIndex(sot), []schema.SatelliteRecord{tt.sat}, "okta", JoinOptions{MatchRules: tt.rules})
+			if tt.wantID == "" {
+				if len(result.Matched) != 0 {
+					t.Fatalf("matched %s, want orphan", result.Matched[0].SoT.CanonicalID)
+				}
+				return
+			}
+			if len(result.Matched) != 1 {
+				t.Fatalf("got %d matches, want 1", len(result.Matched))
+			}
+			m := result.Matched[0]
+			if m.SoT.CanonicalID != tt.wantID || m.MatchType != tt.wantType {
+				t.Errorf("matched %s as %s, want %s as %s", m.SoT.CanonicalID, m.MatchType, tt.wantID, tt.wantType)
+			}
+			i := slices.IndexFunc(m.Evidence, func(e MatchEvidence) bool { return e.Key == tt.wantKey })
+			if i < 0 {
+				t.Fatalf("no %s evidence in %+v", tt.wantKey, m.Evidence)
+			}
+			step := m.Evidence[i]
+			if step.Outcome != EvidenceMatched || !slices.Contains(step.RulesApplied, tt.wantRule) || step.SatelliteNormalized != tt.wantSatNF {
+				t.Errorf("evidence = %+v, want matched with %s and normalized %q", step, tt.wantRule, tt.wantSatNF)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"sort"
+	"strings"
+
+	"uar/pkg/schema"
+)
+
+// Near-miss suggestion defaults.
+const (
+	// DefaultMaxSuggestions is how many near-miss candidates are kept per orphan.
+	DefaultMaxSuggestions = 5
+	// nearMissFloor is the lowest similarity worth showing a reviewer.
+	nearMissFloor = 0.5
+)
+
+// NearMissCandidates returns up to maxSuggestions SoT records that came close to
+// matching an orphan, best score first. Each SoT record is scored on its best key:
+//   - name:       normalized display names
+//   - emailLocal: email local parts
+//   - userId:     satellite user ID against employee ID and email local part
+//
+// Candidates scoring below nearMissFloor are dropped. Ties keep SoT input order.
+func NearMissCandidates(index *SoTIndex, sat schema.SatelliteRecord, maxSuggestions int) []MatchCandidate {
+	if maxSuggestions <= 0 {
+		maxSuggestions = DefaultMaxSuggestions
+	}
+
+	satName := schema.NormalizeName(sat.DisplayName)
+	satLocal := emailLocalPart(sat.Email)
+	satUID := strings.ToLower(strings.TrimSpace(sat.UserId))
+
+	var suggestions []MatchCandidate
+	for _, rec := range index.Records {
+		key, score := "", 0.0
+		consider := func(k, a, b string) {
+			if a == "" || b == "" {
+				return
+			}
+			if s := similarity(a, b); s > score {
+				key, score = k, s
+			}
+		}
+
+		consider("name", satName, rec.NormalizedName)
+		consider("emailLocal", satLocal, emailLocalPart(rec.Email))
+		consider("userId", satUID, strings.ToLower(rec.EmployeeID))
+		consider("userId", satUID, emailLocalPart(rec.Email))
+
+		if score < nearMissFloor {
+			continue
+		}
+		suggestions = append(suggestions, MatchCandidate{
+			CanonicalID: rec.CanonicalID,
+			EmployeeID:  rec.EmployeeID,
+			DisplayName: rec.DisplayName,
+			Department:  rec.Department,
+			Score:       score,
+			Key:         key,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return suggestions
+}
+
+// emailLocalPart returns the lowercase part of an email address before the "@".
+func emailLocalPart(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if at := strings.IndexByte(email, '@'); at >= 0 {
+		return email[:at]
+	}
+	return email
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestNearMissCandidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		sat     schema.SatelliteRecord
+		max     int
+		wantIDs []string
+		wantKey string
+	}{
+		{
+			name:    "user ID equal to an email local part",
+			sat:     schema.SatelliteRecord{UserId: "AKim"},
+			max:     1,
+			wantIDs: []string{"akim@acme.com"},
+			wantKey: "userId",
+		},
+		{
+			name:    "email local part with a typo",
+			sat:     schema.SatelliteRecord{Email: "jnae@partner.com"},
+			wantIDs: []string{"jane@acme.com"},
+			wantKey: "emailLocal",
+		},
+		{
+			name:    "misspelled name keeps SoT order on ties",
+			sat:     schema.SatelliteRecord{DisplayName: "Alex Kym"},
+			wantIDs: []string{"alex.kim@acme.com", "akim@acme.com"},
+			wantKey: "name",
+		},
+		{
+			name: "nothing close",
+			sat:  schema.SatelliteRecord{UserId: "qqqqqqqq", DisplayName: "Zed Quinn"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NearMissCandidates(testSoT(), tt.sat, tt.max)
+			if len(tt.wantIDs) == 0 && len(got) > 0 {
+				t.Fatalf("got suggestions %+v, want none", got)
+			}
+			if len(got) < len(tt.wantIDs) || (tt.max > 0 && len(got) > tt.max) {
+				t.Fatalf("got %d suggestions %+v, want %v first and at most %d", len(got), got, tt.wantIDs, tt.max)
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].CanonicalID != id {
+					t.Errorf("suggestion %d = %s, want %s", i, got[i].CanonicalID, id)
+				}
+			}
+			if len(got) > 0 && got[0].Key != tt.wantKey {
+				t.Errorf("key = %s, want %s", got[0].Key, tt.wantKey)
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i].Score > got[i-1].Score {
+					t.Errorf("suggestions not sorted by score: %+v", got)
+				}
+			}
+		})
+	}
+}
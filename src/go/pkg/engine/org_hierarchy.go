@@ -0,0 +1,243 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"uar/pkg/schema"
+)
+
+// Manager hierarchy finding codes.
+const (
+	// FindingManagerMissing is a manager value that matches nobody in the SoT.
+	FindingManagerMissing = "manager_missing"
+	// FindingManagerAmbiguous is a manager name shared by several current people.
+	FindingManagerAmbiguous = "manager_ambiguous"
+	// FindingManagerTerminated is a current person whose manager is terminated.
+	FindingManagerTerminated = "manager_terminated"
+	// FindingManagerCycle is a person whose reporting chain loops back to them.
+	FindingManagerCycle = "manager_cycle"
+)
+
+// Manager hierarchy finding scores. A broken chain leaves the person's access without
+// an accountable reviewer; a terminated manager is the likeliest to go unnoticed.
+const (
+	managerMissingScore    = 20
+	managerTerminatedScore = 50
+	managerCycleScore      = 40
+)
+
+// OrgHierarchy is the reporting tree of the SoT, keyed by canonical ID. Build it with
+// BuildOrgHierarchy.
+type OrgHierarchy struct {
+	records  map[string]*schema.SoTRecord
+	order    []string            // canonical IDs in SoT input order
+	managers map[string]string   // canonical ID -> resolved manager canonical ID
+	reports  map[string][]string // manager canonical ID -> direct reports in SoT order
+	findings map[string][]RiskFinding
+}
+
+// BuildOrgHierarchy resolves each SoT record's Manager value to a SoT person with
+// ResolvePerson and builds the reporting tree. A manager value naming the person
+// themselves marks the top of the tree. When several records share a canonical ID
+// the first one is used.
+//
+// Current (non-terminated) people get a finding when their manager cannot be
+// resolved, is terminated, or their reporting chain loops back to them.
+func BuildOrgHierarchy(index *SoTIndex) *OrgHierarchy {
+	h := &OrgHierarchy{
+		records:  make(map[string]*schema.SoTRecord),
+		managers: make(map[string]string),
+		reports:  make(map[string][]string),
+		findings: make(map[string][]RiskFinding),
+	}
+
+	for _, rec := range index.Records {
+		if _, seen := h.records[rec.CanonicalID]; seen {
+			continue
+		}
+		h.records[rec.CanonicalID] = rec
+		h.order = append(h.order, rec.CanonicalID)
+	}
+
+	for _, id := range h.order {
+		rec := h.records[id]
+		if rec.Manager == "" {
+			continue
+		}
+
+		manager, candidates := ResolvePerson(index, rec.Manager)
+		switch {
+		case manager == nil && candidates > 1:
+			h.addFinding(rec, RiskFinding{
+				Code:        FindingManagerAmbiguous,
+				Level:       RiskLow,
+				Score:       managerMissingScore,
+				Description: fmt.Sprintf("manager %q matches %d people in the source of truth", rec.Manager, candidates),
+			})
+		case manager == nil:
+			h.addFinding(rec, RiskFinding{
+				Code:        FindingManagerMissing,
+				Level:       RiskLow,
+				Score:       managerMissingScore,
+				Description: fmt.Sprintf("manager %q does not match anyone in the source of truth", rec.Manager),
+			})
+		case manager.CanonicalID == id:
+			// Top of the tree
+		default:
+			h.managers[id] = manager.CanonicalID
+			h.reports[manager.CanonicalID] = append(h.reports[manager.CanonicalID], id)
+
+			if h.records[manager.CanonicalID].LifecycleState == schema.LifecycleTerminated {
+				h.addFinding(rec, RiskFinding{
+					Code:        FindingManagerTerminated,
+					Level:       RiskMedium,
+					Score:       managerTerminatedScore,
+					Description: fmt.Sprintf("manager %s is terminated", h.records[manager.CanonicalID].DisplayName),
+				})
+			}
+		}
+	}
+
+	h.detectCycles()
+	return h
+}
+
+// ResolvePerson looks up a reference to a person, such as a manager value, in the
+// index as an email, then an employee ID, then a normalized name; a name shared by
+// several people resolves to the only current one among them. It returns the record,
+// or nil and the number of people sharing an ambiguous name.
+func ResolvePerson(index *SoTIndex, value string) (*schema.SoTRecord, int) {
+	if rec, ok := index.ByEmail[strings.ToLower(value)]; ok {
+		return rec, 1
+	}
+	if rec, ok := index.ByEmployeeID[value]; ok {
+		return rec, 1
+	}
+
+	var people, current []*schema.SoTRecord
+	seen := make(map[string]bool)
+	for _, rec := range index.ByName[schema.NormalizeName(value)] {
+		if seen[rec.CanonicalID] {
+			continue
+		}
+		seen[rec.CanonicalID] = true
+		people = append(people, rec)
+		if rec.LifecycleState != schema.LifecycleTerminated {
+			current = append(current, rec)
+		}
+	}
+
+	switch {
+	case len(people) == 1:
+		return people[0], 1
+	case len(current) == 1:
+		return current[0], 1
+	}
+	return nil, len(people)
+}
+
+// detectCycles walks each person's chain of managers and flags everyone on a loop.
+// People whose chain merely leads into a loop are not flagged.
+func (h *OrgHierarchy) detectCycles() {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int, len(h.order))
+
+	for _, start := range h.order {
+		var path []string
+		id := start
+		for id != "" && state[id] == unvisited {
+			state[id] = onPath
+			path = append(path, id)
+			id = h.managers[id]
+		}
+
+		if id != "" && state[id] == onPath {
+			// id is on the current path, so the path from id onwards is a loop
+			loopStart := 0
+			for path[loopStart] != id {
+				loopStart++
+			}
+			loop := path[loopStart:]
+			names := make([]string, 0, len(loop)+1)
+			for _, member := range loop {
+				names = append(names, h.records[member].DisplayName)
+			}
+			names = append(names, h.records[id].DisplayName)
+
+			for _, member := range loop {
+				h.addFinding(h.records[member], RiskFinding{
+					Code:        FindingManagerCycle,
+					Level:       RiskMedium,
+					Score:       managerCycleScore,
+					Description: "reporting chain loops: " + strings.Join(names, " → "),
+				})
+			}
+		}
+
+		for _, member := range path {
+			state[member] = done
+		}
+	}
+}
+
+// addFinding records a finding for a current person; terminated people are skipped.
+func (h *OrgHierarchy) addFinding(rec *schema.SoTRecord, finding RiskFinding) {
+	if rec.LifecycleState == schema.LifecycleTerminated {
+		return
+	}
+	h.findings[rec.CanonicalID] = append(h.findings[rec.CanonicalID], finding)
+}
+
+// Record returns the SoT record for a canonical ID, or nil.
+func (h *OrgHierarchy) Record(canonicalID string) *schema.SoTRecord {
+	return h.records[canonicalID]
+}
+
+// ManagerOf returns the canonical ID of a person's resolved manager, or "" for the
+// top of the tree and unresolved managers.
+func (h *OrgHierarchy) ManagerOf(canonicalID string) string {
+	return h.managers[canonicalID]
+}
+
+// DirectReports returns the canonical IDs of a person's direct reports in SoT order.
+func (h *OrgHierarchy) DirectReports(canonicalID string) []string {
+	return h.reports[canonicalID]
+}
+
+// Managers returns the canonical IDs of everyone with at least one direct report,
+// in SoT order.
+func (h *OrgHierarchy) Managers() []string {
+	var managers []string
+	for _, id := range h.order {
+		if len(h.reports[id]) > 0 {
+			managers = append(managers, id)
+		}
+	}
+	return managers
+}
+
+// Subtree returns a person followed by everyone reporting to them directly or
+// indirectly, breadth first. Each person appears once even when the chain loops.
+func (h *OrgHierarchy) Subtree(canonicalID string) []string {
+	subtree := []string{canonicalID}
+	seen := map[string]bool{canonicalID: true}
+	for i := 0; i < len(subtree); i++ {
+		for _, report := range h.reports[subtree[i]] {
+			if !seen[report] {
+				seen[report] = true
+				subtree = append(subtree, report)
+			}
+		}
+	}
+	return subtree
+}
+
+// Findings returns the manager hierarchy findings for a person.
+func (h *OrgHierarchy) Findings(canonicalID string) []RiskFinding {
+	return h.findings[canonicalID]
+}
@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+// orgPerson builds a SoT record whose canonical ID is user at acme.com.
+func orgPerson(user, name, manager string, state schema.LifecycleState) *schema.SoTRecord {
+	email := user + "@acme.com"
+	return &schema.SoTRecord{
+		CanonicalID:    email,
+		EmployeeID:     "E-" + email,
+		DisplayName:    name,
+		NormalizedName: schema.NormalizeName(name),
+		Email:          email,
+		Manager:        manager,
+		LifecycleState: state,
+	}
+}
+
+func TestBuildOrgHierarchyFindings(t *testing.T) {
+	active, terminated := schema.LifecycleActive, schema.LifecycleTerminated
+
+	tests := []struct {
+		name        string
+		records     []*schema.SoTRecord
+		want        map[string][]string // canonical ID -> finding codes
+		wantManager map[string]string   // canonical ID -> resolved manager
+	}{
+		{
+			name: "self-managed user is the top of the tree",
+			records: []*schema.SoTRecord{
+				orgPerson("ceo", "Ceo Smith", "ceo@acme.com", active),
+				orgPerson("jane", "Jane Doe", "Ceo Smith", active),
+			},
+			wantManager: map[string]string{"ceo@acme.com": "", "jane@acme.com": "ceo@acme.com"},
+		},
+		{
+			name: "missing manager",
+			records: []*schema.SoTRecord{
+				orgPerson("jane", "Jane Doe", "nobody@acme.com", active),
+			},
+			want:        map[string][]string{"jane@acme.com": {FindingManagerMissing}},
+			wantManager: map[string]string{"jane@acme.com": ""},
+		},
+		{
+			name: "terminated person with a missing manager is not flagged",
+			records: []*schema.SoTRecord{
+				orgPerson("sam", "Sam Ortiz", "nobody@acme.com", terminated),
+			},
+		},
+		{
+			name: "manager name shared by two current people",
+			records: []*schema.SoTRecord{
+				orgPerson("pat", "Pat Lee", "", active),
+				orgPerson("patricia", "Pat Lee", "", active),
+				orgPerson("jane", "Jane Doe", "Pat Lee", active),
+			},
+			want: map[string][]string{"jane@acme.com": {FindingManagerAmbiguous}},
+		},
+		{
+			name: "shared manager name resolves to the only current person",
+			records: []*schema.SoTRecord{
+				orgPerson("pat", "Pat Lee", "", terminated),
+				orgPerson("patricia", "Pat Lee", "", active),
+				orgPerson("jane", "Jane Doe", "Pat Lee", active),
+			},
+			wantManager: map[string]string{"jane@acme.com": "patricia@acme.com"},
+		},
+		{
+			name: "terminated manager",
+			records: []*schema.SoTRecord{
+				orgPerson("sam", "Sam Ortiz", "", terminated),
+				orgPerson("jane", "Jane Doe", "sam@acme.com", active),
+			},
+			want:        map[string][]string{"jane@acme.com": {FindingManagerTerminated}},
+			wantManager: map[string]string{"jane@acme.com": "sam@acme.com"},
+		},
+		{
+			name: "two people managing each other",
+			records: []*schema.SoTRecord{
+				orgPerson("ann", "Ann Park", "bob@acme.com", active),
+				orgPerson("bob", "Bob Chen", "ann@acme.com", active),
+			},
+			want: map[string][]string{
+				"ann@acme.com": {FindingManagerCycle},
+				"bob@acme.com": {FindingManagerCycle},
+			},
+		},
+		{
+			name: "only the loop is flagged, not the chain leading into it",
+			records: []*schema.SoTRecord{
+				orgPerson("dan", "Dan Wu", "ann@acme.com", active),
+				orgPerson("ann", "Ann Park", "bob@acme.com", active),
+				orgPerson("bob", "Bob Chen", "cat@acme.com", active),
+				orgPerson("cat", "Cat Ruiz", "ann@acme.com", active),
+			},
+			want: map[string][]string{
+				"ann@acme.com": {FindingManagerCycle},
+				"bob@acme.com": {FindingManagerCycle},
+				"cat@acme.com": {FindingManagerCycle},
+			},
+			wantManager: map[string]string{"dan@acme.com": "ann@acme.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := BuildOrgHierarchy(BuildSoTIndex(tt.records))
+
+			for _, rec := range tt.records {
+				var codes []string
+				for _, f := range h.Findings(rec.CanonicalID) {
+					codes = append(codes, f.Code)
+				}
+				if !slices.Equal(codes, tt.want[rec.CanonicalID]) {
+					t.Errorf("Findings(%s) = %v, want %v", rec.CanonicalID, codes, tt.want[rec.CanonicalID])
+				}
+			}
+			for id, want := range tt.wantManager {
+				if got := h.ManagerOf(id); got != want {
+					t.Errorf("ManagerOf(%s) = %q, want %q", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestOrgHierarchySubtree(t *testing.T) {
+	h := BuildOrgHierarchy(BuildSoTIndex([]*schema.SoTRecord{
+		orgPerson("ceo", "Ceo Smith", "", schema.LifecycleActive),
+		orgPerson("ann", "Ann Park", "ceo@acme.com", schema.LifecycleActive),
+		orgPerson("bob", "Bob Chen", "ann@acme.com", schema.LifecycleActive),
+		orgPerson("cat", "Cat Ruiz", "ceo@acme.com", schema.LifecycleActive),
+		orgPerson("dan", "Dan Wu", "eve@acme.com", schema.LifecycleActive),
+		orgPerson("eve", "Eve Kim", "dan@acme.com", schema.LifecycleActive),
+	}))
+
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{"ceo@acme.com", []string{"ceo@acme.com", "ann@acme.com", "cat@acme.com", "bob@acme.com"}},
+		{"bob@acme.com", []string{"bob@acme.com"}},
+		{"dan@acme.com", []string{"dan@acme.com", "eve@acme.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := h.Subtree(tt.id); !slices.Equal(got, tt.want) {
+				t.Errorf("Subtree(%s) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+
+	if got, want := h.Managers(), []string{"ceo@acme.com", "ann@acme.com", "dan@acme.com", "eve@acme.com"}; !slices.Equal(got, want) {
+		t.Errorf("Managers() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"uar/pkg/schema"
+)
+
+// OverrideKind is what a reviewer confirmed a satellite account to be.
+type OverrideKind string
+
+const (
+	// OverridePerson links the account to the SoT person named by CanonicalID.
+	OverridePerson OverrideKind = "person"
+	// OverrideServiceAccount marks the account as a service account owned by CanonicalID.
+	OverrideServiceAccount OverrideKind = "service_account"
+	// OverrideNotAPerson marks the account as belonging to no person.
+	OverrideNotAPerson OverrideKind = "not_a_person"
+)
+
+// LinkOverride is a reviewer-confirmed decision about one satellite account.
+// SatelliteKey is compared case-insensitively against the account's email, then user ID.
+type LinkOverride struct {
+	System       string       `json:"system"`
+	SatelliteKey string       `json:"satelliteKey"`
+	Kind         OverrideKind `json:"kind"`
+	CanonicalID  string       `json:"canonicalId,omitempty"` // person, or owner of a service account
+	ConfirmedBy  string       `json:"confirmedBy"`
+	ConfirmedAt  string       `json:"confirmedAt"` // RFC 3339
+	Note         string       `json:"note,omitempty"`
+}
+
+// OverrideTable holds reviewer-confirmed link overrides, carried from one review
+// cycle to the next so confirmed links are not re-flagged.
+type OverrideTable struct {
+	Overrides []LinkOverride `json:"overrides"`
+	byKey     map[string]*LinkOverride
+}
+
+// NewOverrideTable indexes the given overrides by system and satellite key.
+// When two overrides share a key, the later one wins.
+func NewOverrideTable(overrides []LinkOverride) *OverrideTable {
+	table := &OverrideTable{
+		Overrides: overrides,
+		byKey:     make(map[string]*LinkOverride, len(overrides)),
+	}
+	for i := range overrides {
+		ov := &overrides[i]
+		table.byKey[overrideKey(ov.System, ov.SatelliteKey)] = ov
+	}
+	return table
+}
+
+// ParseOverrideTable parses an override table from JSON and validates each entry.
+// An empty input yields an empty table.
+func ParseOverrideTable(data []byte) (*OverrideTable, error) {
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return NewOverrideTable(nil), nil
+	}
+
+	var raw struct {
+		Overrides []LinkOverride `json:"overrides"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse override table: %w", err)
+	}
+
+	for i, ov := range raw.Overrides {
+		if ov.System == "" || ov.SatelliteKey == "" {
+			return nil, fmt.Errorf("override %d: system and satelliteKey are required", i)
+		}
+		if strings.TrimSpace(ov.ConfirmedBy) == "" || strings.TrimSpace(ov.ConfirmedAt) == "" {
+			return nil, fmt.Errorf("override %d: confirmedBy and confirmedAt are required", i)
+		}
+		switch ov.Kind {
+		case OverridePerson, OverrideServiceAccount:
+			if ov.CanonicalID == "" {
+				return nil, fmt.Errorf("override %d: kind %q requires canonicalId", i, ov.Kind)
+			}
+		case OverrideNotAPerson:
+		default:
+			return nil, fmt.Errorf("override %d: unknown kind %q", i, ov.Kind)
+		}
+	}
+
+	return NewOverrideTable(raw.Overrides), nil
+}
+
+// Lookup returns the override for a satellite record, or nil if none applies.
+func (t *OverrideTable) Lookup(sat schema.SatelliteRecord) *LinkOverride {
+	if t == nil || len(t.byKey) == 0 {
+		return nil
+	}
+	for _, key := range []string{sat.Email, sat.UserId} {
+		if key == "" {
+			continue
+		}
+		if ov, ok := t.byKey[overrideKey(sat.SourceFile, key)]; ok {
+			return ov
+		}
+	}
+	return nil
+}
+
+// overrideKey builds the case-insensitive lookup key for a system and satellite key.
+func overrideKey(system, satelliteKey string) string {
+	return strings.ToLower(strings.TrimSpace(system)) + "\x00" + strings.ToLower(strings.TrimSpace(satelliteKey))
+}
+
+// overrideEvidence records an applied override in the evidence trail.
+func overrideEvidence(ov *LinkOverride, sat schema.SatelliteRecord, sotRec *schema.SoTRecord) MatchEvidence {
+	step := MatchEvidence{
+		Key:                 "override",
+		SatelliteValue:      ov.SatelliteKey,
+		SatelliteNormalized: strings.ToLower(ov.SatelliteKey),
+		RulesApplied:        []string{RuleManualOverride},
+		Outcome:             EvidenceNoMatch,
+		Reason:              string(ov.Kind) + " confirmed by " + ov.ConfirmedBy + " at " + ov.ConfirmedAt,
+	}
+	if sotRec != nil {
+		step.SoTValue = sotRec.DisplayName
+		step.SoTNormalized = sotRec.CanonicalID
+		step.Score = 1.0
+		step.Outcome = EvidenceMatched
+	}
+	return step
+}
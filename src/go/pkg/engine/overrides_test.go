@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestJoinAgainstSoTOverrides(t *testing.T) {
+	overrides := NewOverrideTable([]LinkOverride{
+		{System: "OKTA", SatelliteKey: "JD-Contractor", Kind: OverridePerson, CanonicalID: "jane@acme.com"},
+		{System: "okta", SatelliteKey: "printer@acme.com", Kind: OverrideNotAPerson},
+		{System: "okta", SatelliteKey: "etl", Kind: OverrideServiceAccount, CanonicalID: "gone@acme.com"},
+		{System: "okta", SatelliteKey: "reports-bot", Kind: OverrideServiceAccount, CanonicalID: "jane@acme.com"},
+		{System: "okta", SatelliteKey: "jane@acme.com", Kind: OverridePerson, CanonicalID: "left@acme.com"},
+	})
+
+	tests := []struct {
+		name      string
+		sat       schema.SatelliteRecord
+		wantType  string // match type, or "non_human" or "orphan"
+		wantOwner string
+	}{
+		{"person override by user ID ignores case", schema.SatelliteRecord{UserId: "jd-contractor", DisplayName: "Contractor 7", SourceFile: "okta"}, "manual_override", ""},
+		{"override in another system does not apply", schema.SatelliteRecord{UserId: "jd-contractor", DisplayName: "Contractor 7", SourceFile: "github"}, "orphan", ""},
+		{"not a person", schema.SatelliteRecord{Email: "printer@acme.com", DisplayName: "Jane Doe", SourceFile: "okta"}, "non_human", ""},
+		{"service account with a departed owner", schema.SatelliteRecord{UserId: "etl", SourceFile: "okta"}, "non_human", ""},
+		{"service account keeps its class and links its owner", schema.SatelliteRecord{UserId: "reports-bot", DisplayName: "Jane Doe", SourceFile: "okta"}, "non_human", "jane@acme.com"},
+		{"person no longer in SoT falls through to the cascade", schema.SatelliteRecord{Email: "jane@acme.com", SourceFile: "okta"}, "exact_email", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := JoinAgainstSoT(testSoT(), []schema.SatelliteRecord{tt.sat}, tt.sat.SourceFile, JoinOptions{Overrides: overrides})
+
+			var got string
+			var evidence []MatchEvidence
+			switch {
+			case len(result.Matched) == 1:
+				got, evidence = result.Matched[0].MatchType, result.Matched[0].Evidence
+			case len(result.NonHuman) == 1:
+				got, evidence = "non_human", result.NonHuman[0].AttemptedMatches
+			case len(result.Orphans) == 1:
+				got, evidence = "orphan", result.Orphans[0].AttemptedMatches
+			}
+			if got != tt.wantType {
+				t.Fatalf("got %q, want %q", got, tt.wantType)
+			}
+			if len(result.NonHuman) == 1 {
+				nh := result.NonHuman[0]
+				if nh.Classification.Class == AccountHuman {
+					t.Errorf("classification = %q, want a non-human class", nh.Classification.Class)
+				}
+				var owner string
+				if nh.Owner != nil {
+					owner = nh.Owner.CanonicalID
+				}
+				if owner != tt.wantOwner {
+					t.Errorf("owner = %q, want %q", owner, tt.wantOwner)
+				}
+			}
+			if tt.wantType != "orphan" && (len(evidence) == 0 || evidence[0].Key != "override") {
+				t.Errorf("evidence = %+v, want the override step first", evidence)
+			}
+		})
+	}
+}
+
+func TestParseOverrideTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{name: "empty", input: " ", want: 0},
+		{name: "valid", input: `{"overrides": [{"system": "okta", "satelliteKey": "a", "kind": "not_a_person", "confirmedBy": "r@acme.com", "confirmedAt": "2025-01-02T00:00:00Z"}]}`, want: 1},
+		{name: "missing confirmedBy", input: `{"overrides": [{"system": "okta", "satelliteKey": "a", "kind": "not_a_person", "confirmedAt": "2025-01-02T00:00:00Z"}]}`, wantErr: true},
+		{name: "blank confirmedAt", input: `{"overrides": [{"system": "okta", "satelliteKey": "a", "kind": "not_a_person", "confirmedBy": "r@acme.com", "confirmedAt": " "}]}`, wantErr: true},
+		{name: "missing key", input: `{"overrides": [{"system": "okta", "kind": "not_a_person"}]}`, wantErr: true},
+		{name: "person without canonical ID", input: `{"overrides": [{"system": "okta", "satelliteKey": "a", "kind": "person"}]}`, wantErr: true},
+		{name: "unknown kind", input: `{"overrides": [{"system": "okta", "satelliteKey": "a", "kind": "robot"}]}`, wantErr: true},
+		{name: "not JSON", input: `overrides: []`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ParseOverrideTable([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOverrideTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(table.Overrides) != tt.want {
+				t.Errorf("got %d overrides, want %d", len(table.Overrides), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"uar/pkg/schema"
+)
+
+// FindingPeerOutlier is the finding code for access that is unusual for a peer group.
+const FindingPeerOutlier = "peer_outlier"
+
+// peerOutlierScore is the score of a peer outlier finding. Unusual access is a
+// prompt for the reviewer rather than a risk on its own.
+const peerOutlierScore = 30
+
+// PeerOutlierConfig configures FindPeerOutliers. It is loaded as RiskConfig.PeerOutliers.
+type PeerOutlierConfig struct {
+	// Threshold is the prevalence (0-1) below which access is an outlier; 0 means 0.1.
+	Threshold float64 `json:"threshold" yaml:"threshold"`
+	// MinGroupSize skips peer groups too small for prevalence to mean anything; 0 means 5.
+	MinGroupSize int `json:"minGroupSize" yaml:"minGroupSize"`
+}
+
+// validate checks the configuration's ranges.
+func (c PeerOutlierConfig) validate() error {
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("peerOutliers threshold must be between 0 and 1")
+	}
+	if c.MinGroupSize < 0 {
+		return fmt.Errorf("peerOutliers minGroupSize must not be negative")
+	}
+	return nil
+}
+
+// PeerOutlier is one grant held by few people in its holder's peer group.
+// GroupSize is the number of peers with an account in System.
+type PeerOutlier struct {
+	CanonicalID string  `json:"canonicalId"`
+	System      string  `json:"system"`
+	Grant       string  `json:"grant"`
+	SourceRow   int     `json:"sourceRow"`
+	PeerGroup   string  `json:"peerGroup"`
+	GroupSize   int     `json:"groupSize"`
+	Holders     int     `json:"holders"`
+	Prevalence  float64 `json:"prevalence"`
+}
+
+// Finding converts the outlier into a risk finding for its report entry.
+func (o PeerOutlier) Finding() RiskFinding {
+	return RiskFinding{
+		Code:  FindingPeerOutlier,
+		Level: RiskLow,
+		Score: peerOutlierScore,
+		Description: fmt.Sprintf("%q in %s is held by %d of %d peers in %s with %s accounts (%.0f%%)",
+			o.Grant, o.System, o.Holders, o.GroupSize, o.PeerGroup, o.System, o.Prevalence*100),
+		PeerGroupSize: o.GroupSize,
+		Prevalence:    o.Prevalence,
+	}
+}
+
+// FindPeerOutliers compares each matched person's grants with those of their peers in
+// the same system. A peer group is the SoT department and title (department alone when
+// the title is blank). A grant's prevalence is the share of the group's people with an
+// account in that system who hold it; peers without an account there say nothing about
+// what the system normally grants. Terminated people are neither peers nor checked.
+// Each role and entitlement value in a system is a grant, and a grant held by less than
+// Threshold of its system's peers is an outlier. Groups with fewer than MinGroupSize
+// peers in a system are not checked there. Outliers are returned in join order.
+func FindPeerOutliers(results []*JoinResult, cfg PeerOutlierConfig) []PeerOutlier {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 0.1
+	}
+	if cfg.MinGroupSize <= 0 {
+		cfg.MinGroupSize = 5
+	}
+
+	// Distinct peers with an account in each system, and distinct holders of each grant
+	members := make(map[string]map[string]bool)
+	holders := make(map[string]map[string]bool)
+	groupName := make(map[string]string)
+	add := func(sets map[string]map[string]bool, key, canonicalID string) {
+		if sets[key] == nil {
+			sets[key] = make(map[string]bool)
+		}
+		sets[key][canonicalID] = true
+	}
+	for _, jr := range results {
+		for _, m := range jr.Matched {
+			key, name := peerGroup(m.SoT.Department, m.SoT.Title)
+			if key == "" || !isPeer(m.SoT) {
+				continue
+			}
+			groupName[key] = name
+			add(members, key+"\x00"+strings.ToLower(m.Satellite.SourceFile), m.SoT.CanonicalID)
+			for _, g := range matchedGrants(m) {
+				add(holders, key+"\x00"+grantKey(m.Satellite.SourceFile, g), m.SoT.CanonicalID)
+			}
+		}
+	}
+
+	var outliers []PeerOutlier
+	for _, jr := range results {
+		for _, m := range jr.Matched {
+			key, _ := peerGroup(m.SoT.Department, m.SoT.Title)
+			if key == "" || !isPeer(m.SoT) {
+				continue
+			}
+			size := len(members[key+"\x00"+strings.ToLower(m.Satellite.SourceFile)])
+			if size < cfg.MinGroupSize {
+				continue
+			}
+			for _, g := range matchedGrants(m) {
+				n := len(holders[key+"\x00"+grantKey(m.Satellite.SourceFile, g)])
+				prevalence := float64(n) / float64(size)
+				if prevalence >= cfg.Threshold {
+					continue
+				}
+				outliers = append(outliers, PeerOutlier{
+					CanonicalID: m.SoT.CanonicalID,
+					System:      m.Satellite.SourceFile,
+					Grant:       g,
+					SourceRow:   m.Satellite.SourceRow,
+					PeerGroup:   groupName[key],
+					GroupSize:   size,
+					Holders:     n,
+					Prevalence:  prevalence,
+				})
+			}
+		}
+	}
+
+	return outliers
+}
+
+// peerGroup returns the lookup key and display name of a department/title peer group.
+func peerGroup(department, title string) (string, string) {
+	department = strings.TrimSpace(department)
+	title = strings.TrimSpace(title)
+	if department == "" {
+		return "", ""
+	}
+	if title == "" {
+		return strings.ToLower(department), department
+	}
+	return strings.ToLower(department + "\x00" + title), department + " / " + title
+}
+
+// isPeer reports whether a person counts toward peer groups. Terminated people do not
+// define what is normal, and their access is flagged by termination rules instead.
+func isPeer(rec *schema.SoTRecord) bool {
+	return rec.LifecycleState != schema.LifecycleTerminated
+}
+
+// matchedGrants returns the distinct role and entitlement values of a matched account.
+func matchedGrants(m MatchedRecord) []string {
+	var grants []string
+	seen := make(map[string]bool)
+	for _, value := range []string{m.Satellite.Role, m.Satellite.Entitlement} {
+		for _, g := range SplitGrants(value) {
+			if k := strings.ToLower(g); !seen[k] {
+				seen[k] = true
+				grants = append(grants, g)
+			}
+		}
+	}
+	return grants
+}
+
+// grantKey identifies a grant case-insensitively within its system.
+func grantKey(system, grant string) string {
+	return strings.ToLower(system) + "\x00" + strings.ToLower(grant)
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestFindPeerOutliers(t *testing.T) {
+	tests := []struct {
+		name          string
+		sapPeers      int // active Finance analysts with a plain sap account
+		rareHolders   int // active Finance analysts whose sap account also grants "Rare"
+		oktaOnly      int // active Finance analysts with no sap account
+		terminatedSap int // terminated Finance analysts holding sap "Viewer"
+		cfg           PeerOutlierConfig
+		wantOutliers  int
+		wantGroupSize int
+	}{
+		{name: "prevalence at the threshold is not an outlier", sapPeers: 9, rareHolders: 1},
+		{name: "prevalence below the threshold", sapPeers: 10, rareHolders: 1, wantOutliers: 1, wantGroupSize: 11},
+		{name: "configured threshold", sapPeers: 10, rareHolders: 1, cfg: PeerOutlierConfig{Threshold: 0.05}},
+		{name: "group below the default minimum size", sapPeers: 3, rareHolders: 1, cfg: PeerOutlierConfig{Threshold: 0.5}},
+		{name: "configured minimum group size", sapPeers: 3, rareHolders: 1, cfg: PeerOutlierConfig{Threshold: 0.5, MinGroupSize: 4}, wantOutliers: 1, wantGroupSize: 4},
+		{name: "peers without an account in the system are not in the denominator", sapPeers: 3, rareHolders: 2, oktaOnly: 20},
+		{name: "peers without an account do not make a small group large enough", sapPeers: 1, rareHolders: 1, oktaOnly: 20, cfg: PeerOutlierConfig{Threshold: 0.5}},
+		{name: "terminated holders are not peers", sapPeers: 4, rareHolders: 1, terminatedSap: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sap := &JoinResult{System: "sap"}
+			okta := &JoinResult{System: "okta"}
+			n := 0
+			person := func(state schema.LifecycleState) *schema.SoTRecord {
+				n++
+				id := fmt.Sprintf("p%d@acme.com", n)
+				return &schema.SoTRecord{CanonicalID: id, Email: id, Department: "Finance", Title: "Analyst", LifecycleState: state}
+			}
+			account := func(jr *JoinResult, rec *schema.SoTRecord, entitlement string) {
+				jr.Matched = append(jr.Matched, MatchedRecord{
+					SoT:       rec,
+					Satellite: schema.SatelliteRecord{Email: rec.Email, Role: "Viewer", Entitlement: entitlement, SourceFile: jr.System, SourceRow: len(jr.Matched) + 1},
+					MatchType: "exact_email",
+				})
+			}
+			for i := 0; i < tt.rareHolders; i++ {
+				account(sap, person(schema.LifecycleActive), "Rare")
+			}
+			for i := 0; i < tt.sapPeers; i++ {
+				account(sap, person(schema.LifecycleActive), "")
+			}
+			for i := 0; i < tt.terminatedSap; i++ {
+				account(sap, person(schema.LifecycleTerminated), "")
+			}
+			for i := 0; i < tt.oktaOnly; i++ {
+				account(okta, person(schema.LifecycleActive), "")
+			}
+
+			outliers := FindPeerOutliers([]*JoinResult{sap, okta}, tt.cfg)
+
+			if len(outliers) != tt.wantOutliers*tt.rareHolders {
+				t.Fatalf("FindPeerOutliers() = %+v, want %d outliers", outliers, tt.wantOutliers*tt.rareHolders)
+			}
+			for _, o := range outliers {
+				if o.Grant != "Rare" || o.System != "sap" || o.GroupSize != tt.wantGroupSize || o.Holders != tt.rareHolders {
+					t.Errorf("outlier = %+v, want Rare in sap held by %d of %d", o, tt.rareHolders, tt.wantGroupSize)
+				}
+			}
+		})
+	}
+}
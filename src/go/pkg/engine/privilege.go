@@ -0,0 +1,295 @@
+package engine
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPrivilegeCatalogText is the built-in privilege catalog.
+//
+//go:embed default_privilege_catalog.yaml
+var DefaultPrivilegeCatalogText string
+
+// PrivilegeTier is how much power a grant carries.
+type PrivilegeTier string
+
+const (
+	TierStandard   PrivilegeTier = "standard"
+	TierElevated   PrivilegeTier = "elevated"
+	TierAdmin      PrivilegeTier = "admin"
+	TierSuperAdmin PrivilegeTier = "super_admin"
+)
+
+// privilegeTierRank orders tiers from least to most privileged.
+var privilegeTierRank = map[PrivilegeTier]int{
+	TierStandard:   0,
+	TierElevated:   1,
+	TierAdmin:      2,
+	TierSuperAdmin: 3,
+}
+
+// PrivilegeMatchType is how a privilege rule compares against a grant.
+type PrivilegeMatchType string
+
+const (
+	PrivilegeExact   PrivilegeMatchType = "exact"
+	PrivilegeRegex   PrivilegeMatchType = "regex"
+	PrivilegeKeyword PrivilegeMatchType = "keyword"
+)
+
+// PrivilegeRule maps grants matching Value to a tier and score weight.
+type PrivilegeRule struct {
+	Match  PrivilegeMatchType `json:"match" yaml:"match"`
+	Value  string             `json:"value" yaml:"value"`
+	Tier   PrivilegeTier      `json:"tier" yaml:"tier"`
+	Weight int                `json:"weight" yaml:"weight"`
+
+	re     *regexp.Regexp
+	tokens []string
+}
+
+// PrivilegeCatalog holds privilege rules keyed by system. The "*" key applies to
+// every system.
+type PrivilegeCatalog struct {
+	Systems map[string][]PrivilegeRule `json:"systems" yaml:"systems"`
+
+	keys []string // system keys in sorted order, for deterministic lookups
+}
+
+// PrivilegeAssessment is the privilege of an account's most powerful grant.
+type PrivilegeAssessment struct {
+	Tier   PrivilegeTier `json:"tier"`
+	Weight int           `json:"weight,omitempty"`
+	Grant  string        `json:"grant,omitempty"`
+	// Rule describes the catalog rule that matched, e.g. `sap: exact "SAP_ALL"`.
+	Rule string `json:"rule,omitempty"`
+}
+
+// Outranks reports whether t is a more privileged tier than other.
+func (t PrivilegeTier) Outranks(other PrivilegeTier) bool {
+	return privilegeTierRank[t] > privilegeTierRank[other]
+}
+
+// Privileged reports whether the tier is elevated or higher.
+func (a PrivilegeAssessment) Privileged() bool {
+	return privilegeTierRank[a.Tier] >= privilegeTierRank[TierElevated]
+}
+
+// defaultPrivilegeCatalog is parsed from DefaultPrivilegeCatalogText and used when no
+// catalog is configured.
+var defaultPrivilegeCatalog = mustParsePrivilegeCatalog(DefaultPrivilegeCatalogText)
+
+// DefaultPrivilegeCatalog returns the built-in privilege catalog.
+func DefaultPrivilegeCatalog() *PrivilegeCatalog {
+	return defaultPrivilegeCatalog
+}
+
+// ParsePrivilegeCatalog parses a privilege catalog from JSON or YAML and validates it.
+func ParsePrivilegeCatalog(data []byte) (*PrivilegeCatalog, error) {
+	var catalog PrivilegeCatalog
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, fmt.Errorf("privilege catalog is empty")
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &catalog); err != nil {
+			return nil, fmt.Errorf("failed to parse privilege catalog JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal([]byte(trimmed), &catalog); err != nil {
+		return nil, fmt.Errorf("failed to parse privilege catalog YAML: %w", err)
+	}
+
+	if err := catalog.compile(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+// compile validates the rules and prepares their patterns, keywords, and the sorted
+// system keys.
+func (c *PrivilegeCatalog) compile() error {
+	c.keys = c.keys[:0]
+	for system, rules := range c.Systems {
+		for i := range rules {
+			rule := &rules[i]
+			if _, ok := privilegeTierRank[rule.Tier]; !ok {
+				return fmt.Errorf("privilege rule %s[%d]: unknown tier %q", system, i, rule.Tier)
+			}
+			if rule.Value == "" {
+				return fmt.Errorf("privilege rule %s[%d]: value is required", system, i)
+			}
+			if rule.Weight < 0 {
+				return fmt.Errorf("privilege rule %s[%d]: weight must not be negative", system, i)
+			}
+			switch rule.Match {
+			case PrivilegeExact:
+			case PrivilegeRegex:
+				re, err := regexp.Compile("(?i)" + rule.Value)
+				if err != nil {
+					return fmt.Errorf("privilege rule %s[%d]: %w", system, i, err)
+				}
+				rule.re = re
+			case PrivilegeKeyword:
+				rule.tokens = grantTokens(rule.Value)
+			default:
+				return fmt.Errorf("privilege rule %s[%d]: unknown match %q", system, i, rule.Match)
+			}
+		}
+		c.keys = append(c.keys, system)
+	}
+	sort.Strings(c.keys)
+
+	return nil
+}
+
+// mustParsePrivilegeCatalog is ParsePrivilegeCatalog for catalogs known to be valid.
+func mustParsePrivilegeCatalog(text string) *PrivilegeCatalog {
+	catalog, err := ParsePrivilegeCatalog([]byte(text))
+	if err != nil {
+		panic(err)
+	}
+	return catalog
+}
+
+// Lookup returns the privilege of an account's most powerful role or entitlement
+// value in a system: the highest tier, then the highest weight, then the first rule
+// in catalog order. An account no rule matches is standard. A nil catalog uses the
+// default catalog.
+func (c *PrivilegeCatalog) Lookup(system, role, entitlement string) PrivilegeAssessment {
+	if c == nil {
+		c = defaultPrivilegeCatalog
+	}
+
+	best := PrivilegeAssessment{Tier: TierStandard}
+	systemTokens := grantTokens(system)
+
+	for _, key := range c.keys {
+		if !catalogKeyApplies(key, system, systemTokens) {
+			continue
+		}
+		for _, value := range []string{role, entitlement} {
+			for _, grant := range SplitGrants(value) {
+				tokens := grantTokens(grant)
+				for _, rule := range c.Systems[key] {
+					if !rule.matches(grant, tokens) {
+						continue
+					}
+					rank, bestRank := privilegeTierRank[rule.Tier], privilegeTierRank[best.Tier]
+					if rank < bestRank || (rank == bestRank && rule.Weight <= best.Weight) {
+						continue
+					}
+					best = PrivilegeAssessment{
+						Tier:   rule.Tier,
+						Weight: rule.Weight,
+						Grant:  grant,
+						Rule:   fmt.Sprintf("%s: %s %q", key, rule.Match, rule.Value),
+					}
+				}
+			}
+		}
+	}
+
+	return best
+}
+
+// BestSystemKey returns the key that best covers a system name, matching keys like
+// privilege catalog keys: an exact key first, then the covering key with the most
+// words. It returns "" when no key applies; "*" is never chosen.
+func BestSystemKey(keys []string, system string) string {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	systemTokens := grantTokens(system)
+	best, bestLen := "", 0
+	for _, key := range sorted {
+		if key == system {
+			return key
+		}
+		if key == "*" || !catalogKeyApplies(key, system, systemTokens) {
+			continue
+		}
+		if n := len(grantTokens(key)); n > bestLen {
+			best, bestLen = key, n
+		}
+	}
+	return best
+}
+
+// catalogKeyApplies reports whether a catalog system key covers a system name.
+func catalogKeyApplies(key, system string, systemTokens []string) bool {
+	if key == "*" || strings.EqualFold(key, system) {
+		return true
+	}
+	keyTokens := grantTokens(key)
+	return len(keyTokens) > 0 && containsTokens(systemTokens, keyTokens)
+}
+
+// matches reports whether a single grant value satisfies the rule.
+func (r PrivilegeRule) matches(grant string, tokens []string) bool {
+	switch r.Match {
+	case PrivilegeExact:
+		return strings.EqualFold(strings.TrimSpace(grant), strings.TrimSpace(r.Value))
+	case PrivilegeRegex:
+		return r.re != nil && r.re.MatchString(grant)
+	case PrivilegeKeyword:
+		return len(r.tokens) > 0 && containsTokens(tokens, r.tokens)
+	}
+	return false
+}
+
+// grantTokens splits a value into lowercase words at non-alphanumeric characters
+// and camelCase boundaries: "IAMFullAccess" gives iam, full, access.
+func grantTokens(value string) []string {
+	var tokens []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			tokens = append(tokens, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(value)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prev := cur[len(cur)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+
+	return tokens
+}
+
+// containsTokens reports whether needle appears as a consecutive run in haystack.
+func containsTokens(haystack, needle []string) bool {
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		match := true
+		for j := range needle {
+			if haystack[i+j] != needle[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
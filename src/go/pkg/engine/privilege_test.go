@@ -0,0 +1,80 @@
+package engine
+
+import "testing"
+
+func TestPrivilegeCatalogLookup(t *testing.T) {
+	tests := []struct {
+		name        string
+		system      string
+		role        string
+		entitlement string
+		wantTier    PrivilegeTier
+		wantGrant   string
+	}{
+		{"no grants", "okta", "", "", TierStandard, ""},
+		{"ordinary role", "okta", "Engineer", "", TierStandard, ""},
+		{"keyword in any system", "github", "IT-Admin", "", TierAdmin, "IT-Admin"},
+		{"keyword needs whole words", "jira", "Systems Analyst", "", TierStandard, ""},
+		{"exact match ignores case", "sap", "sap_all", "", TierSuperAdmin, "sap_all"},
+		{"system key covers a longer name", "sap_export", "SAP_NEW", "", TierAdmin, "SAP_NEW"},
+		{"system rules do not leak", "okta", "SAP_ALL", "", TierStandard, ""},
+		{"regex rule", "aws", "S3FullAccess", "", TierElevated, "S3FullAccess"},
+		{"highest tier across grants", "aws", "PowerUserAccess; S3FullAccess", "AdministratorAccess", TierSuperAdmin, "AdministratorAccess"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DefaultPrivilegeCatalog().Lookup(tt.system, tt.role, tt.entitlement)
+			if got.Tier != tt.wantTier || got.Grant != tt.wantGrant {
+				t.Errorf("Lookup() = %s %q (%s), want %s %q", got.Tier, got.Grant, got.Rule, tt.wantTier, tt.wantGrant)
+			}
+		})
+	}
+}
+
+func TestParsePrivilegeCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "YAML", input: "systems:\n  hr:\n    - {match: exact, value: Payroll, tier: elevated}\n"},
+		{name: "JSON", input: `{"systems": {"hr": [{"match": "keyword", "value": "payroll", "tier": "admin"}]}}`},
+		{name: "empty", input: " ", wantErr: true},
+		{name: "unknown tier", input: `{"systems": {"hr": [{"match": "exact", "value": "a", "tier": "god"}]}}`, wantErr: true},
+		{name: "unknown match", input: `{"systems": {"hr": [{"match": "glob", "value": "a", "tier": "admin"}]}}`, wantErr: true},
+		{name: "missing value", input: `{"systems": {"hr": [{"match": "exact", "tier": "admin"}]}}`, wantErr: true},
+		{name: "bad regex", input: `{"systems": {"hr": [{"match": "regex", "value": "(", "tier": "admin"}]}}`, wantErr: true},
+		{name: "negative weight", input: `{"systems": {"hr": [{"match": "exact", "value": "a", "tier": "admin", "weight": -1}]}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := ParsePrivilegeCatalog([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePrivilegeCatalog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !catalog.Lookup("hr", "Payroll", "").Privileged() {
+				t.Errorf("Lookup(hr, Payroll) is not privileged")
+			}
+		})
+	}
+}
+
+func TestBestSystemKey(t *testing.T) {
+	keys := []string{"*", "sap", "sap export", "okta"}
+	tests := []struct {
+		system string
+		want   string
+	}{
+		{"okta", "okta"},
+		{"sap_export_2024", "sap export"},
+		{"sap_hr", "sap"},
+		{"github", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			if got := BestSystemKey(keys, tt.system); got != tt.want {
+				t.Errorf("BestSystemKey(%q) = %q, want %q", tt.system, got, tt.want)
+			}
+		})
+	}
+}
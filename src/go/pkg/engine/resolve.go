@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+
+	"uar/pkg/schema"
+)
+
+// ResolveCandidate re-resolves a fuzzy or ambiguous match to the SoT record the
+// reviewer picked from its candidate list. The chosen canonical ID must be one of
+// the record's candidates. Conflicts are recomputed against the new SoT record with
+// checks, which should be the ConflictChecks the record was joined with; nil uses
+// DefaultConflictChecks. The match type becomes "reviewer_selected" and the
+// reviewer's choice is appended to the evidence trail.
+func ResolveCandidate(index *SoTIndex, rec MatchedRecord, canonicalID string, checks []ConflictCheck) (*MatchedRecord, error) {
+	listed := false
+	for _, c := range rec.Candidates {
+		if c.CanonicalID == canonicalID {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		return nil, fmt.Errorf("canonical ID %q is not a candidate for this record", canonicalID)
+	}
+
+	sotRec := findByCanonicalID(index, canonicalID)
+	if sotRec == nil {
+		return nil, fmt.Errorf("canonical ID %q not found in SoT index", canonicalID)
+	}
+
+	evidence := append(append([]MatchEvidence(nil), rec.Evidence...), MatchEvidence{
+		Key:           "reviewer",
+		SoTValue:      sotRec.DisplayName,
+		SoTNormalized: sotRec.CanonicalID,
+		Score:         1.0,
+		RulesApplied:  []string{RuleReviewerChoice},
+		Outcome:       EvidenceMatched,
+	})
+
+	return &MatchedRecord{
+		SoT:            sotRec,
+		Satellite:      rec.Satellite,
+		MatchType:      "reviewer_selected",
+		Conflicts:      DetectConflictsWith(sotRec, rec.Satellite, checks),
+		Candidates:     rec.Candidates,
+		Evidence:       evidence,
+		Classification: rec.Classification,
+	}, nil
+}
+
+// ClaimOrphan links an orphan to the SoT record the reviewer picked from its
+// near-miss suggestions. It is ResolveCandidate applied to the orphan's suggestions.
+func ClaimOrphan(index *SoTIndex, orphan OrphanRecord, canonicalID string, checks []ConflictCheck) (*MatchedRecord, error) {
+	return ResolveCandidate(index, MatchedRecord{
+		Satellite:      orphan.Satellite,
+		Candidates:     orphan.Suggestions,
+		Evidence:       orphan.AttemptedMatches,
+		Classification: orphan.Classification,
+	}, canonicalID, checks)
+}
+
+// findByCanonicalID looks up a SoT record by canonical ID. Canonical IDs are the
+// email when present, otherwise the employee ID, so both maps are consulted.
+func findByCanonicalID(index *SoTIndex, canonicalID string) *schema.SoTRecord {
+	if rec, ok := index.ByEmail[canonicalID]; ok && rec.CanonicalID == canonicalID {
+		return rec
+	}
+	if rec, ok := index.ByEmployeeID[canonicalID]; ok && rec.CanonicalID == canonicalID {
+		return rec
+	}
+	return nil
+}
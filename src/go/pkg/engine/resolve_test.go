@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+
+	"uar/pkg/schema"
+)
+
+func TestResolveCandidate(t *testing.T) {
+	sot := testSoT()
+	sat := schema.SatelliteRecord{Email: "alex@acme.com", DisplayName: "alex kim", Department: "Marketing", SourceFile: "okta.csv", SourceRow: 1}
+	candidates := []MatchCandidate{{CanonicalID: "alex.kim@acme.com"}, {CanonicalID: "akim@acme.com"}}
+	onlyDepartment := []ConflictCheck{{Field: "department", Mode: ConflictExact, Severity: RiskMedium, Resolution: ResolveFlagOnly}}
+
+	tests := []struct {
+		name          string
+		canonicalID   string
+		checks        []ConflictCheck
+		wantErr       bool
+		wantConflicts []string
+	}{
+		{
+			name:          "default checks",
+			canonicalID:   "akim@acme.com",
+			wantConflicts: []string{"email", "department"},
+		},
+		{
+			name:          "configured checks replace the defaults",
+			canonicalID:   "akim@acme.com",
+			checks:        onlyDepartment,
+			wantConflicts: []string{"department"},
+		},
+		{
+			name:        "not a candidate",
+			canonicalID: "jane@acme.com",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := MatchedRecord{Satellite: sat, MatchType: "fuzzy_ambiguous", Candidates: candidates}
+			got, err := ResolveCandidate(sot, rec, tt.canonicalID, tt.checks)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ResolveCandidate() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveCandidate() error = %v", err)
+			}
+			if got.MatchType != "reviewer_selected" || got.SoT.CanonicalID != tt.canonicalID {
+				t.Errorf("ResolveCandidate() = %s %s, want reviewer_selected %s", got.MatchType, got.SoT.CanonicalID, tt.canonicalID)
+			}
+			var fields []string
+			for _, c := range got.Conflicts {
+				fields = append(fields, c.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.wantConflicts) {
+				t.Errorf("ResolveCandidate() conflicts = %v, want %v", fields, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestClaimOrphanUsesConfiguredChecks(t *testing.T) {
+	orphan := OrphanRecord{
+		Satellite:   schema.SatelliteRecord{Email: "jdoe@acme.com", DisplayName: "J. Doe", Department: "finance", SourceFile: "okta.csv", SourceRow: 1},
+		Suggestions: []MatchCandidate{{CanonicalID: "jane@acme.com"}},
+	}
+	checks := []ConflictCheck{{Field: "department", Mode: ConflictExact, Severity: RiskLow, Resolution: ResolveSoTWins}}
+
+	got, err := ClaimOrphan(testSoT(), orphan, "jane@acme.com", checks)
+	if err != nil {
+		t.Fatalf("ClaimOrphan() error = %v", err)
+	}
+	if len(got.Conflicts) != 1 || got.Conflicts[0].Field != "department" || got.Conflicts[0].ResolvedValue != "Finance" {
+		t.Errorf("ClaimOrphan() conflicts = %+v, want one exact department conflict resolved to the SoT value", got.Conflicts)
+	}
+}
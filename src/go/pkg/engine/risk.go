@@ -1,7 +1,7 @@
 package engine
 
 import (
-	"strings"
+	"fmt"
 	"time"
 
 	"uar/pkg/schema"
@@ -18,167 +18,167 @@ const (
 	RiskInfo     RiskLevel = "INFO"
 )
 
-// DefaultPrivilegedKeywords is the default set of keywords that indicate a privileged role.
-var DefaultPrivilegedKeywords = []string{
-	"admin",
-	"root",
-	"superuser",
-	"owner",
-	"global_admin",
-	"domain_admin",
-	"system",
-	"privileged",
+// DefaultTerminationGraceDays is how long after termination an account may stay
+// enabled before it is flagged as deprovisioned late.
+const DefaultTerminationGraceDays = 7
+
+// RiskFinding is a single risk rule that fired for a record.
+type RiskFinding struct {
+	Code        string    `json:"code"`
+	Level       RiskLevel `json:"level"`
+	Score       int       `json:"score"`
+	Description string    `json:"description"`
+	// GapDays is the number of days between the two dates the rule compared, if any.
+	GapDays int `json:"gapDays,omitempty"`
+	// PeerGroupSize and Prevalence describe a peer outlier: how many peers were
+	// compared and the fraction of them holding the same access.
+	PeerGroupSize int     `json:"peerGroupSize,omitempty"`
+	Prevalence    float64 `json:"prevalence,omitempty"`
 }
 
-// ScoreRisk evaluates a matched record and returns a risk level and numeric score.
-// Rules from Section 8 of the design doc:
-//   - terminated + active access = CRITICAL (100)
-//   - orphan (no SoT match) = HIGH (80)
-//   - dormant N+ days = MEDIUM (50)
-//   - admin/privileged role = MEDIUM (50)
-//   - admin + dormant = HIGH (80)
-//   - contractor + broad access = MEDIUM (50)
-//   - fuzzy_ambiguous match = LOW (20)
-//   - normal active user = INFO (0)
-//
-// The function takes the highest applicable risk level.
-func ScoreRisk(
-	sot *schema.SoTRecord,
-	sat schema.SatelliteRecord,
-	matchType string,
-	processingTimestamp int64,
-	dormancyDays int,
-	privilegedKeywords []string,
-) (RiskLevel, int) {
-	if privilegedKeywords == nil {
-		privilegedKeywords = DefaultPrivilegedKeywords
+// isDormantAccount checks if the last login is older than the dormancy threshold.
+func isDormantAccount(lastLogin string, processingTimestamp int64, dormancyDays int, loc *time.Location) bool {
+	if lastLogin == "" {
+		return false
 	}
 
-	if dormancyDays <= 0 {
-		dormancyDays = 90
+	loginTime, parsed := parseTimestamp(lastLogin, loc)
+	if !parsed {
+		// Cannot parse the date — treat as not dormant to avoid false positives
+		return false
 	}
 
-	// Track the highest risk found
-	highestLevel := RiskInfo
-	highestScore := 0
+	processingTime := time.UnixMilli(processingTimestamp)
+	threshold := processingTime.AddDate(0, 0, -dormancyDays)
 
-	// Check for orphan first (no SoT match)
-	if matchType == "orphan" {
-		return RiskHigh, 80
-	}
+	return loginTime.Before(threshold)
+}
 
-	// Rule: terminated user with active access = CRITICAL
-	if sot != nil && strings.ToLower(sot.EmploymentStatus) == "terminated" {
-		satStatus := strings.ToLower(sat.AccountStatus)
-		if satStatus == "active" || satStatus == "enabled" || satStatus == "" {
-			return RiskCritical, 100
-		}
-	}
+// parseTimestamp parses a date or timestamp with schema.ParseTimestampIn, reading
+// values without an offset in loc. Numeric dates are read month-first;
+// NormalizeSatellite has already rewritten day-first columns.
+func parseTimestamp(value string, loc *time.Location) (time.Time, bool) {
+	t, kind := schema.ParseTimestampIn(value, schema.DateOrderMDY, loc)
+	return t, kind == schema.TimestampValid
+}
 
-	// Check for privileged role
-	isPrivileged := isPrivilegedAccess(sat.Role, sat.Entitlement, privilegedKeywords)
+// Non-human account finding codes returned by RiskPolicy.ScoreNonHuman.
+const (
+	FindingServiceNoOwner    = "service_account_no_owner"
+	FindingServiceOwned      = "service_account_owned"
+	FindingSharedAccount     = "shared_account"
+	FindingBreakGlassUsed    = "break_glass_recent_use"
+	FindingBreakGlassUnknown = "break_glass_unknown_use"
+	FindingBreakGlassStandby = "break_glass_standby"
+)
 
-	// Check for dormancy
-	isDormant := isDormantAccount(sat.LastLogin, processingTimestamp, dormancyDays)
+// NonHumanScore is the level and score of one non-human account finding.
+type NonHumanScore struct {
+	Level RiskLevel `json:"level" yaml:"level"`
+	Score int       `json:"score" yaml:"score"`
+}
 
-	// Rule: admin + dormant = HIGH (80)
-	if isPrivileged && isDormant {
-		if 80 > highestScore {
-			highestLevel = RiskHigh
-			highestScore = 80
-		}
-	} else {
-		// Rule: dormant alone = MEDIUM (50)
-		if isDormant {
-			if 50 > highestScore {
-				highestLevel = RiskMedium
-				highestScore = 50
-			}
-		}
+// NonHumanScoring scores unmatched non-human accounts (JoinResult.NonHuman). These
+// accounts are not matched to a SoT person, so ownership is itself the primary finding
+// and each account gets exactly one. A privileged account adds PrivilegedBonus to the
+// score, up to PrivilegedCap.
+type NonHumanScoring struct {
+	// BreakGlassUsed is a break-glass account used within the dormancy window.
+	BreakGlassUsed NonHumanScore `json:"breakGlassUsed" yaml:"breakGlassUsed"`
+	// BreakGlassUnknown is a break-glass account whose last login cannot be parsed.
+	BreakGlassUnknown NonHumanScore `json:"breakGlassUnknown" yaml:"breakGlassUnknown"`
+	// BreakGlassStandby is an idle break-glass account, expected to exist but be unused.
+	BreakGlassStandby NonHumanScore `json:"breakGlassStandby" yaml:"breakGlassStandby"`
+	Shared            NonHumanScore `json:"shared" yaml:"shared"`
+	ServiceNoOwner    NonHumanScore `json:"serviceNoOwner" yaml:"serviceNoOwner"`
+	// ServiceOwned is a service account with a reviewer-confirmed owner in the SoT.
+	ServiceOwned    NonHumanScore `json:"serviceOwned" yaml:"serviceOwned"`
+	PrivilegedBonus int           `json:"privilegedBonus" yaml:"privilegedBonus"`
+	PrivilegedCap   int           `json:"privilegedCap" yaml:"privilegedCap"`
+}
 
-		// Rule: admin/privileged alone = MEDIUM (50)
-		if isPrivileged {
-			if 50 > highestScore {
-				highestLevel = RiskMedium
-				highestScore = 50
-			}
-		}
+// validate reports an unknown level or a score outside 0-100.
+func (n *NonHumanScoring) validate() error {
+	scores := []struct {
+		name  string
+		score NonHumanScore
+	}{
+		{"breakGlassUsed", n.BreakGlassUsed},
+		{"breakGlassUnknown", n.BreakGlassUnknown},
+		{"breakGlassStandby", n.BreakGlassStandby},
+		{"shared", n.Shared},
+		{"serviceNoOwner", n.ServiceNoOwner},
+		{"serviceOwned", n.ServiceOwned},
 	}
-
-	// Rule: contractor with broad access = MEDIUM (50)
-	if sot != nil && strings.ToLower(sot.EmploymentStatus) == "contractor" {
-		if isPrivileged {
-			if 50 > highestScore {
-				highestLevel = RiskMedium
-				highestScore = 50
-			}
+	for _, s := range scores {
+		if _, ok := riskLevelRank[s.score.Level]; !ok {
+			return fmt.Errorf("nonHuman.%s: unknown level %q", s.name, s.score.Level)
 		}
-	}
-
-	// Rule: fuzzy_ambiguous = LOW (20)
-	if matchType == "fuzzy_ambiguous" {
-		if 20 > highestScore {
-			highestLevel = RiskLow
-			highestScore = 20
+		if s.score.Score < 0 || s.score.Score > 100 {
+			return fmt.Errorf("nonHuman.%s: score must be between 0 and 100", s.name)
 		}
 	}
-
-	return highestLevel, highestScore
+	if n.PrivilegedBonus < 0 || n.PrivilegedCap < 0 || n.PrivilegedCap > 100 {
+		return fmt.Errorf("nonHuman: privilegedBonus must not be negative and privilegedCap must be between 0 and 100")
+	}
+	return nil
 }
 
-// isPrivilegedAccess checks if the role or entitlement contains any privileged keywords.
-func isPrivilegedAccess(role, entitlement string, keywords []string) bool {
-	roleLower := strings.ToLower(role)
-	entitlementLower := strings.ToLower(entitlement)
-
-	for _, kw := range keywords {
-		kwLower := strings.ToLower(kw)
-		if strings.Contains(roleLower, kwLower) || strings.Contains(entitlementLower, kwLower) {
-			return true
-		}
+// ScoreNonHuman evaluates an unmatched non-human account and returns its finding,
+// scored by the policy's NonHuman section; a nil policy, or one without that section,
+// uses the default policy's. The dormancy window is cfg's threshold for the account's
+// system. A break-glass account with no last login is idle; one whose last login
+// cannot be parsed is reported as unknown use rather than assumed idle or used.
+func (p *RiskPolicy) ScoreNonHuman(nh OrphanRecord, cfg RiskConfig) RiskFinding {
+	scoring := defaultRiskPolicy.NonHuman
+	if p != nil && p.NonHuman != nil {
+		scoring = p.NonHuman
 	}
 
-	return false
-}
-
-// isDormantAccount checks if the last login is older than the dormancy threshold.
-func isDormantAccount(lastLogin string, processingTimestamp int64, dormancyDays int) bool {
-	if lastLogin == "" {
-		return false
+	sat := nh.Satellite
+	dormancyDays := cfg.DormancyDaysFor(sat.SourceFile)
+	if dormancyDays <= 0 {
+		dormancyDays = DefaultDormancyDays
 	}
 
-	// Try several common date formats
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
-		"01/02/2006",
-		"1/2/2006",
-		"Jan 2, 2006",
-		"January 2, 2006",
-		"02-Jan-2006",
+	finding := func(code string, score NonHumanScore, description string) RiskFinding {
+		return RiskFinding{Code: code, Level: score.Level, Score: score.Score, Description: description}
 	}
 
-	var loginTime time.Time
-	parsed := false
-	for _, format := range formats {
-		t, err := time.Parse(format, lastLogin)
-		if err == nil {
-			loginTime = t
-			parsed = true
+	var f RiskFinding
+
+	switch nh.Classification.Class {
+	case AccountBreakGlass:
+		if sat.LastLogin == "" {
+			return finding(FindingBreakGlassStandby, scoring.BreakGlassStandby, "break-glass account not used recently")
+		}
+		if _, ok := parseTimestamp(sat.LastLogin, cfg.Location()); !ok {
+			f = finding(FindingBreakGlassUnknown, scoring.BreakGlassUnknown, fmt.Sprintf("break-glass account last login %q is not a recognizable date", sat.LastLogin))
+			break
+		}
+		if isDormantAccount(sat.LastLogin, cfg.ProcessingTimestamp, dormancyDays, cfg.Location()) {
+			return finding(FindingBreakGlassStandby, scoring.BreakGlassStandby, "break-glass account not used recently")
+		}
+		f = finding(FindingBreakGlassUsed, scoring.BreakGlassUsed, "break-glass account used recently")
+	case AccountShared:
+		f = finding(FindingSharedAccount, scoring.Shared, "shared or generic account with no individual owner")
+	default:
+		if nh.Owner != nil {
+			f = finding(FindingServiceOwned, scoring.ServiceOwned, "service account owned by "+nh.Owner.CanonicalID)
 			break
 		}
+		f = finding(FindingServiceNoOwner, scoring.ServiceNoOwner, "service account without an owner")
 	}
 
-	if !parsed {
-		// Cannot parse the date — treat as not dormant to avoid false positives
-		return false
+	if privilege := cfg.Privileges.Lookup(sat.SourceFile, sat.Role, sat.Entitlement); privilege.Privileged() {
+		if f.Score < scoring.PrivilegedCap {
+			f.Score += scoring.PrivilegedBonus
+			if f.Score > scoring.PrivilegedCap {
+				f.Score = scoring.PrivilegedCap
+			}
+		}
+		f.Description += fmt.Sprintf(", with %s access through %q", privilege.Tier, privilege.Grant)
 	}
 
-	processingTime := time.UnixMilli(processingTimestamp)
-	threshold := processingTime.AddDate(0, 0, -dormancyDays)
-
-	return loginTime.Before(threshold)
+	return f
 }
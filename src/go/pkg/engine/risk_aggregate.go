@@ -0,0 +1,154 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// AggregationMethod is how a record's findings combine into one risk score.
+type AggregationMethod string
+
+const (
+	// AggregateMax takes the highest-scoring finding.
+	AggregateMax AggregationMethod = "max"
+	// AggregateWeightedSum adds the findings' scores, each multiplied by its code's weight.
+	AggregateWeightedSum AggregationMethod = "weighted_sum"
+	// AggregateCappedSum is a weighted sum limited to Cap.
+	AggregateCappedSum AggregationMethod = "capped_sum"
+)
+
+// defaultAggregationCap limits capped_sum scores when no cap is configured.
+const defaultAggregationCap = 100
+
+// LevelThresholds are the minimum scores for each level when a sum is converted
+// back to a risk level. Scores below Low are INFO.
+type LevelThresholds struct {
+	Critical int `json:"critical" yaml:"critical"`
+	High     int `json:"high" yaml:"high"`
+	Medium   int `json:"medium" yaml:"medium"`
+	Low      int `json:"low" yaml:"low"`
+}
+
+// DefaultLevelThresholds place the original rule scores in their original levels.
+var DefaultLevelThresholds = LevelThresholds{Critical: 90, High: 70, Medium: 40, Low: 1}
+
+// RiskAggregation configures how RiskPolicy.Aggregate combines findings.
+type RiskAggregation struct {
+	Method AggregationMethod `json:"method" yaml:"method"`
+	// Weights multiply finding scores by code in the sum methods; unlisted codes weigh 1.
+	Weights map[string]float64 `json:"weights,omitempty" yaml:"weights,omitempty"`
+	// Cap is the highest capped_sum score; 0 means 100.
+	Cap int `json:"cap,omitempty" yaml:"cap,omitempty"`
+	// Thresholds convert sum scores to levels; nil uses DefaultLevelThresholds.
+	Thresholds *LevelThresholds `json:"thresholds,omitempty" yaml:"thresholds,omitempty"`
+}
+
+// validate reports an unknown method or a negative weight, cap, or threshold.
+func (a RiskAggregation) validate() error {
+	switch a.Method {
+	case "", AggregateMax, AggregateWeightedSum, AggregateCappedSum:
+	default:
+		return fmt.Errorf("aggregation: unknown method %q", a.Method)
+	}
+	for code, w := range a.Weights {
+		if w < 0 {
+			return fmt.Errorf("aggregation: weight for %s must not be negative", code)
+		}
+	}
+	if a.Cap < 0 {
+		return fmt.Errorf("aggregation: cap must not be negative")
+	}
+	if t := a.Thresholds; t != nil {
+		if t.Low < 0 || t.Medium < t.Low || t.High < t.Medium || t.Critical < t.High {
+			return fmt.Errorf("aggregation: thresholds must be non-negative and ordered low <= medium <= high <= critical")
+		}
+	}
+	return nil
+}
+
+// Aggregate combines a record's findings into one level and score using the policy's
+// aggregation method. Under max the level is that of the highest-scoring finding.
+// Under the sum methods the level comes from the score thresholds, but is never
+// lower than the most severe finding, so a CRITICAL rule cannot be diluted.
+// No findings yields INFO (0).
+func (p *RiskPolicy) Aggregate(findings []RiskFinding) (RiskLevel, int) {
+	if p == nil {
+		p = defaultRiskPolicy
+	}
+
+	highestLevel := RiskInfo
+	highestScore := 0
+	for _, f := range findings {
+		if f.Score > highestScore {
+			highestLevel = f.Level
+			highestScore = f.Score
+		}
+	}
+
+	agg := p.Aggregation
+	if agg.Method == "" || agg.Method == AggregateMax {
+		return highestLevel, highestScore
+	}
+
+	sum := 0.0
+	for _, f := range findings {
+		weight, ok := agg.Weights[f.Code]
+		if !ok {
+			weight = 1
+		}
+		sum += weight * float64(f.Score)
+	}
+	score := int(math.Round(sum))
+
+	if agg.Method == AggregateCappedSum {
+		limit := agg.Cap
+		if limit == 0 {
+			limit = defaultAggregationCap
+		}
+		if score > limit {
+			score = limit
+		}
+	}
+
+	thresholds := DefaultLevelThresholds
+	if agg.Thresholds != nil {
+		thresholds = *agg.Thresholds
+	}
+	level := thresholds.level(score)
+	for _, f := range findings {
+		if riskLevelRank[f.Level] > riskLevelRank[level] {
+			level = f.Level
+		}
+	}
+
+	return level, score
+}
+
+// level maps a score to the highest level whose threshold it reaches.
+func (t LevelThresholds) level(score int) RiskLevel {
+	switch {
+	case score >= t.Critical:
+		return RiskCritical
+	case score >= t.High:
+		return RiskHigh
+	case score >= t.Medium:
+		return RiskMedium
+	case score >= t.Low && score > 0:
+		return RiskLow
+	}
+	return RiskInfo
+}
+
+// riskLevelRank orders risk levels from least to most severe.
+var riskLevelRank = map[RiskLevel]int{
+	RiskInfo:     0,
+	RiskLow:      1,
+	RiskMedium:   2,
+	RiskHigh:     3,
+	RiskCritical: 4,
+}
+
+// Outranks reports whether l is a more severe risk level than other.
+func (l RiskLevel) Outranks(other RiskLevel) bool {
+	return riskLevelRank[l] > riskLevelRank[other]
+}
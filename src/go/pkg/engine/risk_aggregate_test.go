@@ -0,0 +1,66 @@
+package engine
+
+import "testing"
+
+func TestRiskPolicyAggregate(t *testing.T) {
+	policy := func(agg RiskAggregation) *RiskPolicy {
+		return &RiskPolicy{Aggregation: agg}
+	}
+	dormant := RiskFinding{Code: "dormant", Level: RiskMedium, Score: 50}
+	privileged := RiskFinding{Code: "privileged", Level: RiskMedium, Score: 50}
+	orphan := RiskFinding{Code: "orphan", Level: RiskHigh, Score: 80}
+	critical := RiskFinding{Code: "terminated_active", Level: RiskCritical, Score: 10}
+	ambiguous := RiskFinding{Code: "ambiguous_match", Level: RiskLow, Score: 20}
+
+	tests := []struct {
+		name      string
+		policy    *RiskPolicy
+		findings  []RiskFinding
+		wantLevel RiskLevel
+		wantScore int
+	}{
+		{name: "nil policy uses max", findings: []RiskFinding{dormant, orphan}, wantLevel: RiskHigh, wantScore: 80},
+		{name: "no findings", policy: policy(RiskAggregation{Method: AggregateCappedSum}), wantLevel: RiskInfo, wantScore: 0},
+		{name: "empty finding set under max", policy: policy(RiskAggregation{Method: AggregateMax}), findings: []RiskFinding{}, wantLevel: RiskInfo, wantScore: 0},
+		{name: "max takes the highest score", policy: policy(RiskAggregation{Method: AggregateMax}), findings: []RiskFinding{dormant, orphan, ambiguous}, wantLevel: RiskHigh, wantScore: 80},
+		{name: "max tie keeps the first finding's level", policy: policy(RiskAggregation{}), findings: []RiskFinding{{Code: "a", Level: RiskMedium, Score: 50}, {Code: "b", Level: RiskHigh, Score: 50}}, wantLevel: RiskMedium, wantScore: 50},
+		{name: "weighted sum adds scores", policy: policy(RiskAggregation{Method: AggregateWeightedSum}), findings: []RiskFinding{dormant, privileged, orphan}, wantLevel: RiskCritical, wantScore: 180},
+		{name: "weighted sum applies weights", policy: policy(RiskAggregation{Method: AggregateWeightedSum, Weights: map[string]float64{"dormant": 0.5, "privileged": 0}}), findings: []RiskFinding{dormant, privileged}, wantLevel: RiskMedium, wantScore: 25},
+		{name: "capped sum stops at 100 by default", policy: policy(RiskAggregation{Method: AggregateCappedSum}), findings: []RiskFinding{dormant, privileged, orphan}, wantLevel: RiskCritical, wantScore: 100},
+		{name: "capped sum uses the configured cap", policy: policy(RiskAggregation{Method: AggregateCappedSum, Cap: 75}), findings: []RiskFinding{dormant, privileged}, wantLevel: RiskHigh, wantScore: 75},
+		{name: "sum exactly at a threshold reaches that level", policy: policy(RiskAggregation{Method: AggregateCappedSum}), findings: []RiskFinding{dormant, ambiguous}, wantLevel: RiskHigh, wantScore: 70},
+		{name: "sum uses configured thresholds", policy: policy(RiskAggregation{Method: AggregateCappedSum, Thresholds: &LevelThresholds{Critical: 200, High: 150, Medium: 100, Low: 10}}), findings: []RiskFinding{dormant, ambiguous}, wantLevel: RiskMedium, wantScore: 70},
+		{name: "sum never dilutes a critical finding", policy: policy(RiskAggregation{Method: AggregateCappedSum}), findings: []RiskFinding{critical}, wantLevel: RiskCritical, wantScore: 10},
+		{name: "zero weighted sum is INFO", policy: policy(RiskAggregation{Method: AggregateWeightedSum, Weights: map[string]float64{"ambiguous_match": 0}}), findings: []RiskFinding{{Code: "ambiguous_match", Level: RiskInfo, Score: 20}}, wantLevel: RiskInfo, wantScore: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, score := tt.policy.Aggregate(tt.findings)
+			if level != tt.wantLevel || score != tt.wantScore {
+				t.Errorf("Aggregate() = %s %d, want %s %d", level, score, tt.wantLevel, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestRiskAggregationValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		agg     RiskAggregation
+		wantErr bool
+	}{
+		{name: "zero value", agg: RiskAggregation{}},
+		{name: "capped sum", agg: RiskAggregation{Method: AggregateCappedSum, Cap: 90, Weights: map[string]float64{"orphan": 2}}},
+		{name: "unknown method", agg: RiskAggregation{Method: "average"}, wantErr: true},
+		{name: "negative weight", agg: RiskAggregation{Method: AggregateWeightedSum, Weights: map[string]float64{"orphan": -1}}, wantErr: true},
+		{name: "negative cap", agg: RiskAggregation{Method: AggregateCappedSum, Cap: -5}, wantErr: true},
+		{name: "unordered thresholds", agg: RiskAggregation{Thresholds: &LevelThresholds{Critical: 50, High: 70, Medium: 40, Low: 1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.agg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
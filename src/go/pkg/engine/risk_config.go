@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDormancyDays is how long an account may go without a login before it is
+// dormant, for systems without their own threshold.
+const DefaultDormancyDays = 90
+
+// RiskConfig holds the caller's scoring settings. Zero fields fall back to the
+// defaults, or to the risk policy's params for dormancy and grace days.
+type RiskConfig struct {
+	// ProcessingTimestamp is the "now" of the review in Unix milliseconds.
+	ProcessingTimestamp int64 `json:"processingTimestamp,omitempty" yaml:"processingTimestamp"`
+	// TimeZone is the IANA zone used to read dates and times without an offset,
+	// e.g. a termination date of 2026-03-31; empty means UTC.
+	TimeZone string `json:"timeZone,omitempty" yaml:"timeZone"`
+	// DormancyDays is the dormancy threshold for systems not in SystemDormancyDays.
+	DormancyDays int `json:"dormancyDays,omitempty" yaml:"dormancyDays"`
+	// SystemDormancyDays overrides DormancyDays per system. Keys match system names
+	// like privilege catalog keys: "aws_prod" covers "aws_prod_export".
+	SystemDormancyDays map[string]int `json:"systemDormancyDays,omitempty" yaml:"systemDormancyDays"`
+	// TerminationGraceDays is how long after termination deprovisioning may take.
+	TerminationGraceDays int `json:"terminationGraceDays,omitempty" yaml:"terminationGraceDays"`
+	// Privileges is the privilege catalog with the privileged keyword sets; nil uses
+	// the default catalog.
+	Privileges *PrivilegeCatalog `json:"privileges,omitempty" yaml:"privileges"`
+	// PeerOutliers enables peer-group outlier findings; nil skips the analysis.
+	PeerOutliers *PeerOutlierConfig `json:"peerOutliers,omitempty" yaml:"peerOutliers"`
+
+	loc *time.Location
+}
+
+// ParseRiskConfig parses a risk configuration from JSON or YAML and validates it.
+// Empty input gives the zero configuration.
+func ParseRiskConfig(data []byte) (*RiskConfig, error) {
+	var cfg RiskConfig
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return &cfg, nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse risk config JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal([]byte(trimmed), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse risk config YAML: %w", err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate checks the configuration and loads its time zone and privilege catalog.
+func (c *RiskConfig) validate() error {
+	if c.ProcessingTimestamp < 0 {
+		return fmt.Errorf("processingTimestamp must not be negative")
+	}
+	if c.DormancyDays < 0 {
+		return fmt.Errorf("dormancyDays must not be negative")
+	}
+	if c.TerminationGraceDays < 0 {
+		return fmt.Errorf("terminationGraceDays must not be negative")
+	}
+	for system, days := range c.SystemDormancyDays {
+		if strings.TrimSpace(system) == "" || system == "*" {
+			return fmt.Errorf("systemDormancyDays: system name is required; use dormancyDays for every system")
+		}
+		if days <= 0 {
+			return fmt.Errorf("systemDormancyDays %s: days must be positive", system)
+		}
+	}
+
+	if c.PeerOutliers != nil {
+		if err := c.PeerOutliers.validate(); err != nil {
+			return err
+		}
+	}
+
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return fmt.Errorf("unknown timeZone %q: %w", c.TimeZone, err)
+	}
+	c.loc = loc
+
+	if c.Privileges != nil {
+		if err := c.Privileges.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithDefaults returns the configuration with every unset field filled in: the
+// processing timestamp from the argument, UTC, DefaultDormancyDays, and
+// DefaultTerminationGraceDays. SystemDormancyDays, Privileges, and PeerOutliers are
+// left as they are.
+func (c RiskConfig) WithDefaults(processingTimestamp int64) RiskConfig {
+	if c.ProcessingTimestamp == 0 {
+		c.ProcessingTimestamp = processingTimestamp
+	}
+	if c.TimeZone == "" {
+		c.TimeZone = "UTC"
+	}
+	if c.DormancyDays <= 0 {
+		c.DormancyDays = DefaultDormancyDays
+	}
+	if c.TerminationGraceDays <= 0 {
+		c.TerminationGraceDays = DefaultTerminationGraceDays
+	}
+	return c
+}
+
+// DormancyDaysFor returns the dormancy threshold for a system: the SystemDormancyDays
+// key chosen by BestSystemKey, then DormancyDays. It returns 0 when neither is set.
+func (c RiskConfig) DormancyDaysFor(system string) int {
+	keys := make([]string, 0, len(c.SystemDormancyDays))
+	for key := range c.SystemDormancyDays {
+		keys = append(keys, key)
+	}
+	if key := BestSystemKey(keys, system); key != "" {
+		return c.SystemDormancyDays[key]
+	}
+	return c.DormancyDays
+}
+
+// Location returns the configured time zone, or UTC.
+func (c RiskConfig) Location() *time.Location {
+	if c.loc != nil {
+		return c.loc
+	}
+	if c.TimeZone != "" {
+		if loc, err := time.LoadLocation(c.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
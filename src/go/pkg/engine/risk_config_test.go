@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRiskConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string // substring of the error; empty when parsing must succeed
+	}{
+		{name: "empty input", input: "  \n"},
+		{name: "JSON", input: `{"dormancyDays": 30, "timeZone": "America/New_York"}`},
+		{name: "YAML", input: "dormancyDays: 30\nsystemDormancyDays:\n  aws_prod: 14\n"},
+		{name: "invalid JSON", input: `{"dormancyDays": "thirty"}`, wantErr: "risk config JSON"},
+		{name: "invalid YAML", input: "dormancyDays: [30", wantErr: "risk config YAML"},
+		{name: "unknown time zone", input: "timeZone: Mars/Olympus_Mons", wantErr: "unknown timeZone"},
+		{name: "negative processing timestamp", input: "processingTimestamp: -1", wantErr: "processingTimestamp"},
+		{name: "negative dormancy", input: "dormancyDays: -1", wantErr: "dormancyDays"},
+		{name: "negative grace days", input: "terminationGraceDays: -7", wantErr: "terminationGraceDays"},
+		{name: "zero system dormancy", input: "systemDormancyDays:\n  okta: 0\n", wantErr: "systemDormancyDays okta"},
+		{name: "negative system dormancy", input: "systemDormancyDays:\n  okta: -30\n", wantErr: "systemDormancyDays okta"},
+		{name: "wildcard system dormancy", input: "systemDormancyDays:\n  \"*\": 30\n", wantErr: "use dormancyDays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseRiskConfig([]byte(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseRiskConfig() error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRiskConfig() error = %v", err)
+			}
+			if cfg == nil {
+				t.Fatal("ParseRiskConfig() = nil, want a configuration")
+			}
+		})
+	}
+}
+
+func TestRiskConfigWithDefaults(t *testing.T) {
+	cfg, err := ParseRiskConfig(nil)
+	if err != nil {
+		t.Fatalf("ParseRiskConfig() error = %v", err)
+	}
+
+	got := cfg.WithDefaults(1767225600000)
+	if got.ProcessingTimestamp != 1767225600000 {
+		t.Errorf("ProcessingTimestamp = %d, want 1767225600000", got.ProcessingTimestamp)
+	}
+	if got.TimeZone != "UTC" || got.Location() != time.UTC {
+		t.Errorf("TimeZone = %q, location %v, want UTC", got.TimeZone, got.Location())
+	}
+	if got.DormancyDays != DefaultDormancyDays {
+		t.Errorf("DormancyDays = %d, want %d", got.DormancyDays, DefaultDormancyDays)
+	}
+	if got.TerminationGraceDays != DefaultTerminationGraceDays {
+		t.Errorf("TerminationGraceDays = %d, want %d", got.TerminationGraceDays, DefaultTerminationGraceDays)
+	}
+
+	set := RiskConfig{ProcessingTimestamp: 1, TimeZone: "Europe/Berlin", DormancyDays: 30, TerminationGraceDays: 2}
+	if got := set.WithDefaults(1767225600000); got.ProcessingTimestamp != 1 || got.TimeZone != "Europe/Berlin" || got.DormancyDays != 30 || got.TerminationGraceDays != 2 {
+		t.Errorf("WithDefaults() = %+v, want the configured values kept", got)
+	}
+}
+
+func TestRiskConfigTimeZone(t *testing.T) {
+	cfg, err := ParseRiskConfig([]byte(`{"timeZone": "America/New_York"}`))
+	if err != nil {
+		t.Fatalf("ParseRiskConfig() error = %v", err)
+	}
+	if got := cfg.Location().String(); got != "America/New_York" {
+		t.Errorf("Location() = %s, want America/New_York", got)
+	}
+}
+
+func TestRiskConfigDormancyDaysFor(t *testing.T) {
+	cfg, err := ParseRiskConfig([]byte("dormancyDays: 60\nsystemDormancyDays:\n  aws: 45\n  aws_prod: 14\n  okta: 30\n"))
+	if err != nil {
+		t.Fatalf("ParseRiskConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		system string
+		want   int
+	}{
+		{"okta", 30},
+		{"aws_prod", 14},
+		{"aws_prod_export", 14},
+		{"aws_dev", 45},
+		{"github", 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			if got := cfg.DormancyDaysFor(tt.system); got != tt.want {
+				t.Errorf("DormancyDaysFor(%q) = %d, want %d", tt.system, got, tt.want)
+			}
+		})
+	}
+
+	if got := (RiskConfig{}).DormancyDaysFor("okta"); got != 0 {
+		t.Errorf("DormancyDaysFor() with nothing set = %d, want 0", got)
+	}
+}
@@ -0,0 +1,510 @@
+package engine
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"uar/pkg/schema"
+)
+
+// DefaultRiskPolicyText is the built-in risk policy. It encodes the rules from
+// Section 8 of the design doc:
+//   - login after the SoT termination date = CRITICAL (100)
+//   - terminated + active access = CRITICAL (100)
+//   - disabled more than terminationGraceDays after termination = HIGH (70)
+//   - login after the contract end date = CRITICAL (100)
+//   - contract ended more than terminationGraceDays ago + active access = HIGH (80)
+//   - pre-hire login before the start date = HIGH (70)
+//   - pre-hire account active more than 14 days before the start date = MEDIUM (50)
+//   - on leave + login within 14 days = HIGH (70)
+//   - suspended + login within 14 days = HIGH (80); suspended + active = HIGH (70)
+//   - orphan (no SoT match) = HIGH (80)
+//   - dormant N+ days = MEDIUM (50)
+//   - elevated or higher privilege tier = MEDIUM (catalog weight, up to 50)
+//   - super-admin privilege tier = HIGH (70)
+//   - privileged + dormant = HIGH (80)
+//   - contractor + broad access = MEDIUM (50)
+//   - fuzzy_ambiguous match = LOW (20)
+//   - normal active user = INFO (0)
+//
+// Unmatched service, shared and break-glass accounts are scored by its nonHuman section.
+//
+//go:embed default_risk_policy.yaml
+var DefaultRiskPolicyText string
+
+// ConditionOp is a comparison operator in a risk rule condition.
+type ConditionOp string
+
+const (
+	OpEquals        ConditionOp = "equals"
+	OpIn            ConditionOp = "in"
+	OpRegex         ConditionOp = "regex"
+	OpOlderThanDays ConditionOp = "older_than_days"
+	OpGreaterThan   ConditionOp = "gt"
+	OpGreaterEqual  ConditionOp = "gte"
+	OpLessThan      ConditionOp = "lt"
+	OpLessEqual     ConditionOp = "lte"
+	OpEmpty         ConditionOp = "empty"
+	OpNotEmpty      ConditionOp = "not_empty"
+	// OpInvalidDate holds for a non-empty value that is not a recognizable date.
+	OpInvalidDate ConditionOp = "invalid_date"
+	// OpWithinDays holds for a date no more than N days before the processing time,
+	// or after it.
+	OpWithinDays ConditionOp = "within_days"
+)
+
+// RuleCondition tests one field of the record being scored. Value holds the operand
+// for single-valued operators (a number or "$param" for numeric operators); Values
+// holds the set for "in".
+type RuleCondition struct {
+	Field  string      `json:"field" yaml:"field"`
+	Op     ConditionOp `json:"op" yaml:"op"`
+	Value  string      `json:"value,omitempty" yaml:"value,omitempty"`
+	Values []string    `json:"values,omitempty" yaml:"values,omitempty"`
+	Not    bool        `json:"not,omitempty" yaml:"not,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// UnmarshalJSON accepts a number or boolean for Value, as YAML does.
+func (c *RuleCondition) UnmarshalJSON(data []byte) error {
+	type plain RuleCondition
+	var raw struct {
+		plain
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = RuleCondition(raw.plain)
+	if len(raw.Value) > 0 && raw.Value[0] == '"' {
+		return json.Unmarshal(raw.Value, &c.Value)
+	}
+	if len(raw.Value) > 0 && string(raw.Value) != "null" {
+		c.Value = string(raw.Value)
+	}
+	return nil
+}
+
+// RiskRule produces a finding when all of its conditions hold.
+type RiskRule struct {
+	Code  string    `json:"code" yaml:"code"`
+	Level RiskLevel `json:"level" yaml:"level"`
+	Score int       `json:"score" yaml:"score"`
+	// Description may reference fields as {field}, and parameters as {params.name}.
+	Description string `json:"description" yaml:"description"`
+	// GapDaysField names a numeric field copied into the finding's GapDays.
+	GapDaysField string `json:"gapDaysField,omitempty" yaml:"gapDaysField,omitempty"`
+	// ScoreField names a numeric field that sets the finding's score, capped at Score.
+	// A missing, zero, or non-numeric value leaves Score.
+	ScoreField string          `json:"scoreField,omitempty" yaml:"scoreField,omitempty"`
+	When       []RuleCondition `json:"when" yaml:"when"`
+}
+
+// RiskPolicy is a declarative set of risk rules. Params are named numbers rules can
+// reference; dormancyDays and terminationGraceDays are overridden by the caller's
+// values when those are positive. Aggregation combines the findings of one record.
+// NonHuman scores unmatched non-human accounts; nil uses the default policy's.
+type RiskPolicy struct {
+	Name        string             `json:"name" yaml:"name"`
+	Params      map[string]float64 `json:"params" yaml:"params"`
+	Aggregation RiskAggregation    `json:"aggregation" yaml:"aggregation"`
+	NonHuman    *NonHumanScoring   `json:"nonHuman,omitempty" yaml:"nonHuman,omitempty"`
+	Rules       []RiskRule         `json:"rules" yaml:"rules"`
+}
+
+// defaultRiskPolicy is parsed from DefaultRiskPolicyText and used when no policy is
+// configured.
+var defaultRiskPolicy = mustParseRiskPolicy(DefaultRiskPolicyText)
+
+// DefaultRiskPolicy returns the built-in risk policy.
+func DefaultRiskPolicy() *RiskPolicy {
+	return defaultRiskPolicy
+}
+
+// ParseRiskPolicy parses a risk policy from JSON or YAML and validates it.
+func ParseRiskPolicy(data []byte) (*RiskPolicy, error) {
+	var policy RiskPolicy
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return nil, fmt.Errorf("risk policy is empty")
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &policy); err != nil {
+			return nil, fmt.Errorf("failed to parse risk policy JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal([]byte(trimmed), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse risk policy YAML: %w", err)
+	}
+
+	if err := policy.compile(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// mustParseRiskPolicy is ParseRiskPolicy for policies known to be valid.
+func mustParseRiskPolicy(text string) *RiskPolicy {
+	policy, err := ParseRiskPolicy([]byte(text))
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// compile validates every rule and compiles regex conditions.
+func (p *RiskPolicy) compile() error {
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("risk policy has no rules")
+	}
+	if err := p.Aggregation.validate(); err != nil {
+		return err
+	}
+	if p.NonHuman != nil {
+		if err := p.NonHuman.validate(); err != nil {
+			return err
+		}
+	}
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Code == "" {
+			return fmt.Errorf("rule %d: code is required", i)
+		}
+		switch rule.Level {
+		case RiskCritical, RiskHigh, RiskMedium, RiskLow, RiskInfo:
+		default:
+			return fmt.Errorf("rule %s: unknown level %q", rule.Code, rule.Level)
+		}
+		if rule.Score < 0 || rule.Score > 100 {
+			return fmt.Errorf("rule %s: score must be between 0 and 100", rule.Code)
+		}
+		if len(rule.When) == 0 {
+			return fmt.Errorf("rule %s: at least one condition is required", rule.Code)
+		}
+		if rule.GapDaysField != "" && !isRiskField(rule.GapDaysField) {
+			return fmt.Errorf("rule %s: unknown gapDaysField %q", rule.Code, rule.GapDaysField)
+		}
+		if rule.ScoreField != "" && !isRiskField(rule.ScoreField) {
+			return fmt.Errorf("rule %s: unknown scoreField %q", rule.Code, rule.ScoreField)
+		}
+
+		for j := range rule.When {
+			cond := &rule.When[j]
+			if !isRiskField(cond.Field) {
+				return fmt.Errorf("rule %s condition %d: unknown field %q", rule.Code, j, cond.Field)
+			}
+			switch cond.Op {
+			case OpEquals, OpEmpty, OpNotEmpty, OpInvalidDate:
+			case OpIn:
+				if len(cond.Values) == 0 {
+					return fmt.Errorf("rule %s condition %d: %q requires values", rule.Code, j, cond.Op)
+				}
+			case OpRegex:
+				re, err := regexp.Compile("(?i)" + cond.Value)
+				if err != nil {
+					return fmt.Errorf("rule %s condition %d: %w", rule.Code, j, err)
+				}
+				cond.re = re
+			case OpOlderThanDays, OpWithinDays, OpGreaterThan, OpGreaterEqual, OpLessThan, OpLessEqual:
+				if _, err := p.number(cond.Value, nil); err != nil {
+					return fmt.Errorf("rule %s condition %d: %w", rule.Code, j, err)
+				}
+			default:
+				return fmt.Errorf("rule %s condition %d: unknown op %q", rule.Code, j, cond.Op)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Evaluate runs every rule against a record and returns the findings of the rules
+// that fired, in policy order. A nil policy evaluates the default policy. The
+// config's dormancy threshold for the record's system and its grace days, when set,
+// replace the policy's dormancyDays and terminationGraceDays params.
+func (p *RiskPolicy) Evaluate(sot *schema.SoTRecord, sat schema.SatelliteRecord, matchType string, cfg RiskConfig) []RiskFinding {
+	if p == nil {
+		p = defaultRiskPolicy
+	}
+
+	overrides := map[string]float64{}
+	if days := cfg.DormancyDaysFor(sat.SourceFile); days > 0 {
+		overrides["dormancyDays"] = float64(days)
+	}
+	if cfg.TerminationGraceDays > 0 {
+		overrides["terminationGraceDays"] = float64(cfg.TerminationGraceDays)
+	}
+
+	loc := cfg.Location()
+	rec := &riskRecord{
+		sot:         sot,
+		sat:         sat,
+		matchType:   matchType,
+		processedAt: time.UnixMilli(cfg.ProcessingTimestamp),
+		loc:         loc,
+		privilege:   cfg.Privileges.Lookup(sat.SourceFile, sat.Role, sat.Entitlement),
+		termination: newTerminationFacts(sot, sat, cfg.ProcessingTimestamp, loc),
+		lifecycle:   newLifecycleFacts(sot, sat, cfg.ProcessingTimestamp, loc),
+	}
+
+	var findings []RiskFinding
+	for _, rule := range p.Rules {
+		if !p.matches(rule, rec, overrides) {
+			continue
+		}
+		f := RiskFinding{
+			Code:        rule.Code,
+			Level:       rule.Level,
+			Score:       rule.Score,
+			Description: p.describe(rule.Description, rec, overrides),
+		}
+		if rule.GapDaysField != "" {
+			if v, ok := rec.field(rule.GapDaysField); ok {
+				f.GapDays, _ = strconv.Atoi(v)
+			}
+		}
+		if rule.ScoreField != "" {
+			if v, ok := rec.field(rule.ScoreField); ok {
+				if n, err := strconv.Atoi(v); err == nil && n > 0 && n < f.Score {
+					f.Score = n
+				}
+			}
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// Score evaluates the policy and aggregates the findings into one level and score.
+func (p *RiskPolicy) Score(sot *schema.SoTRecord, sat schema.SatelliteRecord, matchType string, cfg RiskConfig) (RiskLevel, int) {
+	return p.Aggregate(p.Evaluate(sot, sat, matchType, cfg))
+}
+
+// matches reports whether all of a rule's conditions hold.
+func (p *RiskPolicy) matches(rule RiskRule, rec *riskRecord, overrides map[string]float64) bool {
+	for _, cond := range rule.When {
+		if p.test(cond, rec, overrides) == cond.Not {
+			return false
+		}
+	}
+	return true
+}
+
+// test evaluates a single condition, ignoring its Not flag. Missing fields and
+// values that cannot be parsed as numbers or dates never satisfy a comparison.
+func (p *RiskPolicy) test(cond RuleCondition, rec *riskRecord, overrides map[string]float64) bool {
+	value, present := rec.field(cond.Field)
+	value = strings.TrimSpace(value)
+
+	switch cond.Op {
+	case OpEmpty:
+		return value == ""
+	case OpNotEmpty:
+		return value != ""
+	case OpInvalidDate:
+		_, kind := schema.ParseTimestamp(value, schema.DateOrderMDY)
+		return kind == schema.TimestampInvalid
+	case OpEquals:
+		return strings.EqualFold(value, strings.TrimSpace(cond.Value))
+	case OpIn:
+		for _, v := range cond.Values {
+			if strings.EqualFold(value, strings.TrimSpace(v)) {
+				return true
+			}
+		}
+		return false
+	case OpRegex:
+		return cond.re != nil && cond.re.MatchString(value)
+	}
+
+	if !present || value == "" {
+		return false
+	}
+	operand, err := p.number(cond.Value, overrides)
+	if err != nil {
+		return false
+	}
+
+	if cond.Op == OpOlderThanDays || cond.Op == OpWithinDays {
+		t, ok := parseTimestamp(value, rec.loc)
+		if !ok {
+			return false
+		}
+		older := t.Before(rec.processedAt.AddDate(0, 0, -int(operand)))
+		return older == (cond.Op == OpOlderThanDays)
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	switch cond.Op {
+	case OpGreaterThan:
+		return n > operand
+	case OpGreaterEqual:
+		return n >= operand
+	case OpLessThan:
+		return n < operand
+	case OpLessEqual:
+		return n <= operand
+	}
+	return false
+}
+
+// number resolves a numeric operand, either a literal or a "$param" reference.
+// Caller overrides take precedence over the policy's params.
+func (p *RiskPolicy) number(operand string, overrides map[string]float64) (float64, error) {
+	operand = strings.TrimSpace(operand)
+	if name, ok := strings.CutPrefix(operand, "$"); ok {
+		if v, ok := overrides[name]; ok {
+			return v, nil
+		}
+		if v, ok := p.Params[name]; ok {
+			return v, nil
+		}
+		return 0, fmt.Errorf("unknown parameter %q", name)
+	}
+	n, err := strconv.ParseFloat(operand, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", operand)
+	}
+	return n, nil
+}
+
+// placeholderPattern matches {field} references in rule descriptions.
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z]+\.[A-Za-z]+)\}`)
+
+// describe fills {field} and {params.name} placeholders in a rule description.
+func (p *RiskPolicy) describe(template string, rec *riskRecord, overrides map[string]float64) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		name := match[1 : len(match)-1]
+		if param, ok := strings.CutPrefix(name, "params."); ok {
+			if n, err := p.number("$"+param, overrides); err == nil {
+				return strconv.FormatFloat(n, 'f', -1, 64)
+			}
+			return match
+		}
+		if v, ok := rec.field(name); ok {
+			return v
+		}
+		return match
+	})
+}
+
+// riskRecord is the record a policy is evaluated against.
+type riskRecord struct {
+	sot         *schema.SoTRecord
+	sat         schema.SatelliteRecord
+	matchType   string
+	processedAt time.Time
+	loc         *time.Location
+	privilege   PrivilegeAssessment
+	termination terminationFacts
+	lifecycle   lifecycleFacts
+}
+
+// riskFields maps policy field names to their values. The boolean is false when the
+// field has no value for this record (no SoT record, or an unknown date gap).
+var riskFields = map[string]func(r *riskRecord) (string, bool){
+	"sot.employeeId":       sotField(func(s *schema.SoTRecord) string { return s.EmployeeID }),
+	"sot.email":            sotField(func(s *schema.SoTRecord) string { return s.Email }),
+	"sot.displayName":      sotField(func(s *schema.SoTRecord) string { return s.DisplayName }),
+	"sot.department":       sotField(func(s *schema.SoTRecord) string { return s.Department }),
+	"sot.manager":          sotField(func(s *schema.SoTRecord) string { return s.Manager }),
+	"sot.employmentStatus": sotField(func(s *schema.SoTRecord) string { return s.EmploymentStatus }),
+	"sot.lifecycleState":   sotField(func(s *schema.SoTRecord) string { return string(s.LifecycleState) }),
+	"sot.title":            sotField(func(s *schema.SoTRecord) string { return s.Title }),
+	"sot.startDate":        sotField(func(s *schema.SoTRecord) string { return s.StartDate }),
+	"sot.terminationDate":  sotField(func(s *schema.SoTRecord) string { return s.TerminationDate }),
+	"sot.contractEndDate":  sotField(func(s *schema.SoTRecord) string { return s.ContractEndDate }),
+
+	"satellite.system":        func(r *riskRecord) (string, bool) { return r.sat.SourceFile, true },
+	"satellite.userId":        func(r *riskRecord) (string, bool) { return r.sat.UserId, true },
+	"satellite.email":         func(r *riskRecord) (string, bool) { return r.sat.Email, true },
+	"satellite.displayName":   func(r *riskRecord) (string, bool) { return r.sat.DisplayName, true },
+	"satellite.role":          func(r *riskRecord) (string, bool) { return r.sat.Role, true },
+	"satellite.entitlement":   func(r *riskRecord) (string, bool) { return r.sat.Entitlement, true },
+	"satellite.lastLogin":     func(r *riskRecord) (string, bool) { return r.sat.LastLogin, true },
+	"satellite.accountStatus": func(r *riskRecord) (string, bool) { return r.sat.AccountStatus, true },
+	"satellite.department":    func(r *riskRecord) (string, bool) { return r.sat.Department, true },
+	"satellite.manager":       func(r *riskRecord) (string, bool) { return r.sat.Manager, true },
+	"satellite.title":         func(r *riskRecord) (string, bool) { return r.sat.Title, true },
+	"satellite.accountType":   func(r *riskRecord) (string, bool) { return r.sat.AccountType, true },
+	"satellite.disabledDate":  func(r *riskRecord) (string, bool) { return r.sat.DisabledDate, true },
+	"satellite.createdDate":   func(r *riskRecord) (string, bool) { return r.sat.CreatedDate, true },
+
+	"match.type":              func(r *riskRecord) (string, bool) { return r.matchType, true },
+	"account.privileged":      func(r *riskRecord) (string, bool) { return strconv.FormatBool(r.privilege.Privileged()), true },
+	"account.privilegeTier":   func(r *riskRecord) (string, bool) { return string(r.privilege.Tier), true },
+	"account.privilegeWeight": func(r *riskRecord) (string, bool) { return strconv.Itoa(r.privilege.Weight), true },
+	"account.privilegeGrant":  func(r *riskRecord) (string, bool) { return r.privilege.Grant, true },
+
+	"termination.daysSince": func(r *riskRecord) (string, bool) {
+		return gapField(r.termination.hasTermDate, r.termination.daysSince)
+	},
+	"termination.loginGapDays": func(r *riskRecord) (string, bool) {
+		return gapField(r.termination.hasLoginGap, r.termination.loginGapDays)
+	},
+	"termination.disabledGapDays": func(r *riskRecord) (string, bool) {
+		return gapField(r.termination.hasDisabledGap, r.termination.disabledGapDays)
+	},
+
+	"lifecycle.state": func(r *riskRecord) (string, bool) {
+		return string(r.lifecycle.state), r.sot != nil
+	},
+	"lifecycle.daysUntilStart": func(r *riskRecord) (string, bool) {
+		return gapField(r.lifecycle.hasDaysUntilStart, r.lifecycle.daysUntilStart)
+	},
+	"lifecycle.loginBeforeStartDays": func(r *riskRecord) (string, bool) {
+		return gapField(r.lifecycle.hasLoginBeforeStart, r.lifecycle.loginBeforeStartDays)
+	},
+	"lifecycle.daysPastContractEnd": func(r *riskRecord) (string, bool) {
+		return gapField(r.lifecycle.hasDaysPastContractEnd, r.lifecycle.daysPastContractEnd)
+	},
+	"lifecycle.loginAfterContractEndDays": func(r *riskRecord) (string, bool) {
+		return gapField(r.lifecycle.hasLoginAfterContractEnd, r.lifecycle.loginAfterContractEndDays)
+	},
+}
+
+// field returns the value of a policy field for this record.
+func (r *riskRecord) field(name string) (string, bool) {
+	get, ok := riskFields[name]
+	if !ok {
+		return "", false
+	}
+	return get(r)
+}
+
+// isRiskField reports whether name is a field policies can reference.
+func isRiskField(name string) bool {
+	_, ok := riskFields[name]
+	return ok
+}
+
+// sotField adapts a SoT record accessor; the field is absent for orphans.
+func sotField(get func(*schema.SoTRecord) string) func(r *riskRecord) (string, bool) {
+	return func(r *riskRecord) (string, bool) {
+		if r.sot == nil {
+			return "", false
+		}
+		return get(r.sot), true
+	}
+}
+
+// gapField formats a day count that is only meaningful when known.
+func gapField(known bool, days int) (string, bool) {
+	if !known {
+		return "", false
+	}
+	return strconv.Itoa(days), true
+}
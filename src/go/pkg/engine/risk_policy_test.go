@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"uar/pkg/schema"
+)
+
+func TestRiskPolicyEvaluate(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	active := &schema.SoTRecord{CanonicalID: "jane@acme.com", EmploymentStatus: "active", LifecycleState: schema.LifecycleActive}
+	terminated := &schema.SoTRecord{CanonicalID: "sam@acme.com", EmploymentStatus: "terminated", LifecycleState: schema.LifecycleTerminated, TerminationDate: "2026-05-02"}
+	custom, err := ParseRiskPolicy([]byte(`
+params:
+  limit: 10
+rules:
+  - code: not_okta
+    level: LOW
+    score: 10
+    description: "{satellite.system} is not okta (limit {params.limit})"
+    when:
+      - {field: satellite.system, op: equals, value: okta, not: true}
+`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		policy    *RiskPolicy
+		sot       *schema.SoTRecord
+		sat       schema.SatelliteRecord
+		matchType string
+		cfg       RiskConfig
+		wantCodes []string
+		wantGap   int
+		wantDesc  string
+	}{
+		{
+			name:      "recent login is clean",
+			sot:       active,
+			sat:       schema.SatelliteRecord{SourceFile: "okta", Role: "Viewer", AccountStatus: "active", LastLogin: "2026-05-30"},
+			matchType: "exact_email",
+		},
+		{
+			name:      "terminated with active account",
+			sot:       terminated,
+			sat:       schema.SatelliteRecord{SourceFile: "okta", Role: "Viewer", AccountStatus: "active", LastLogin: "2026-04-20"},
+			matchType: "exact_email",
+			wantCodes: []string{"terminated_active"},
+			wantGap:   30,
+		},
+		{
+			name:      "login after termination",
+			sot:       terminated,
+			sat:       schema.SatelliteRecord{SourceFile: "okta", Role: "Viewer", AccountStatus: "active", LastLogin: "2026-05-07"},
+			matchType: "exact_email",
+			wantCodes: []string{"post_termination_login", "terminated_active"},
+			wantGap:   5,
+		},
+		{
+			name:      "orphan",
+			sat:       schema.SatelliteRecord{SourceFile: "okta", Role: "Viewer", AccountStatus: "active", LastLogin: "2026-05-30"},
+			matchType: "orphan",
+			wantCodes: []string{"orphan"},
+		},
+		{
+			name:      "dormant with the policy default",
+			sot:       active,
+			sat:       schema.SatelliteRecord{SourceFile: "okta", Role: "Viewer", AccountStatus: "active", LastLogin: "2025-11-01"},
+			matchType: "exact_email",
+			wantCodes: []string{"dormant"},
+		},
+		{
+			name:      "system dormancy threshold replaces the default",
+			sot:       active,
+			sat:       schema.SatelliteRecord{SourceFile: "okta_export", Role: "Viewer", AccountStatus: "active", LastLogin: "2025-11-01"},
+			matchType: "exact_email",
+			cfg:       RiskConfig{SystemDormancyDays: map[string]int{"okta": 365}},
+		},
+		{
+			name:      "unparseable login",
+			sot:       active,
+			sat:       schema.SatelliteRecord{SourceFile: "okta", Role: "Viewer", AccountStatus: "active", LastLogin: "last tuesday"},
+			matchType: "exact_email",
+			wantCodes: []string{"unparseable_timestamp"},
+		},
+		{
+			name:      "custom policy with negated condition and params",
+			policy:    custom,
+			sat:       schema.SatelliteRecord{SourceFile: "github"},
+			matchType: "orphan",
+			wantCodes: []string{"not_okta"},
+			wantDesc:  "github is not okta (limit 10)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.ProcessingTimestamp = now
+			findings := tt.policy.Evaluate(tt.sot, tt.sat, tt.matchType, tt.cfg)
+
+			var codes []string
+			for _, f := range findings {
+				codes = append(codes, f.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Fatalf("codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if len(findings) > 0 && findings[0].GapDays != tt.wantGap {
+				t.Errorf("GapDays = %d, want %d", findings[0].GapDays, tt.wantGap)
+			}
+			if tt.wantDesc != "" && findings[0].Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", findings[0].Description, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestParseRiskPolicyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"no rules", "name: x\nrules: []\n"},
+		{"unknown field", "rules:\n  - {code: a, level: LOW, score: 1, when: [{field: sot.nope, op: empty}]}\n"},
+		{"unknown op", "rules:\n  - {code: a, level: LOW, score: 1, when: [{field: match.type, op: like}]}\n"},
+		{"unknown param", "rules:\n  - {code: a, level: LOW, score: 1, when: [{field: satellite.lastLogin, op: older_than_days, value: $nope}]}\n"},
+		{"score out of range", "rules:\n  - {code: a, level: LOW, score: 101, when: [{field: match.type, op: empty}]}\n"},
+		{"non-human level unknown", "nonHuman: {shared: {level: SEVERE, score: 70}}\nrules:\n  - {code: a, level: LOW, score: 1, when: [{field: match.type, op: empty}]}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseRiskPolicy([]byte(tt.input)); err == nil {
+				t.Error("ParseRiskPolicy() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestScoreNonHuman(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	cfg := RiskConfig{ProcessingTimestamp: now, Privileges: DefaultPrivilegeCatalog()}
+	owner := &schema.SoTRecord{CanonicalID: "jane@acme.com"}
+	custom, err := ParseRiskPolicy([]byte(`
+nonHuman:
+  breakGlassUsed: {level: CRITICAL, score: 95}
+  breakGlassUnknown: {level: HIGH, score: 75}
+  breakGlassStandby: {level: INFO, score: 0}
+  shared: {level: MEDIUM, score: 45}
+  serviceNoOwner: {level: MEDIUM, score: 40}
+  serviceOwned: {level: INFO, score: 5}
+  privilegedBonus: 20
+  privilegedCap: 60
+rules:
+  - {code: orphan, level: HIGH, score: 80, when: [{field: match.type, op: equals, value: orphan}]}
+`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		policy    *RiskPolicy
+		class     AccountClass
+		sat       schema.SatelliteRecord
+		owner     *schema.SoTRecord
+		wantCode  string
+		wantLevel RiskLevel
+		wantScore int
+	}{
+		{name: "break-glass used recently", class: AccountBreakGlass, sat: schema.SatelliteRecord{LastLogin: "2026-05-30"}, wantCode: FindingBreakGlassUsed, wantLevel: RiskHigh, wantScore: 80},
+		{name: "break-glass idle", class: AccountBreakGlass, sat: schema.SatelliteRecord{LastLogin: "2025-01-01"}, wantCode: FindingBreakGlassStandby, wantLevel: RiskLow, wantScore: 20},
+		{name: "break-glass never used", class: AccountBreakGlass, wantCode: FindingBreakGlassStandby, wantLevel: RiskLow, wantScore: 20},
+		{name: "break-glass unparseable login is unknown", class: AccountBreakGlass, sat: schema.SatelliteRecord{LastLogin: "last Tuesday"}, wantCode: FindingBreakGlassUnknown, wantLevel: RiskMedium, wantScore: 50},
+		{name: "shared", class: AccountShared, wantCode: FindingSharedAccount, wantLevel: RiskHigh, wantScore: 70},
+		{name: "service without owner", class: AccountService, wantCode: FindingServiceNoOwner, wantLevel: RiskHigh, wantScore: 70},
+		{name: "service with owner", class: AccountService, owner: owner, wantCode: FindingServiceOwned, wantLevel: RiskLow, wantScore: 20},
+		{name: "privileged adds the bonus", class: AccountShared, sat: schema.SatelliteRecord{Role: "Admin"}, wantCode: FindingSharedAccount, wantLevel: RiskHigh, wantScore: 80},
+		{name: "privileged bonus is capped", class: AccountBreakGlass, sat: schema.SatelliteRecord{Role: "Admin", LastLogin: "2026-05-30"}, wantCode: FindingBreakGlassUsed, wantLevel: RiskHigh, wantScore: 90},
+		{name: "policy scores", policy: custom, class: AccountShared, wantCode: FindingSharedAccount, wantLevel: RiskMedium, wantScore: 45},
+		{name: "policy bonus and cap", policy: custom, class: AccountShared, sat: schema.SatelliteRecord{Role: "Admin"}, wantCode: FindingSharedAccount, wantLevel: RiskMedium, wantScore: 60},
+		{name: "policy unknown break-glass use", policy: custom, class: AccountBreakGlass, sat: schema.SatelliteRecord{LastLogin: "n/a"}, wantCode: FindingBreakGlassUnknown, wantLevel: RiskHigh, wantScore: 75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sat := tt.sat
+			sat.SourceFile = "okta"
+			nh := OrphanRecord{Satellite: sat, Owner: tt.owner, Classification: AccountClassification{Class: tt.class}}
+
+			f := tt.policy.ScoreNonHuman(nh, cfg)
+
+			if f.Code != tt.wantCode || f.Level != tt.wantLevel || f.Score != tt.wantScore {
+				t.Errorf("ScoreNonHuman() = %s %s %d, want %s %s %d", f.Code, f.Level, f.Score, tt.wantCode, tt.wantLevel, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestPrivilegeWeightScoresPrivilegedRule(t *testing.T) {
+	active := &schema.SoTRecord{CanonicalID: "jane@acme.com", EmploymentStatus: "active", LifecycleState: schema.LifecycleActive}
+	sat := schema.SatelliteRecord{SourceFile: "crm", Role: "Deal Desk", AccountStatus: "active"}
+
+	tests := []struct {
+		name      string
+		weight    int
+		wantScore int
+	}{
+		{name: "weight sets the score", weight: 30, wantScore: 30},
+		{name: "another weight", weight: 45, wantScore: 45},
+		{name: "weight is capped at the rule score", weight: 90, wantScore: 50},
+		{name: "no weight keeps the rule score", weight: 0, wantScore: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := ParsePrivilegeCatalog([]byte(fmt.Sprintf(
+				"systems:\n  crm:\n    - {match: exact, value: Deal Desk, tier: elevated, weight: %d}\n", tt.weight)))
+			if err != nil {
+				t.Fatalf("ParsePrivilegeCatalog() error = %v", err)
+			}
+			cfg := RiskConfig{ProcessingTimestamp: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), Privileges: catalog}
+
+			var got *RiskFinding
+			findings := DefaultRiskPolicy().Evaluate(active, sat, "exact_email", cfg)
+			for i := range findings {
+				if findings[i].Code == "privileged" {
+					got = &findings[i]
+				}
+			}
+			if got == nil {
+				t.Fatalf("Evaluate() = %+v, want a privileged finding", findings)
+			}
+			if got.Score != tt.wantScore {
+				t.Errorf("privileged score = %d, want %d", got.Score, tt.wantScore)
+			}
+		})
+	}
+}
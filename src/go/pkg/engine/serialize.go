@@ -17,35 +17,13 @@ type serializedIndex struct {
 // SerializeSoTIndex converts a SoTIndex to a JSON string for transfer
 // between Web Workers. The serialized form includes all records and stats.
 // Maps are rebuilt on the receiving end via DeserializeSoTIndex.
+// Records are written in SoT input order, so identical inputs serialize to
+// identical bytes.
 func SerializeSoTIndex(index *SoTIndex) string {
-	// Collect all unique records from the index.
-	// We use ByEmail as the primary source and add any records only in ByEmployeeID or ByName.
-	seen := make(map[*schema.SoTRecord]bool)
-	var records []*schema.SoTRecord
-
-	for _, rec := range index.ByEmail {
-		if !seen[rec] {
-			seen[rec] = true
-			records = append(records, rec)
-		}
-	}
-	for _, rec := range index.ByEmployeeID {
-		if !seen[rec] {
-			seen[rec] = true
-			records = append(records, rec)
-		}
-	}
-	for _, recs := range index.ByName {
-		for _, rec := range recs {
-			if !seen[rec] {
-				seen[rec] = true
-				records = append(records, rec)
-			}
-		}
-	}
-
+	// Serialize every SoT row, including rows shadowed by key collisions,
+	// so the receiving worker can rebuild the index and its duplicate findings.
 	si := serializedIndex{
-		Records: records,
+		Records: index.Records,
 		Stats:   index.Stats,
 	}
 
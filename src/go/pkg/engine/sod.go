@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Segregation-of-duties finding scores by level.
+const (
+	sodCriticalScore = 100
+	sodHighScore     = 80
+)
+
+// EntitlementMatcher selects grants by system and a case-insensitive regular
+// expression tested against each role and entitlement value. An empty System
+// matches every system; otherwise System covers systems as a privilege catalog key
+// does.
+type EntitlementMatcher struct {
+	System  string `json:"system" yaml:"system"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+
+	re *regexp.Regexp
+}
+
+// SoDRule is a toxic combination: holding a grant matched by Left and a different
+// grant matched by Right is a conflict.
+type SoDRule struct {
+	ID          string             `json:"id" yaml:"id"`
+	Description string             `json:"description" yaml:"description"`
+	Level       RiskLevel          `json:"level" yaml:"level"` // CRITICAL or HIGH
+	Left        EntitlementMatcher `json:"left" yaml:"left"`
+	Right       EntitlementMatcher `json:"right" yaml:"right"`
+}
+
+// SoDMatrix is a set of segregation-of-duties rules.
+type SoDMatrix struct {
+	Rules []SoDRule `json:"rules" yaml:"rules"`
+}
+
+// SoDGrant is one role or entitlement value a person holds in one system.
+type SoDGrant struct {
+	System    string `json:"system"`
+	Grant     string `json:"grant"`
+	SourceRow int    `json:"sourceRow"`
+}
+
+// SoDFinding is a person holding both sides of a SoD rule.
+type SoDFinding struct {
+	RuleID      string    `json:"ruleId"`
+	Level       RiskLevel `json:"level"`
+	Score       int       `json:"score"`
+	Description string    `json:"description"`
+	Left        SoDGrant  `json:"left"`
+	Right       SoDGrant  `json:"right"`
+}
+
+// ParseSoDMatrix parses a SoD rule matrix from JSON or YAML and compiles its patterns.
+func ParseSoDMatrix(data []byte) (*SoDMatrix, error) {
+	var matrix SoDMatrix
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return &matrix, nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &matrix); err != nil {
+			return nil, fmt.Errorf("failed to parse SoD matrix JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal([]byte(trimmed), &matrix); err != nil {
+		return nil, fmt.Errorf("failed to parse SoD matrix YAML: %w", err)
+	}
+
+	for i := range matrix.Rules {
+		rule := &matrix.Rules[i]
+		if rule.ID == "" {
+			return nil, fmt.Errorf("SoD rule %d: id is required", i)
+		}
+		if rule.Level != RiskCritical && rule.Level != RiskHigh {
+			return nil, fmt.Errorf("SoD rule %s: level must be CRITICAL or HIGH", rule.ID)
+		}
+		for _, m := range []*EntitlementMatcher{&rule.Left, &rule.Right} {
+			if m.Pattern == "" {
+				return nil, fmt.Errorf("SoD rule %s: left and right patterns are required", rule.ID)
+			}
+			re, err := regexp.Compile("(?i)" + m.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("SoD rule %s: %w", rule.ID, err)
+			}
+			m.re = re
+		}
+	}
+
+	return &matrix, nil
+}
+
+// Evaluate checks one person's combined grants across all systems and returns a
+// finding for each distinct pair of grants that satisfies a rule, in rule order.
+// A grant never conflicts with itself, including the same grant held on another row
+// of the same system.
+func (m *SoDMatrix) Evaluate(grants []SoDGrant) []SoDFinding {
+	if m == nil {
+		return nil
+	}
+
+	var findings []SoDFinding
+	for _, rule := range m.Rules {
+		seen := make(map[[2]string]bool)
+		for _, left := range grants {
+			if !rule.Left.matches(left) {
+				continue
+			}
+			for _, right := range grants {
+				if sameGrant(left, right) || !rule.Right.matches(right) {
+					continue
+				}
+				// (A, B) and (B, A) are the same conflict when both sides match both
+				// matchers, so the pair is keyed in sorted order
+				pair := [2]string{grantKey(left.System, strings.TrimSpace(left.Grant)), grantKey(right.System, strings.TrimSpace(right.Grant))}
+				if pair[1] < pair[0] {
+					pair[0], pair[1] = pair[1], pair[0]
+				}
+				if seen[pair] {
+					continue
+				}
+				seen[pair] = true
+
+				score := sodHighScore
+				if rule.Level == RiskCritical {
+					score = sodCriticalScore
+				}
+				description := fmt.Sprintf("holds %q in %s and %q in %s", left.Grant, left.System, right.Grant, right.System)
+				if rule.Description != "" {
+					description = rule.Description + ": " + description
+				}
+				findings = append(findings, SoDFinding{
+					RuleID:      rule.ID,
+					Level:       rule.Level,
+					Score:       score,
+					Description: description,
+					Left:        left,
+					Right:       right,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// matches reports whether a grant is in the matcher's system and matches its pattern.
+// The system covers grants the way a privilege catalog key does, so "aws_prod" also
+// matches "aws_prod_export".
+func (em EntitlementMatcher) matches(g SoDGrant) bool {
+	if em.System != "" && !catalogKeyApplies(em.System, g.System, grantTokens(g.System)) {
+		return false
+	}
+	return em.re != nil && em.re.MatchString(g.Grant)
+}
+
+// sameGrant reports whether two grants are the same value in the same system.
+func sameGrant(a, b SoDGrant) bool {
+	return grantKey(a.System, strings.TrimSpace(a.Grant)) == grantKey(b.System, strings.TrimSpace(b.Grant))
+}
+
+// SplitGrants splits a role or entitlement cell into its individual values.
+// Exports often list several groups in one cell separated by commas, semicolons,
+// or pipes.
+func SplitGrants(value string) []string {
+	var grants []string
+	for _, g := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	}) {
+		if g = strings.TrimSpace(g); g != "" {
+			grants = append(grants, g)
+		}
+	}
+	return grants
+}
@@ -0,0 +1,101 @@
+package engine
+
+import "testing"
+
+func TestSoDMatrixEvaluate(t *testing.T) {
+	matrix, err := ParseSoDMatrix([]byte(`
+rules:
+  - id: pay-approve
+    level: CRITICAL
+    left: {system: sap, pattern: "vendor.?create"}
+    right: {system: sap, pattern: "payment.?approve"}
+  - id: prod-deploy
+    level: HIGH
+    left: {system: aws_prod, pattern: admin}
+    right: {pattern: "deploy|admin"}
+`))
+	if err != nil {
+		t.Fatalf("ParseSoDMatrix() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		grants []SoDGrant
+		want   []string // rule IDs in order
+	}{
+		{
+			name: "both sides in one system",
+			grants: []SoDGrant{
+				{System: "sap", Grant: "Vendor_Create", SourceRow: 1},
+				{System: "sap", Grant: "Payment Approve", SourceRow: 2},
+			},
+			want: []string{"pay-approve"},
+		},
+		{
+			name:   "one side only",
+			grants: []SoDGrant{{System: "sap", Grant: "Vendor_Create", SourceRow: 1}},
+		},
+		{
+			name: "matcher system covers a longer system name",
+			grants: []SoDGrant{
+				{System: "aws_prod_export", Grant: "AdministratorAccess", SourceRow: 1},
+				{System: "github", Grant: "deploy-bot", SourceRow: 2},
+			},
+			want: []string{"prod-deploy"},
+		},
+		{
+			name: "other systems do not match",
+			grants: []SoDGrant{
+				{System: "aws_dev", Grant: "AdministratorAccess", SourceRow: 1},
+				{System: "github", Grant: "deploy-bot", SourceRow: 2},
+			},
+		},
+		{
+			name: "same grant on two rows is not a conflict",
+			grants: []SoDGrant{
+				{System: "aws_prod", Grant: "Admin", SourceRow: 1},
+				{System: "aws_prod", Grant: "admin", SourceRow: 2},
+			},
+		},
+		{
+			name: "a pair matching both ways is reported once",
+			grants: []SoDGrant{
+				{System: "aws_prod", Grant: "Admin", SourceRow: 1},
+				{System: "aws_prod", Grant: "PowerAdmin", SourceRow: 2},
+			},
+			want: []string{"prod-deploy"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := matrix.Evaluate(tt.grants)
+			if len(findings) != len(tt.want) {
+				t.Fatalf("got %d findings %+v, want %v", len(findings), findings, tt.want)
+			}
+			for i, f := range findings {
+				if f.RuleID != tt.want[i] {
+					t.Errorf("finding %d rule = %s, want %s", i, f.RuleID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSoDMatrixErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing id", `{"rules": [{"level": "HIGH", "left": {"pattern": "a"}, "right": {"pattern": "b"}}]}`},
+		{"medium level", `{"rules": [{"id": "r", "level": "MEDIUM", "left": {"pattern": "a"}, "right": {"pattern": "b"}}]}`},
+		{"missing pattern", `{"rules": [{"id": "r", "level": "HIGH", "left": {"pattern": "a"}, "right": {}}]}`},
+		{"bad regex", `{"rules": [{"id": "r", "level": "HIGH", "left": {"pattern": "("}, "right": {"pattern": "b"}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSoDMatrix([]byte(tt.input)); err == nil {
+				t.Error("ParseSoDMatrix() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ type SoTIndex struct {
 	ByEmail      map[string]*schema.SoTRecord   `json:"byEmail"`
 	ByEmployeeID map[string]*schema.SoTRecord   `json:"byEmployeeId"`
 	ByName       map[string][]*schema.SoTRecord  `json:"byName"`
+	Records      []*schema.SoTRecord             `json:"records"`
 	Stats        IndexStats                      `json:"stats"`
 }
 
@@ -24,12 +25,14 @@ type IndexStats struct {
 
 // BuildSoTIndex constructs a SoTIndex from a slice of SoT records.
 // It indexes records into three maps: ByEmail (lowercase), ByEmployeeID, and ByName (normalized).
+// Records keeps every input record in order, including those shadowed by a key collision.
 // It computes aggregate stats including active/terminated counts and unique emails.
 func BuildSoTIndex(records []*schema.SoTRecord) *SoTIndex {
 	index := &SoTIndex{
 		ByEmail:      make(map[string]*schema.SoTRecord, len(records)),
 		ByEmployeeID: make(map[string]*schema.SoTRecord, len(records)),
 		ByName:       make(map[string][]*schema.SoTRecord, len(records)),
+		Records:      records,
 	}
 
 	activeCount := 0
@@ -56,15 +59,11 @@ func BuildSoTIndex(records []*schema.SoTRecord) *SoTIndex {
 			index.ByName[rec.NormalizedName] = append(index.ByName[rec.NormalizedName], rec)
 		}
 
-		// Count employment status
-		status := strings.ToLower(rec.EmploymentStatus)
-		switch status {
-		case "terminated":
+		// Count lifecycle state; leave, suspended, pre-hire, etc. count as active
+		// for stats purposes
+		if rec.LifecycleState == schema.LifecycleTerminated {
 			terminatedCount++
-		case "active", "":
-			activeCount++
-		default:
-			// leave, contractor, etc. — count as active for stats purposes
+		} else {
 			activeCount++
 		}
 	}
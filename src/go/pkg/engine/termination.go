@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"time"
+
+	"uar/pkg/schema"
+)
+
+// terminationFacts are the date gaps termination rules compare, measured from the
+// SoT termination date. Each gap is only set when both of its dates are known.
+type terminationFacts struct {
+	hasTermDate     bool
+	daysSince       int // termination date to the processing timestamp
+	hasLoginGap     bool
+	loginGapDays    int // set only for a login on or after the termination cutoff
+	hasDisabledGap  bool
+	disabledGapDays int
+}
+
+// newTerminationFacts computes the termination date gaps for a record, reading dates
+// without an offset in loc. A termination date without a time of day covers that
+// whole day.
+func newTerminationFacts(sot *schema.SoTRecord, sat schema.SatelliteRecord, processingTimestamp int64, loc *time.Location) terminationFacts {
+	var facts terminationFacts
+	if sot == nil {
+		return facts
+	}
+
+	termDate, ok := parseTimestamp(sot.TerminationDate, loc)
+	if !ok {
+		return facts
+	}
+	facts.hasTermDate = true
+	facts.daysSince = daysBetween(termDate, time.UnixMilli(processingTimestamp))
+
+	cutoff := dayCutoff(termDate)
+	if loginTime, ok := parseTimestamp(sat.LastLogin, loc); ok && !loginTime.Before(cutoff) {
+		facts.hasLoginGap = true
+		facts.loginGapDays = daysBetween(termDate, loginTime)
+	}
+	if disabledTime, ok := parseTimestamp(sat.DisabledDate, loc); ok {
+		facts.hasDisabledGap = true
+		facts.disabledGapDays = daysBetween(termDate, disabledTime)
+	}
+
+	return facts
+}
+
+// dayCutoff returns the first instant after a date that ends something: the next
+// midnight for a date without a time of day, since the date covers that whole day,
+// or the time itself otherwise.
+func dayCutoff(t time.Time) time.Time {
+	if h, m, s := t.Clock(); h == 0 && m == 0 && s == 0 && t.Nanosecond() == 0 {
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// daysBetween returns the whole days from a to b, or 0 if b is not after a.
+func daysBetween(a, b time.Time) int {
+	if !b.After(a) {
+		return 0
+	}
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"uar/pkg/schema"
+)
+
+func TestNewTerminationFacts(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	tests := []struct {
+		name     string
+		termDate string
+		sat      schema.SatelliteRecord
+		want     terminationFacts
+		noSoT    bool
+	}{
+		{
+			name:  "no SoT record",
+			noSoT: true,
+		},
+		{
+			name:     "no termination date",
+			termDate: "",
+			sat:      schema.SatelliteRecord{LastLogin: "2026-05-20"},
+		},
+		{
+			name:     "unparseable termination date",
+			termDate: "last spring",
+			sat:      schema.SatelliteRecord{LastLogin: "2026-05-20", DisabledDate: "2026-05-21"},
+		},
+		{
+			name:     "login before termination has no login gap",
+			termDate: "2026-05-01",
+			sat:      schema.SatelliteRecord{LastLogin: "2026-04-30T09:00:00Z"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 31},
+		},
+		{
+			name:     "login on the termination day is covered by that day",
+			termDate: "2026-05-01",
+			sat:      schema.SatelliteRecord{LastLogin: "2026-05-01T17:00:00Z"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 31},
+		},
+		{
+			name:     "login the day after termination",
+			termDate: "2026-05-01",
+			sat:      schema.SatelliteRecord{LastLogin: "2026-05-02T08:00:00Z"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 31, hasLoginGap: true, loginGapDays: 1},
+		},
+		{
+			name:     "termination with a time of day cuts off at that time",
+			termDate: "2026-05-01T12:00:00Z",
+			sat:      schema.SatelliteRecord{LastLogin: "2026-05-01T13:00:00Z"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 30, hasLoginGap: true, loginGapDays: 0},
+		},
+		{
+			name:     "unparseable login is ignored",
+			termDate: "2026-05-01",
+			sat:      schema.SatelliteRecord{LastLogin: "yesterday"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 31},
+		},
+		{
+			name:     "disabled date gap",
+			termDate: "2026-05-01",
+			sat:      schema.SatelliteRecord{DisabledDate: "2026-05-11"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 31, hasDisabledGap: true, disabledGapDays: 10},
+		},
+		{
+			name:     "disabled before termination is a zero gap",
+			termDate: "2026-05-01",
+			sat:      schema.SatelliteRecord{DisabledDate: "2026-04-25"},
+			want:     terminationFacts{hasTermDate: true, daysSince: 31, hasDisabledGap: true},
+		},
+		{
+			name:     "termination in the future",
+			termDate: "2026-07-01",
+			want:     terminationFacts{hasTermDate: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sot *schema.SoTRecord
+			if !tt.noSoT {
+				sot = &schema.SoTRecord{TerminationDate: tt.termDate}
+			}
+			got := newTerminationFacts(sot, tt.sat, now, time.UTC)
+			if got != tt.want {
+				t.Errorf("newTerminationFacts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminationRules(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	terminated := func(date string) *schema.SoTRecord {
+		return &schema.SoTRecord{CanonicalID: "sam@acme.com", EmploymentStatus: "terminated", LifecycleState: schema.LifecycleTerminated, TerminationDate: date}
+	}
+
+	tests := []struct {
+		name      string
+		sot       *schema.SoTRecord
+		sat       schema.SatelliteRecord
+		cfg       RiskConfig
+		wantCodes []string
+		wantGap   int
+	}{
+		{
+			name:      "disabled within the grace window",
+			sot:       terminated("2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "disabled", DisabledDate: "2026-05-08"},
+			wantCodes: nil,
+		},
+		{
+			name:      "disabled one day past the grace window",
+			sot:       terminated("2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "disabled", DisabledDate: "2026-05-09"},
+			wantCodes: []string{"late_deprovisioning"},
+			wantGap:   8,
+		},
+		{
+			name:      "configured grace window",
+			sot:       terminated("2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "disabled", DisabledDate: "2026-05-09"},
+			cfg:       RiskConfig{TerminationGraceDays: 10},
+			wantCodes: nil,
+		},
+		{
+			name:      "access after termination",
+			sot:       terminated("2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "disabled", DisabledDate: "2026-05-03", LastLogin: "2026-05-02T10:00:00Z"},
+			wantCodes: []string{"post_termination_login"},
+			wantGap:   1,
+		},
+		{
+			name:      "unparseable termination date still flags an active account",
+			sot:       terminated("sometime in May"),
+			sat:       schema.SatelliteRecord{AccountStatus: "active", LastLogin: "2026-05-30"},
+			wantCodes: []string{"terminated_active", "unparseable_timestamp"},
+		},
+		{
+			name:      "unparseable disabled date is reported, not scored as late",
+			sot:       terminated("2026-05-01"),
+			sat:       schema.SatelliteRecord{AccountStatus: "disabled", DisabledDate: "soon"},
+			wantCodes: []string{"unparseable_timestamp"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.ProcessingTimestamp = now
+			findings := DefaultRiskPolicy().Evaluate(tt.sot, tt.sat, "exact_email", cfg)
+
+			var codes []string
+			for _, f := range findings {
+				codes = append(codes, f.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Fatalf("Evaluate() codes = %v, want %v", codes, tt.wantCodes)
+			}
+			if len(findings) > 0 && findings[0].GapDays != tt.wantGap {
+				t.Errorf("GapDays = %d, want %d", findings[0].GapDays, tt.wantGap)
+			}
+		})
+	}
+}
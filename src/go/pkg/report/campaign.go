@@ -0,0 +1,534 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"uar/pkg/engine"
+	"uar/pkg/schema"
+)
+
+// ReviewerSource is where a review item's reviewer came from.
+type ReviewerSource string
+
+const (
+	// ReviewerEntitlementOwner is the owner of one of the account's grants.
+	ReviewerEntitlementOwner ReviewerSource = "entitlement_owner"
+	// ReviewerSystemOwner is the owner of the account's system.
+	ReviewerSystemOwner ReviewerSource = "system_owner"
+	// ReviewerManager is the account holder's line manager.
+	ReviewerManager ReviewerSource = "manager"
+	// ReviewerFallback is the campaign's fallback reviewer.
+	ReviewerFallback ReviewerSource = "fallback"
+)
+
+// ReassignReason is why a candidate reviewer was passed over.
+type ReassignReason string
+
+const (
+	// ReassignTerminated is a candidate who is terminated in the SoT.
+	ReassignTerminated ReassignReason = "reviewer_terminated"
+	// ReassignSelfReview is a candidate who holds the account under review.
+	ReassignSelfReview ReassignReason = "self_review"
+	// ReassignNotFound is an owner or fallback value that matches nobody in the SoT.
+	ReassignNotFound ReassignReason = "reviewer_not_found"
+)
+
+// DecisionAction is a reviewer's verdict on an account.
+type DecisionAction string
+
+const (
+	DecisionApprove DecisionAction = "approve"
+	DecisionRevoke  DecisionAction = "revoke"
+)
+
+// CampaignConfig configures BuildCampaign. Owner and reviewer values are resolved to
+// SoT people like manager values: by email, employee ID, or name.
+type CampaignConfig struct {
+	Name string `json:"name" yaml:"name"`
+	// Sources is the order reviewer sources are tried in. Empty means entitlement
+	// owner, then system owner, then manager.
+	Sources []ReviewerSource `json:"sources,omitempty" yaml:"sources"`
+	// SystemOwners maps system names to owners. Keys match system names like
+	// privilege catalog keys: "aws_prod" covers "aws_prod_export".
+	SystemOwners map[string]string `json:"systemOwners,omitempty" yaml:"systemOwners"`
+	// EntitlementOwners assigns individual grants to owners; the first matching
+	// entry wins.
+	EntitlementOwners []EntitlementOwner `json:"entitlementOwners,omitempty" yaml:"entitlementOwners"`
+	// FallbackReviewer reviews accounts no source could assign, e.g. the IAM team lead.
+	FallbackReviewer string `json:"fallbackReviewer,omitempty" yaml:"fallbackReviewer"`
+}
+
+// EntitlementOwner names the owner of a role or entitlement value.
+type EntitlementOwner struct {
+	// System is a system key as in CampaignConfig.SystemOwners; empty matches every system.
+	System string `json:"system,omitempty" yaml:"system"`
+	// Grant is a single role or entitlement value, compared ignoring case.
+	Grant string `json:"grant" yaml:"grant"`
+	Owner string `json:"owner" yaml:"owner"`
+}
+
+// defaultReviewerSources is the source order used when CampaignConfig.Sources is empty.
+var defaultReviewerSources = []ReviewerSource{ReviewerEntitlementOwner, ReviewerSystemOwner, ReviewerManager}
+
+// ParseCampaignConfig parses a campaign configuration from JSON or YAML and validates it.
+// Empty input gives the zero configuration, which assigns every account to its
+// holder's manager.
+func ParseCampaignConfig(data []byte) (*CampaignConfig, error) {
+	var cfg CampaignConfig
+	trimmed := strings.TrimSpace(string(data))
+	if trimmed == "" {
+		return &cfg, nil
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal([]byte(trimmed), &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse campaign config JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal([]byte(trimmed), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse campaign config YAML: %w", err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate checks the reviewer sources and owner tables.
+func (c *CampaignConfig) validate() error {
+	for _, source := range c.Sources {
+		switch source {
+		case ReviewerEntitlementOwner, ReviewerSystemOwner, ReviewerManager:
+		default:
+			return fmt.Errorf("unknown reviewer source %q", source)
+		}
+	}
+	for system, owner := range c.SystemOwners {
+		if strings.TrimSpace(system) == "" || system == "*" {
+			return fmt.Errorf("systemOwners: system name is required; use fallbackReviewer for every system")
+		}
+		if strings.TrimSpace(owner) == "" {
+			return fmt.Errorf("systemOwners %s: owner is required", system)
+		}
+	}
+	for i, eo := range c.EntitlementOwners {
+		if strings.TrimSpace(eo.Grant) == "" {
+			return fmt.Errorf("entitlementOwners[%d]: grant is required", i)
+		}
+		if strings.TrimSpace(eo.Owner) == "" {
+			return fmt.Errorf("entitlementOwners[%d]: owner is required", i)
+		}
+	}
+	return nil
+}
+
+// Campaign splits a report's accounts into per-reviewer work packages. It is stored
+// on the report it was built from, so decisions travel with the report.
+type Campaign struct {
+	Name                string        `json:"name"`
+	ProcessingTimestamp int64         `json:"processingTimestamp"`
+	WorkPackages        []WorkPackage `json:"workPackages"`
+	// Unassigned holds accounts no source, reassignment, or fallback could assign.
+	Unassigned []ReviewItem     `json:"unassigned"`
+	Progress   CampaignProgress `json:"progress"`
+	// CarryForward summarizes the decisions carried from the previous cycle, if any.
+	CarryForward *CarryForwardSummary `json:"carryForward,omitempty"`
+}
+
+// WorkPackage is everything one reviewer has to review.
+type WorkPackage struct {
+	Reviewer      string           `json:"reviewer"` // canonical ID
+	ReviewerName  string           `json:"reviewerName"`
+	ReviewerEmail string           `json:"reviewerEmail"`
+	Items         []ReviewItem     `json:"items"`
+	Progress      CampaignProgress `json:"progress"`
+}
+
+// CampaignProgress counts review items by decision.
+type CampaignProgress struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Pending   int `json:"pending"`
+	Approved  int `json:"approved"`
+	Revoked   int `json:"revoked"`
+}
+
+// ReviewItem is one account to review.
+type ReviewItem struct {
+	// ID identifies the item within the campaign and across cycles; see ReviewItemID.
+	ID            string           `json:"id"`
+	Key           AccessKey        `json:"key"`
+	CanonicalID   string           `json:"canonicalId,omitempty"`
+	DisplayName   string           `json:"displayName"`
+	Email         string           `json:"email"`
+	System        string           `json:"system"`
+	Role          string           `json:"role"`
+	Entitlement   string           `json:"entitlement"`
+	AccountStatus string           `json:"accountStatus"`
+	MatchType     string           `json:"matchType"`
+	RiskLevel     engine.RiskLevel `json:"riskLevel"`
+	RiskScore     int              `json:"riskScore"`
+	SourceRow     int              `json:"sourceRow"`
+	Reviewer      string           `json:"reviewer,omitempty"` // canonical ID
+	Source        ReviewerSource   `json:"source,omitempty"`
+	// Reassignments lists the candidate reviewers passed over, in order.
+	Reassignments []Reassignment  `json:"reassignments,omitempty"`
+	Decision      *ReviewDecision `json:"decision,omitempty"`
+	// CarryForward is how CarryForward treated the previous cycle's decision.
+	CarryForward CarryStatus `json:"carryForward,omitempty"`
+	// ReReviewReasons lists what changed since the previous cycle's decision.
+	ReReviewReasons []string `json:"reReviewReasons,omitempty"`
+}
+
+// Reassignment records a candidate reviewer who was passed over.
+type Reassignment struct {
+	Source ReviewerSource `json:"source"`
+	// From is the candidate's canonical ID, or the unresolved owner value.
+	From   string         `json:"from"`
+	Reason ReassignReason `json:"reason"`
+}
+
+// ReviewDecision is a reviewer's verdict on a review item.
+type ReviewDecision struct {
+	Action    DecisionAction `json:"action"`
+	Reviewer  string         `json:"reviewer"`
+	DecidedAt int64          `json:"decidedAt"` // Unix milliseconds
+	Notes     string         `json:"notes,omitempty"`
+	// RoleActions are verdicts on individual roles of the account, e.g. keep the
+	// account but revoke one of its groups.
+	RoleActions []RoleAction `json:"roleActions,omitempty"`
+	// Origin is the cycle the decision was made in, when it was carried forward.
+	Origin *DecisionOrigin `json:"origin,omitempty"`
+}
+
+// RoleAction is a verdict on one role or entitlement value of an account.
+type RoleAction struct {
+	Role   string         `json:"role"`
+	Action DecisionAction `json:"action"`
+}
+
+// DecisionOrigin identifies the review cycle and item a carried decision was made on.
+type DecisionOrigin struct {
+	Campaign            string `json:"campaign"`
+	ProcessingTimestamp int64  `json:"processingTimestamp"`
+	ItemID              string `json:"itemId,omitempty"`
+}
+
+// validate checks the decision's actions.
+func (d ReviewDecision) validate() error {
+	if !validDecisionAction(d.Action) {
+		return fmt.Errorf("unknown decision action %q", d.Action)
+	}
+	for i, ra := range d.RoleActions {
+		if strings.TrimSpace(ra.Role) == "" {
+			return fmt.Errorf("roleActions[%d]: role is required", i)
+		}
+		if !validDecisionAction(ra.Action) {
+			return fmt.Errorf("roleActions[%d]: unknown decision action %q", i, ra.Action)
+		}
+	}
+	return nil
+}
+
+// validDecisionAction reports whether an action is approve or revoke.
+func validDecisionAction(action DecisionAction) bool {
+	return action == DecisionApprove || action == DecisionRevoke
+}
+
+// ReviewItemID identifies a report entry within a campaign by its AccessKey, so the
+// same account has the same ID in every cycle whatever row it is exported on.
+// occurrence is 1 for the first entry with the key; later entries repeating it, such
+// as one account's rows for several roles, get "#2", "#3" and so on, in report order
+// as Diff pairs them.
+func ReviewItemID(key AccessKey, occurrence int) string {
+	id := key.System + ":" + key.Identity + ":" + key.Entitlement
+	if occurrence > 1 {
+		id += "#" + strconv.Itoa(occurrence)
+	}
+	return id
+}
+
+// ReviewItemIDs returns the review item ID of each entry, in entry order. no_access
+// entries are not reviewed and get "".
+func ReviewItemIDs(entries []MasterReportEntry) []string {
+	ids := make([]string, len(entries))
+	seen := make(map[AccessKey]int)
+	for i, e := range entries {
+		if e.MatchType == "no_access" {
+			continue
+		}
+		key := AccessKeyOf(e)
+		seen[key]++
+		ids[i] = ReviewItemID(key, seen[key])
+	}
+	return ids
+}
+
+// entryItemIDs maps each reviewed entry of a report's AllEntries to its review item ID.
+func entryItemIDs(r *MasterReport) map[*MasterReportEntry]string {
+	ids := ReviewItemIDs(r.AllEntries)
+	byEntry := make(map[*MasterReportEntry]string, len(ids))
+	for i, id := range ids {
+		if id != "" {
+			byEntry[&r.AllEntries[i]] = id
+		}
+	}
+	return byEntry
+}
+
+// BuildCampaign assigns a reviewer to every account in the report and groups the
+// accounts into work packages, which it also stores in r.Campaign. no_access entries
+// have nothing to review and are left out.
+//
+// Sources are tried in the configured order, then the fallback reviewer. A candidate
+// who is terminated or holds the account under review is replaced by their own
+// manager, up the chain, before the next source is tried. Work packages are in
+// order of their first item in AllEntries.
+func BuildCampaign(r *MasterReport, sotIndex *engine.SoTIndex, cfg CampaignConfig) *Campaign {
+	sources := cfg.Sources
+	if len(sources) == 0 {
+		sources = defaultReviewerSources
+	}
+	ownerSystems := make([]string, 0, len(cfg.SystemOwners))
+	for system := range cfg.SystemOwners {
+		ownerSystems = append(ownerSystems, system)
+	}
+
+	a := reviewerAssigner{index: sotIndex, org: engine.BuildOrgHierarchy(sotIndex)}
+	campaign := &Campaign{
+		Name:                cfg.Name,
+		ProcessingTimestamp: r.Metadata.ProcessingTimestamp,
+		WorkPackages:        make([]WorkPackage, 0),
+		Unassigned:          make([]ReviewItem, 0),
+	}
+	packages := make(map[string]int)
+	ids := ReviewItemIDs(r.AllEntries)
+
+	for i, e := range r.AllEntries {
+		if e.MatchType == "no_access" {
+			continue
+		}
+
+		item := ReviewItem{
+			ID:            ids[i],
+			Key:           AccessKeyOf(e),
+			CanonicalID:   e.CanonicalID,
+			DisplayName:   e.DisplayName,
+			Email:         e.Email,
+			System:        e.System,
+			Role:          e.Role,
+			Entitlement:   e.Entitlement,
+			AccountStatus: e.AccountStatus,
+			MatchType:     e.MatchType,
+			RiskLevel:     e.RiskLevel,
+			RiskScore:     e.RiskScore,
+			SourceRow:     e.SourceRow,
+		}
+
+		var reviewer *schema.SoTRecord
+		for _, source := range sources {
+			var candidate string
+			switch source {
+			case ReviewerEntitlementOwner:
+				candidate = entitlementOwner(cfg.EntitlementOwners, e)
+			case ReviewerSystemOwner:
+				if key := engine.BestSystemKey(ownerSystems, e.System); key != "" {
+					candidate = cfg.SystemOwners[key]
+				}
+			case ReviewerManager:
+				if e.CanonicalID != "" {
+					candidate = a.org.ManagerOf(e.CanonicalID)
+				}
+			}
+			if reviewer = a.assign(&item, source, candidate); reviewer != nil {
+				break
+			}
+		}
+		if reviewer == nil {
+			reviewer = a.assign(&item, ReviewerFallback, cfg.FallbackReviewer)
+		}
+
+		if reviewer == nil {
+			campaign.Unassigned = append(campaign.Unassigned, item)
+			continue
+		}
+
+		i, ok := packages[reviewer.CanonicalID]
+		if !ok {
+			i = len(campaign.WorkPackages)
+			packages[reviewer.CanonicalID] = i
+			campaign.WorkPackages = append(campaign.WorkPackages, WorkPackage{
+				Reviewer:      reviewer.CanonicalID,
+				ReviewerName:  reviewer.DisplayName,
+				ReviewerEmail: reviewer.Email,
+			})
+		}
+		campaign.WorkPackages[i].Items = append(campaign.WorkPackages[i].Items, item)
+	}
+
+	campaign.updateProgress()
+	r.Campaign = campaign
+	return campaign
+}
+
+// entitlementOwner returns the owner of the first EntitlementOwner matching one of
+// the entry's grants, or "".
+func entitlementOwner(owners []EntitlementOwner, e MasterReportEntry) string {
+	grants := append(engine.SplitGrants(e.Role), engine.SplitGrants(e.Entitlement)...)
+	for _, eo := range owners {
+		if eo.System != "" && engine.BestSystemKey([]string{eo.System}, e.System) == "" {
+			continue
+		}
+		for _, g := range grants {
+			if strings.EqualFold(g, strings.TrimSpace(eo.Grant)) {
+				return eo.Owner
+			}
+		}
+	}
+	return ""
+}
+
+// reviewerAssigner resolves candidate reviewers and applies the reassignment rules.
+type reviewerAssigner struct {
+	index *engine.SoTIndex
+	org   *engine.OrgHierarchy
+}
+
+// assign resolves a candidate from one source to an eligible reviewer, walking up the
+// candidate's manager chain past terminated people and the account holder. Each
+// candidate passed over is recorded on the item. It returns nil when the source has
+// no candidate or the chain runs out; on success it sets the item's reviewer.
+func (a reviewerAssigner) assign(item *ReviewItem, source ReviewerSource, candidate string) *schema.SoTRecord {
+	if candidate == "" {
+		return nil
+	}
+
+	var person *schema.SoTRecord
+	if source == ReviewerManager {
+		person = a.org.Record(candidate)
+	} else {
+		person, _ = engine.ResolvePerson(a.index, candidate)
+		if person != nil {
+			person = a.org.Record(person.CanonicalID)
+		}
+	}
+	if person == nil {
+		item.Reassignments = append(item.Reassignments, Reassignment{Source: source, From: candidate, Reason: ReassignNotFound})
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for person != nil && !seen[person.CanonicalID] {
+		seen[person.CanonicalID] = true
+
+		var reason ReassignReason
+		switch {
+		case person.LifecycleState == schema.LifecycleTerminated:
+			reason = ReassignTerminated
+		case isSubject(person, item):
+			reason = ReassignSelfReview
+		default:
+			item.Reviewer = person.CanonicalID
+			item.Source = source
+			return person
+		}
+
+		item.Reassignments = append(item.Reassignments, Reassignment{Source: source, From: person.CanonicalID, Reason: reason})
+		person = a.org.Record(a.org.ManagerOf(person.CanonicalID))
+	}
+	return nil
+}
+
+// isSubject reports whether a person holds the account under review, either as its
+// matched SoT identity or by the account's email.
+func isSubject(person *schema.SoTRecord, item *ReviewItem) bool {
+	if item.CanonicalID != "" && person.CanonicalID == item.CanonicalID {
+		return true
+	}
+	return item.Email != "" && strings.EqualFold(person.Email, item.Email)
+}
+
+// Decide records a decision on a review item and updates the progress counters.
+func (c *Campaign) Decide(itemID string, decision ReviewDecision) error {
+	if err := decision.validate(); err != nil {
+		return err
+	}
+
+	item := c.Item(itemID)
+	if item == nil {
+		return fmt.Errorf("review item %q not found", itemID)
+	}
+	item.Decision = &decision
+	c.updateProgress()
+	return nil
+}
+
+// ItemIndex indexes the campaign's review items, assigned and unassigned, by ID. Use
+// it instead of Item to look up many items.
+func (c *Campaign) ItemIndex() map[string]*ReviewItem {
+	items := make(map[string]*ReviewItem)
+	for i := range c.WorkPackages {
+		for j := range c.WorkPackages[i].Items {
+			items[c.WorkPackages[i].Items[j].ID] = &c.WorkPackages[i].Items[j]
+		}
+	}
+	for i := range c.Unassigned {
+		items[c.Unassigned[i].ID] = &c.Unassigned[i]
+	}
+	return items
+}
+
+// Item returns the review item with the given ID, or nil.
+func (c *Campaign) Item(itemID string) *ReviewItem {
+	for i := range c.WorkPackages {
+		for j := range c.WorkPackages[i].Items {
+			if c.WorkPackages[i].Items[j].ID == itemID {
+				return &c.WorkPackages[i].Items[j]
+			}
+		}
+	}
+	for i := range c.Unassigned {
+		if c.Unassigned[i].ID == itemID {
+			return &c.Unassigned[i]
+		}
+	}
+	return nil
+}
+
+// updateProgress recounts the progress of every work package and the campaign.
+func (c *Campaign) updateProgress() {
+	c.Progress = progressOf(c.Unassigned)
+	for i := range c.WorkPackages {
+		p := progressOf(c.WorkPackages[i].Items)
+		c.WorkPackages[i].Progress = p
+		c.Progress.Total += p.Total
+		c.Progress.Completed += p.Completed
+		c.Progress.Pending += p.Pending
+		c.Progress.Approved += p.Approved
+		c.Progress.Revoked += p.Revoked
+	}
+}
+
+// progressOf counts a list of review items by decision.
+func progressOf(items []ReviewItem) CampaignProgress {
+	p := CampaignProgress{Total: len(items)}
+	for _, item := range items {
+		if item.Decision == nil {
+			continue
+		}
+		p.Completed++
+		switch item.Decision.Action {
+		case DecisionApprove:
+			p.Approved++
+		case DecisionRevoke:
+			p.Revoked++
+		}
+	}
+	p.Pending = p.Total - p.Completed
+	return p
+}
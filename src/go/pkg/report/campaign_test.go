@@ -0,0 +1,174 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"uar/pkg/engine"
+	"uar/pkg/schema"
+)
+
+// campaignSoT is a small org: Ceo manages Mia and Gus, who is terminated; Mia
+// manages Jane, and Gus still manages Sam.
+func campaignSoT() *engine.SoTIndex {
+	person := func(user, name, manager string, state schema.LifecycleState) *schema.SoTRecord {
+		return &schema.SoTRecord{
+			CanonicalID:    user + "@acme.com",
+			EmployeeID:     "E-" + user,
+			DisplayName:    name,
+			NormalizedName: schema.NormalizeName(name),
+			Email:          user + "@acme.com",
+			Manager:        manager,
+			LifecycleState: state,
+		}
+	}
+	return engine.BuildSoTIndex([]*schema.SoTRecord{
+		person("ceo", "Ceo Smith", "", schema.LifecycleActive),
+		person("mia", "Mia Lopez", "ceo@acme.com", schema.LifecycleActive),
+		person("gus", "Gus Hale", "ceo@acme.com", schema.LifecycleTerminated),
+		person("jane", "Jane Doe", "mia@acme.com", schema.LifecycleActive),
+		person("sam", "Sam Ortiz", "gus@acme.com", schema.LifecycleActive),
+		person("lead", "Iam Lead", "ceo@acme.com", schema.LifecycleActive),
+	})
+}
+
+func TestBuildCampaignReviewers(t *testing.T) {
+	jane := testEntry("jane@acme.com", "okta", 1, "user")
+	jane.Email = "jane@acme.com"
+	sam := testEntry("sam@acme.com", "okta", 2, "user")
+	admin := testEntry("jane@acme.com", "okta", 3, "Admin")
+	orphan := MasterReportEntry{Email: "temp7@acme.com", System: "okta", Role: "user", AccountStatus: "active", MatchType: "orphan", SourceRow: 4}
+	janeOrphan := MasterReportEntry{Email: "jane@acme.com", System: "github", Role: "write", AccountStatus: "active", MatchType: "orphan", SourceRow: 1}
+
+	tests := []struct {
+		name         string
+		entry        MasterReportEntry
+		cfg          CampaignConfig
+		wantReviewer string // empty when the item is unassigned
+		wantSource   ReviewerSource
+		wantReasons  []ReassignReason
+	}{
+		{
+			name:         "manager reviews by default",
+			entry:        jane,
+			wantReviewer: "mia@acme.com",
+			wantSource:   ReviewerManager,
+		},
+		{
+			name:         "terminated manager is replaced by their manager",
+			entry:        sam,
+			wantReviewer: "ceo@acme.com",
+			wantSource:   ReviewerManager,
+			wantReasons:  []ReassignReason{ReassignTerminated},
+		},
+		{
+			name:         "entitlement owner comes first",
+			entry:        admin,
+			cfg:          CampaignConfig{EntitlementOwners: []EntitlementOwner{{System: "okta", Grant: "admin", Owner: "Ceo Smith"}}, SystemOwners: map[string]string{"okta": "lead@acme.com"}},
+			wantReviewer: "ceo@acme.com",
+			wantSource:   ReviewerEntitlementOwner,
+		},
+		{
+			name:         "system owner may not review their own account",
+			entry:        jane,
+			cfg:          CampaignConfig{Sources: []ReviewerSource{ReviewerSystemOwner}, SystemOwners: map[string]string{"okta": "jane@acme.com"}},
+			wantReviewer: "mia@acme.com",
+			wantSource:   ReviewerSystemOwner,
+			wantReasons:  []ReassignReason{ReassignSelfReview},
+		},
+		{
+			name:         "unknown owner falls through to the next source",
+			entry:        jane,
+			cfg:          CampaignConfig{SystemOwners: map[string]string{"okta": "nobody@acme.com"}},
+			wantReviewer: "mia@acme.com",
+			wantSource:   ReviewerManager,
+			wantReasons:  []ReassignReason{ReassignNotFound},
+		},
+		{
+			name:         "fallback reviewer takes unmatched accounts",
+			entry:        orphan,
+			cfg:          CampaignConfig{FallbackReviewer: "Iam Lead"},
+			wantReviewer: "lead@acme.com",
+			wantSource:   ReviewerFallback,
+		},
+		{
+			name:         "fallback reviewer may not review their own account",
+			entry:        janeOrphan,
+			cfg:          CampaignConfig{FallbackReviewer: "jane@acme.com"},
+			wantReviewer: "mia@acme.com",
+			wantSource:   ReviewerFallback,
+			wantReasons:  []ReassignReason{ReassignSelfReview},
+		},
+		{
+			name:  "unmatched account without a fallback is unassigned",
+			entry: orphan,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &MasterReport{AllEntries: []MasterReportEntry{tt.entry}}
+			c := BuildCampaign(r, campaignSoT(), tt.cfg)
+
+			if r.Campaign != c {
+				t.Fatal("BuildCampaign() did not store the campaign on the report")
+			}
+			item := c.Item(ReviewItemIDs(r.AllEntries)[0])
+			if item == nil {
+				t.Fatalf("campaign has no item for the entry: %+v", c)
+			}
+			if tt.wantReviewer == "" {
+				if len(c.Unassigned) != 1 || len(c.WorkPackages) != 0 {
+					t.Errorf("unassigned = %d, work packages = %d, want the item unassigned", len(c.Unassigned), len(c.WorkPackages))
+				}
+				return
+			}
+			if item.Reviewer != tt.wantReviewer || item.Source != tt.wantSource {
+				t.Errorf("reviewer = %s from %s, want %s from %s", item.Reviewer, item.Source, tt.wantReviewer, tt.wantSource)
+			}
+			var reasons []ReassignReason
+			for _, ra := range item.Reassignments {
+				reasons = append(reasons, ra.Reason)
+			}
+			if !slices.Equal(reasons, tt.wantReasons) {
+				t.Errorf("reassignments = %+v, want reasons %v", item.Reassignments, tt.wantReasons)
+			}
+			if len(c.WorkPackages) != 1 || c.WorkPackages[0].Reviewer != tt.wantReviewer {
+				t.Errorf("work packages = %+v, want one for %s", c.WorkPackages, tt.wantReviewer)
+			}
+		})
+	}
+}
+
+func TestBuildCampaignItemIDs(t *testing.T) {
+	r := &MasterReport{AllEntries: []MasterReportEntry{
+		testEntry("jane@acme.com", "okta", 7, "user"),
+		{CanonicalID: "mia@acme.com", MatchType: "no_access"},
+		testEntry("jane@acme.com", "okta", 9, "admin"),
+		testEntry("jane@acme.com", "github", 7, "write"),
+	}}
+	c := BuildCampaign(r, campaignSoT(), CampaignConfig{Name: "Q2"})
+
+	want := []string{"okta:jane@acme.com:", "", "okta:jane@acme.com:#2", "github:jane@acme.com:"}
+	if got := ReviewItemIDs(r.AllEntries); !slices.Equal(got, want) {
+		t.Fatalf("ReviewItemIDs() = %q, want %q", got, want)
+	}
+	if c.Progress.Total != 3 {
+		t.Errorf("progress total = %d, want 3 items without the no_access entry", c.Progress.Total)
+	}
+	if item := c.Item("okta:jane@acme.com:#2"); item == nil || item.Role != "admin" || item.Key != AccessKeyOf(r.AllEntries[2]) {
+		t.Errorf("Item(#2) = %+v, want the admin row keyed by its AccessKey", item)
+	}
+
+	if err := c.Decide("okta:jane@acme.com:", ReviewDecision{Action: DecisionRevoke}); err != nil {
+		t.Fatalf("Decide() error = %v", err)
+	}
+	if c.Progress.Revoked != 1 || c.Progress.Pending != 2 {
+		t.Errorf("progress = %+v, want one revoked and two pending", c.Progress)
+	}
+	if err := c.Decide("okta:7", ReviewDecision{Action: DecisionApprove}); err == nil {
+		t.Error("Decide() with a row-based ID succeeded, want not found")
+	}
+	if err := c.Decide("github:jane@acme.com:", ReviewDecision{Action: "maybe"}); err == nil {
+		t.Error("Decide() with an unknown action succeeded, want an error")
+	}
+}
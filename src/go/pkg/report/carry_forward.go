@@ -0,0 +1,99 @@
+package report
+
+import "fmt"
+
+// CarryStatus is how a previous cycle's decision was applied to a review item.
+type CarryStatus string
+
+const (
+	// CarryCarried is a decision copied from the previous cycle because nothing
+	// material changed.
+	CarryCarried CarryStatus = "carried"
+	// CarryReReview is an account decided in the previous cycle that changed since
+	// and must be reviewed again.
+	CarryReReview CarryStatus = "re_review"
+)
+
+// Re-review reasons recorded on review items, besides the Diff changes "role" and
+// "accountStatus".
+const (
+	ReReviewPrivilege         = "privilege"
+	ReReviewRiskLevel         = "riskLevel"
+	ReReviewRevokeNotActioned = "revoke_not_actioned"
+)
+
+// CarryForwardSummary counts the decisions CarryForward applied.
+type CarryForwardSummary struct {
+	// From is the previous cycle: its campaign name and processing timestamp.
+	From     DecisionOrigin `json:"from"`
+	Carried  int            `json:"carried"`
+	ReReview int            `json:"reReview"`
+}
+
+// CarryForward pre-populates the current report's campaign with the previous cycle's
+// decisions. Accounts are paired as in Diff. A decision is carried when the paired
+// account kept its role and account status, gained no privilege, and did not rise in
+// risk level; it keeps its original Origin, or records the previous cycle as its
+// origin. Otherwise the item is left undecided and marked for re-review with the
+// reasons, including a revoke that was never carried out. Items already decided in
+// the current campaign are left as they are.
+//
+// The current report must have a campaign from BuildCampaign.
+func CarryForward(previous, current *MasterReport) (*CarryForwardSummary, error) {
+	if current.Campaign == nil {
+		return nil, fmt.Errorf("current report has no campaign; build one before carrying decisions forward")
+	}
+
+	summary := &CarryForwardSummary{}
+	if previous.Campaign != nil {
+		summary.From = DecisionOrigin{
+			Campaign:            previous.Campaign.Name,
+			ProcessingTimestamp: previous.Campaign.ProcessingTimestamp,
+		}
+	}
+
+	items := current.Campaign.ItemIndex()
+	currentIDs, previousIDs := entryItemIDs(current), entryItemIDs(previous)
+	for _, d := range Diff(previous, current).Entries {
+		if d.Decision == nil || d.Previous == nil || d.Current == nil {
+			continue
+		}
+		item := items[currentIDs[d.Current]]
+		if item == nil || item.Decision != nil {
+			continue
+		}
+
+		reasons := append([]string(nil), d.Changes...)
+		if d.NewPrivilege {
+			reasons = append(reasons, ReReviewPrivilege)
+		}
+		if d.Current.RiskLevel.Outranks(d.Previous.RiskLevel) {
+			reasons = append(reasons, ReReviewRiskLevel)
+		}
+		if d.Kind == DiffRevokeNotActioned {
+			reasons = append(reasons, ReReviewRevokeNotActioned)
+		}
+
+		if len(reasons) > 0 {
+			item.CarryForward = CarryReReview
+			item.ReReviewReasons = reasons
+			summary.ReReview++
+			continue
+		}
+
+		decision := *d.Decision
+		decision.RoleActions = append([]RoleAction(nil), d.Decision.RoleActions...)
+		if decision.Origin == nil {
+			origin := summary.From
+			origin.ItemID = previousIDs[d.Previous]
+			decision.Origin = &origin
+		}
+		item.Decision = &decision
+		item.CarryForward = CarryCarried
+		summary.Carried++
+	}
+
+	current.Campaign.CarryForward = summary
+	current.Campaign.updateProgress()
+	return summary, nil
+}
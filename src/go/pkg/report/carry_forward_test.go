@@ -0,0 +1,135 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"uar/pkg/engine"
+)
+
+func TestCarryForward(t *testing.T) {
+	approve := map[string]ReviewDecision{janeOkta: {Action: DecisionApprove, Reviewer: "reviewer@acme.com", DecidedAt: 10}}
+	earlier := &DecisionOrigin{Campaign: "Q4", ProcessingTimestamp: 0, ItemID: "okta:jdoe:"}
+	carriedBefore := map[string]ReviewDecision{janeOkta: {Action: DecisionApprove, Origin: earlier}}
+	risky := testEntry("jane", "okta", 1, "user")
+	risky.RiskLevel = engine.RiskHigh
+
+	tests := []struct {
+		name        string
+		previous    []MasterReportEntry
+		current     []MasterReportEntry
+		decisions   map[string]ReviewDecision
+		decided     bool // the current item already has a decision
+		wantStatus  CarryStatus
+		wantReasons []string
+		wantOrigin  DecisionOrigin
+	}{
+		{
+			name:       "unchanged approval is carried",
+			previous:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:  approve,
+			wantStatus: CarryCarried,
+			wantOrigin: DecisionOrigin{Campaign: "Q1", ProcessingTimestamp: 1, ItemID: janeOkta},
+		},
+		{
+			name:       "decision follows the account to another row",
+			previous:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:    []MasterReportEntry{testEntry("jane", "okta", 4, "user")},
+			decisions:  approve,
+			wantStatus: CarryCarried,
+			wantOrigin: DecisionOrigin{Campaign: "Q1", ProcessingTimestamp: 1, ItemID: janeOkta},
+		},
+		{
+			name:       "carried decision keeps its origin",
+			previous:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:  carriedBefore,
+			wantStatus: CarryCarried,
+			wantOrigin: *earlier,
+		},
+		{
+			name:        "role change needs re-review",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{testEntry("jane", "okta", 1, "power user")},
+			decisions:   approve,
+			wantStatus:  CarryReReview,
+			wantReasons: []string{"role"},
+		},
+		{
+			name:        "higher risk needs re-review",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{risky},
+			decisions:   approve,
+			wantStatus:  CarryReReview,
+			wantReasons: []string{ReReviewRiskLevel},
+		},
+		{
+			name:        "revoke not carried out needs re-review",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:   map[string]ReviewDecision{janeOkta: {Action: DecisionRevoke}},
+			wantStatus:  CarryReReview,
+			wantReasons: []string{ReReviewRevokeNotActioned},
+		},
+		{
+			name:      "decision already recorded in the current campaign is kept",
+			previous:  []MasterReportEntry{testEntry("jane", "okta", 1, "power user")},
+			current:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions: approve,
+			decided:   true,
+		},
+		{
+			name:     "undecided account is left alone",
+			previous: []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:  []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := testReport(1, tt.previous, tt.decisions)
+			var current *MasterReport
+			if tt.decided {
+				current = testReport(2, tt.current, map[string]ReviewDecision{janeOkta: {Action: DecisionRevoke, Reviewer: "now@acme.com"}})
+			} else {
+				current = testReport(2, tt.current, nil)
+			}
+
+			summary, err := CarryForward(previous, current)
+			if err != nil {
+				t.Fatalf("CarryForward() error = %v", err)
+			}
+
+			item := current.Campaign.Item(janeOkta)
+			if item.CarryForward != tt.wantStatus || !slices.Equal(item.ReReviewReasons, tt.wantReasons) {
+				t.Fatalf("item = %s %v, want %s %v", item.CarryForward, item.ReReviewReasons, tt.wantStatus, tt.wantReasons)
+			}
+
+			switch {
+			case tt.decided:
+				if item.Decision.Reviewer != "now@acme.com" || summary.Carried+summary.ReReview != 0 {
+					t.Errorf("current decision was replaced: %+v, summary %+v", item.Decision, summary)
+				}
+			case tt.wantStatus == CarryCarried:
+				if item.Decision == nil || item.Decision.Origin == nil || *item.Decision.Origin != tt.wantOrigin {
+					t.Fatalf("decision = %+v, want origin %+v", item.Decision, tt.wantOrigin)
+				}
+				if summary.Carried != 1 || current.Campaign.Progress.Completed != 1 {
+					t.Errorf("summary = %+v, progress = %+v, want one carried and completed", summary, current.Campaign.Progress)
+				}
+			default:
+				if item.Decision != nil {
+					t.Errorf("decision = %+v, want none", item.Decision)
+				}
+			}
+		})
+	}
+}
+
+func TestCarryForwardNeedsCampaign(t *testing.T) {
+	current := testReport(2, nil, nil)
+	current.Campaign = nil
+	if _, err := CarryForward(testReport(1, nil, nil), current); err == nil {
+		t.Error("CarryForward() error = nil, want an error without a campaign")
+	}
+}
@@ -0,0 +1,263 @@
+package report
+
+import (
+	"strings"
+
+	"uar/pkg/engine"
+	"uar/pkg/schema"
+)
+
+// DiffKind classifies an account between two review cycles.
+type DiffKind string
+
+const (
+	DiffAdded     DiffKind = "added"
+	DiffRemoved   DiffKind = "removed"
+	DiffUnchanged DiffKind = "unchanged"
+	// DiffChanged is an account whose role or account status changed.
+	DiffChanged DiffKind = "changed"
+	// DiffRevokeNotActioned is an account still active after a "revoke" decision in
+	// the previous cycle.
+	DiffRevokeNotActioned DiffKind = "revoke_not_actioned"
+)
+
+// AccessKey identifies an account across review cycles.
+type AccessKey struct {
+	// Identity is the canonical ID, or for unmatched accounts the lowercase email,
+	// then the display name.
+	Identity    string `json:"identity"`
+	System      string `json:"system"`
+	Entitlement string `json:"entitlement"`
+}
+
+// ReportDiff compares two master reports.
+type ReportDiff struct {
+	PreviousTimestamp int64        `json:"previousTimestamp"`
+	CurrentTimestamp  int64        `json:"currentTimestamp"`
+	Entries           []EntryDiff  `json:"entries"`
+	NewlyTerminated   []UserChange `json:"newlyTerminated"`
+	Summary           DiffSummary  `json:"summary"`
+}
+
+// EntryDiff is one account's classification between two cycles.
+type EntryDiff struct {
+	Kind        DiffKind  `json:"kind"`
+	Key         AccessKey `json:"key"`
+	DisplayName string    `json:"displayName"`
+	// Changes names the fields that differ for a changed account: role, accountStatus.
+	Changes []string `json:"changes,omitempty"`
+	// NewPrivilege is set when the account is privileged now and was not, or was
+	// less privileged, in the previous cycle.
+	NewPrivilege bool               `json:"newPrivilege,omitempty"`
+	Previous     *MasterReportEntry `json:"previous,omitempty"`
+	Current      *MasterReportEntry `json:"current,omitempty"`
+	// Decision is the previous cycle's review decision on the account, if any.
+	Decision *ReviewDecision `json:"decision,omitempty"`
+}
+
+// UserChange is a person whose lifecycle state changed between two cycles.
+type UserChange struct {
+	CanonicalID    string `json:"canonicalId"`
+	DisplayName    string `json:"displayName"`
+	PreviousStatus string `json:"previousStatus"`
+	CurrentStatus  string `json:"currentStatus"`
+	// ActiveAccounts is the number of accounts still active in the current cycle.
+	ActiveAccounts int `json:"activeAccounts"`
+}
+
+// DiffSummary counts the classifications of a ReportDiff.
+type DiffSummary struct {
+	Added             int `json:"added"`
+	Removed           int `json:"removed"`
+	Unchanged         int `json:"unchanged"`
+	Changed           int `json:"changed"`
+	RevokeNotActioned int `json:"revokeNotActioned"`
+	NewPrivileged     int `json:"newPrivileged"`
+	NewlyTerminated   int `json:"newlyTerminated"`
+}
+
+// Diff compares a previous cycle's master report with the current one. Accounts are
+// paired by AccessKey, in report order when a key repeats; no_access entries are
+// left out. A paired account the previous campaign decided to revoke that is still
+// active is revoke_not_actioned, whatever else changed.
+//
+// Entries hold the current report's accounts in AllEntries order, followed by the
+// removed accounts in the previous report's order.
+func Diff(previous, current *MasterReport) *ReportDiff {
+	diff := &ReportDiff{
+		PreviousTimestamp: previous.Metadata.ProcessingTimestamp,
+		CurrentTimestamp:  current.Metadata.ProcessingTimestamp,
+		Entries:           make([]EntryDiff, 0),
+		NewlyTerminated:   make([]UserChange, 0),
+	}
+
+	decisions := campaignDecisions(previous.Campaign)
+	previousIDs := ReviewItemIDs(previous.AllEntries)
+
+	// Unpaired previous entries per key, in report order
+	unpaired := make(map[AccessKey][]int)
+	for i, e := range previous.AllEntries {
+		if e.MatchType == "no_access" {
+			continue
+		}
+		key := AccessKeyOf(e)
+		unpaired[key] = append(unpaired[key], i)
+	}
+	paired := make(map[int]bool)
+
+	for i := range current.AllEntries {
+		cur := &current.AllEntries[i]
+		if cur.MatchType == "no_access" {
+			continue
+		}
+		key := AccessKeyOf(*cur)
+		d := EntryDiff{Key: key, DisplayName: cur.DisplayName, Current: cur}
+
+		if queue := unpaired[key]; len(queue) > 0 {
+			prev := &previous.AllEntries[queue[0]]
+			unpaired[key] = queue[1:]
+			paired[queue[0]] = true
+
+			d.Previous = prev
+			d.Decision = decisions[previousIDs[queue[0]]]
+			if !strings.EqualFold(strings.TrimSpace(prev.Role), strings.TrimSpace(cur.Role)) {
+				d.Changes = append(d.Changes, "role")
+			}
+			if engine.IsCurrentStatus(prev.AccountStatus) != engine.IsCurrentStatus(cur.AccountStatus) {
+				d.Changes = append(d.Changes, "accountStatus")
+			}
+
+			switch {
+			case d.Decision != nil && d.Decision.Action == DecisionRevoke && engine.IsCurrentStatus(cur.AccountStatus):
+				d.Kind = DiffRevokeNotActioned
+			case len(d.Changes) > 0:
+				d.Kind = DiffChanged
+			default:
+				d.Kind = DiffUnchanged
+			}
+		} else {
+			d.Kind = DiffAdded
+		}
+
+		d.NewPrivilege = gainedPrivilege(d.Previous, cur)
+		diff.add(d)
+	}
+
+	for i := range previous.AllEntries {
+		prev := &previous.AllEntries[i]
+		if prev.MatchType == "no_access" || paired[i] {
+			continue
+		}
+		diff.add(EntryDiff{
+			Kind:        DiffRemoved,
+			Key:         AccessKeyOf(*prev),
+			DisplayName: prev.DisplayName,
+			Previous:    prev,
+			Decision:    decisions[previousIDs[i]],
+		})
+	}
+
+	diff.NewlyTerminated = newlyTerminated(previous, current)
+	diff.Summary.NewlyTerminated = len(diff.NewlyTerminated)
+	return diff
+}
+
+// add appends an entry diff and counts it.
+func (d *ReportDiff) add(e EntryDiff) {
+	d.Entries = append(d.Entries, e)
+	switch e.Kind {
+	case DiffAdded:
+		d.Summary.Added++
+	case DiffRemoved:
+		d.Summary.Removed++
+	case DiffUnchanged:
+		d.Summary.Unchanged++
+	case DiffChanged:
+		d.Summary.Changed++
+	case DiffRevokeNotActioned:
+		d.Summary.RevokeNotActioned++
+	}
+	if e.NewPrivilege {
+		d.Summary.NewPrivileged++
+	}
+}
+
+// AccessKeyOf returns the cross-cycle key of a report entry.
+func AccessKeyOf(e MasterReportEntry) AccessKey {
+	identity := e.CanonicalID
+	if identity == "" {
+		identity = strings.ToLower(strings.TrimSpace(e.Email))
+	}
+	if identity == "" {
+		identity = schema.NormalizeName(e.DisplayName)
+	}
+	return AccessKey{
+		Identity:    identity,
+		System:      e.System,
+		Entitlement: strings.ToLower(strings.TrimSpace(e.Entitlement)),
+	}
+}
+
+// campaignDecisions indexes a campaign's decisions by review item ID.
+func campaignDecisions(c *Campaign) map[string]*ReviewDecision {
+	decisions := make(map[string]*ReviewDecision)
+	if c == nil {
+		return decisions
+	}
+	for id, item := range c.ItemIndex() {
+		if item.Decision != nil {
+			decisions[id] = item.Decision
+		}
+	}
+	return decisions
+}
+
+// gainedPrivilege reports whether an active account is privileged now and was not,
+// or was less privileged, before. prev is nil for a new account.
+func gainedPrivilege(prev, cur *MasterReportEntry) bool {
+	if cur.Privilege == nil || !engine.IsCurrentStatus(cur.AccountStatus) {
+		return false
+	}
+	if prev == nil || prev.Privilege == nil {
+		return true
+	}
+	return cur.Privilege.Tier.Outranks(prev.Privilege.Tier)
+}
+
+// newlyTerminated lists people terminated in the current report who were in the
+// previous one and not terminated there, in current user order.
+func newlyTerminated(previous, current *MasterReport) []UserChange {
+	previousStatus := make(map[string]string, len(previous.Users))
+	for _, u := range previous.Users {
+		if len(u.Entries) > 0 {
+			previousStatus[u.CanonicalID] = u.Entries[0].EmploymentStatus
+		}
+	}
+
+	changes := make([]UserChange, 0)
+	for _, u := range current.Users {
+		if len(u.Entries) == 0 {
+			continue
+		}
+		status := u.Entries[0].EmploymentStatus
+		before, ok := previousStatus[u.CanonicalID]
+		if !ok || schema.LifecycleStateOf(status) != schema.LifecycleTerminated ||
+			schema.LifecycleStateOf(before) == schema.LifecycleTerminated {
+			continue
+		}
+
+		change := UserChange{
+			CanonicalID:    u.CanonicalID,
+			DisplayName:    u.DisplayName,
+			PreviousStatus: before,
+			CurrentStatus:  status,
+		}
+		for _, e := range u.Entries {
+			if e.MatchType != "no_access" && engine.IsCurrentStatus(e.AccountStatus) {
+				change.ActiveAccounts++
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
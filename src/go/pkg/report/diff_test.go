@@ -0,0 +1,169 @@
+package report
+
+import (
+	"slices"
+	"testing"
+
+	"uar/pkg/engine"
+)
+
+// janeOkta is the review item ID of testEntry("jane", "okta", ...).
+const janeOkta = "okta:jane:"
+
+// testEntry returns an active, matched report entry.
+func testEntry(id, system string, row int, role string) MasterReportEntry {
+	return MasterReportEntry{
+		CanonicalID:      id,
+		DisplayName:      id,
+		EmploymentStatus: "active",
+		System:           system,
+		Role:             role,
+		AccountStatus:    "active",
+		MatchType:        "exact_email",
+		RiskLevel:        engine.RiskInfo,
+		SourceRow:        row,
+	}
+}
+
+// testReport builds a report with one user per canonical ID and a campaign holding a
+// review item for every entry, with the given decisions by item ID.
+func testReport(timestamp int64, entries []MasterReportEntry, decisions map[string]ReviewDecision) *MasterReport {
+	r := &MasterReport{
+		Metadata:   ReportMetadata{ProcessingTimestamp: timestamp},
+		AllEntries: entries,
+	}
+
+	users := make(map[string]int)
+	pkg := WorkPackage{Reviewer: "reviewer@acme.com"}
+	ids := ReviewItemIDs(entries)
+	for i, e := range entries {
+		if i, ok := users[e.CanonicalID]; ok {
+			r.Users[i].Entries = append(r.Users[i].Entries, e)
+		} else {
+			users[e.CanonicalID] = len(r.Users)
+			r.Users = append(r.Users, UserSummary{CanonicalID: e.CanonicalID, DisplayName: e.DisplayName, Entries: []MasterReportEntry{e}})
+		}
+
+		if e.MatchType == "no_access" {
+			continue
+		}
+		item := ReviewItem{ID: ids[i], Key: AccessKeyOf(e), CanonicalID: e.CanonicalID, System: e.System, Role: e.Role, SourceRow: e.SourceRow}
+		if d, ok := decisions[item.ID]; ok {
+			item.Decision = &d
+		}
+		pkg.Items = append(pkg.Items, item)
+	}
+	r.Campaign = &Campaign{Name: "Q1", ProcessingTimestamp: timestamp, WorkPackages: []WorkPackage{pkg}}
+	return r
+}
+
+func TestDiff(t *testing.T) {
+	revoke := map[string]ReviewDecision{janeOkta: {Action: DecisionRevoke, Reviewer: "reviewer@acme.com"}}
+	disabled := testEntry("jane", "okta", 1, "user")
+	disabled.AccountStatus = "disabled"
+	admin := testEntry("jane", "okta", 1, "admin")
+	admin.Privilege = &engine.PrivilegeAssessment{Tier: engine.TierAdmin}
+	noAccess := MasterReportEntry{CanonicalID: "sam", MatchType: "no_access"}
+	unmatched := MasterReportEntry{Email: "X@Acme.com", System: "okta", Role: "user", AccountStatus: "active", MatchType: "orphan", SourceRow: 7}
+	unmatchedMoved := unmatched
+	unmatchedMoved.Email, unmatchedMoved.SourceRow = "x@acme.com", 9
+
+	tests := []struct {
+		name        string
+		previous    []MasterReportEntry
+		current     []MasterReportEntry
+		decisions   map[string]ReviewDecision
+		wantKinds   []DiffKind
+		wantChanges []string
+		wantNewPriv int
+	}{
+		{
+			name:      "unchanged",
+			previous:  []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:   []MasterReportEntry{testEntry("jane", "okta", 4, "User ")},
+			wantKinds: []DiffKind{DiffUnchanged},
+		},
+		{
+			name:        "role changed and gained privilege",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{admin},
+			wantKinds:   []DiffKind{DiffChanged},
+			wantChanges: []string{"role"},
+			wantNewPriv: 1,
+		},
+		{
+			name:      "added and removed",
+			previous:  []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:   []MasterReportEntry{testEntry("jane", "github", 1, "user")},
+			wantKinds: []DiffKind{DiffAdded, DiffRemoved},
+		},
+		{
+			name:      "revoked account still active",
+			previous:  []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions: revoke,
+			wantKinds: []DiffKind{DiffRevokeNotActioned},
+		},
+		{
+			name:        "revoked account disabled",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{disabled},
+			decisions:   revoke,
+			wantKinds:   []DiffKind{DiffChanged},
+			wantChanges: []string{"accountStatus"},
+		},
+		{
+			name:      "no_access entries are left out",
+			previous:  []MasterReportEntry{noAccess},
+			current:   []MasterReportEntry{noAccess},
+			wantKinds: nil,
+		},
+		{
+			name:      "unmatched accounts pair by email",
+			previous:  []MasterReportEntry{unmatched},
+			current:   []MasterReportEntry{unmatchedMoved},
+			wantKinds: []DiffKind{DiffUnchanged},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := Diff(testReport(1, tt.previous, tt.decisions), testReport(2, tt.current, nil))
+
+			var kinds []DiffKind
+			for _, e := range diff.Entries {
+				kinds = append(kinds, e.Kind)
+			}
+			if !slices.Equal(kinds, tt.wantKinds) {
+				t.Fatalf("kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+			if len(diff.Entries) > 0 && !slices.Equal(diff.Entries[0].Changes, tt.wantChanges) {
+				t.Errorf("changes = %v, want %v", diff.Entries[0].Changes, tt.wantChanges)
+			}
+			if diff.Summary.NewPrivileged != tt.wantNewPriv {
+				t.Errorf("NewPrivileged = %d, want %d", diff.Summary.NewPrivileged, tt.wantNewPriv)
+			}
+			if tt.decisions != nil && diff.Entries[0].Decision == nil {
+				t.Error("previous decision not attached to the entry")
+			}
+		})
+	}
+}
+
+func TestDiffNewlyTerminated(t *testing.T) {
+	terminated := testEntry("jane", "okta", 1, "user")
+	terminated.EmploymentStatus = "Terminated"
+	alreadyGone := testEntry("sam", "okta", 2, "user")
+	alreadyGone.EmploymentStatus = "terminated"
+
+	previous := testReport(1, []MasterReportEntry{testEntry("jane", "okta", 1, "user"), alreadyGone}, nil)
+	current := testReport(2, []MasterReportEntry{terminated, alreadyGone}, nil)
+
+	diff := Diff(previous, current)
+	if len(diff.NewlyTerminated) != 1 || diff.Summary.NewlyTerminated != 1 {
+		t.Fatalf("NewlyTerminated = %+v, want jane only", diff.NewlyTerminated)
+	}
+	change := diff.NewlyTerminated[0]
+	if change.CanonicalID != "jane" || change.PreviousStatus != "active" || change.ActiveAccounts != 1 {
+		t.Errorf("change = %+v, want jane from active with 1 active account", change)
+	}
+}
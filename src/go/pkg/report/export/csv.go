@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// formulaPrefixes are the characters that make a spreadsheet read a cell as a
+// formula, including their full-width forms.
+const formulaPrefixes = "=+-@\t\r＝＋－＠"
+
+// Neutralize makes a text value safe to open in a spreadsheet: a value starting with
+// a formula character is prefixed with a single quote so it is shown as text instead
+// of evaluated.
+func Neutralize(value string) string {
+	if startsLikeFormula(value) {
+		return "'" + value
+	}
+	return value
+}
+
+// startsLikeFormula reports whether a value starts with a formula character.
+func startsLikeFormula(value string) bool {
+	r, size := utf8.DecodeRuneInString(value)
+	return size > 0 && strings.ContainsRune(formulaPrefixes, r)
+}
+
+// WriteCSV writes a table as RFC 4180 CSV: a header row, CRLF line endings, and
+// quoting where needed. Text cells are neutralized.
+func WriteCSV(w io.Writer, t Table) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+
+	record := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		record[i] = Neutralize(col.Header)
+	}
+	if err := cw.Write(record); err != nil {
+		return fmt.Errorf("failed to write %s CSV: %w", t.Name, err)
+	}
+
+	for _, row := range t.Rows {
+		for i, cell := range row {
+			if cell.Numeric {
+				record[i] = cell.String()
+			} else {
+				record[i] = Neutralize(cell.Text)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write %s CSV: %w", t.Name, err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write %s CSV: %w", t.Name, err)
+	}
+	return nil
+}
+
+// WriteJSONL writes a table as JSON Lines: one object per row with the column keys
+// in column order. Values are written as they are; JSON is not read as formulas.
+func WriteJSONL(w io.Writer, t Table) error {
+	bw := bufio.NewWriter(w)
+	for _, row := range t.Rows {
+		bw.WriteByte('{')
+		for i, cell := range row {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			key, _ := json.Marshal(t.Columns[i].Key)
+			bw.Write(key)
+			bw.WriteByte(':')
+			if cell.Numeric {
+				bw.WriteString(cell.String())
+			} else {
+				value, _ := json.Marshal(cell.Text)
+				bw.Write(value)
+			}
+		}
+		bw.WriteString("}\n")
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s JSON Lines: %w", t.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNeutralize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Jane Doe", "Jane Doe"},
+		{"=SUM(A1)", "'=SUM(A1)"},
+		{"+1 555", "'+1 555"},
+		{"-x", "'-x"},
+		{"@cmd", "'@cmd"},
+		{"\tTab", "'\tTab"},
+		{"＝HYPERLINK()", "'＝HYPERLINK()"},
+		{"a=b", "a=b"},
+		{"\xff=", "\xff="},
+	}
+	for _, tt := range tests {
+		if got := Neutralize(tt.value); got != tt.want {
+			t.Errorf("Neutralize(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		table Table
+		want  string
+	}{
+		{
+			name:  "header only",
+			table: Table{Name: "t", Columns: []Column{{"Name", "name"}, {"Count", "count"}}},
+			want:  "Name,Count\r\n",
+		},
+		{
+			name: "quoting, numbers, and formulas",
+			table: Table{
+				Name:    "t",
+				Columns: []Column{{"Name", "name"}, {"Count", "count"}},
+				Rows: [][]Cell{
+					{text("Doe, Jane"), number(-3)},
+					{text("=1+1"), number(0)},
+					{text("say \"hi\""), number(7)},
+				},
+			},
+			want: "Name,Count\r\n\"Doe, Jane\",-3\r\n'=1+1,0\r\n\"say \"\"hi\"\"\",7\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteCSV(&b, tt.table); err != nil {
+				t.Fatalf("WriteCSV() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("WriteCSV() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	table := Table{
+		Name:    "t",
+		Columns: []Column{{"Name", "name"}, {"Count", "count"}},
+		Rows:    [][]Cell{{text("=1+1"), number(2)}},
+	}
+	var b bytes.Buffer
+	if err := WriteJSONL(&b, table); err != nil {
+		t.Fatalf("WriteJSONL() error = %v", err)
+	}
+	if want := "{\"name\":\"=1+1\",\"count\":2}\n"; b.String() != want {
+		t.Errorf("WriteJSONL() = %q, want %q", b.String(), want)
+	}
+}
@@ -0,0 +1,375 @@
+// Package export writes a report.MasterReport as an XLSX workbook, RFC 4180 CSV, or
+// JSON Lines. Every format is built from the same tables, so columns appear in the
+// same order everywhere.
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"uar/pkg/engine"
+	"uar/pkg/report"
+)
+
+// Table names, which are also the XLSX sheet names.
+const (
+	TableSummary   = "Summary"
+	TableEntries   = "All Entries"
+	TableOrphans   = "Orphans"
+	TableConflicts = "Conflicts"
+	TableFindings  = "Findings"
+	TableMetadata  = "Metadata"
+)
+
+// Column is one column of a table.
+type Column struct {
+	// Header is the column title in XLSX and CSV.
+	Header string
+	// Key is the field name in JSON Lines.
+	Key string
+}
+
+// Cell is a single table value: text, or a number when Numeric is set.
+type Cell struct {
+	Text    string
+	Number  int
+	Numeric bool
+}
+
+// text returns a text cell.
+func text(s string) Cell {
+	return Cell{Text: s}
+}
+
+// number returns a numeric cell.
+func number(n int) Cell {
+	return Cell{Number: n, Numeric: true}
+}
+
+// String returns the cell's value as text.
+func (c Cell) String() string {
+	if c.Numeric {
+		return strconv.Itoa(c.Number)
+	}
+	return c.Text
+}
+
+// Table is a named grid of cells with a fixed column order.
+type Table struct {
+	Name    string
+	Columns []Column
+	Rows    [][]Cell
+}
+
+// Tables returns every table of the report in workbook order: summary, all entries,
+// orphans, conflicts, findings, and metadata.
+func Tables(r *report.MasterReport) []Table {
+	return []Table{
+		SummaryTable(r),
+		EntriesTable(r, TableEntries, r.AllEntries),
+		EntriesTable(r, TableOrphans, r.OrphanEntries),
+		ConflictsTable(r),
+		FindingsTable(r),
+		MetadataTable(r),
+	}
+}
+
+// entryColumns are the columns of every table of report entries.
+var entryColumns = []Column{
+	{"Canonical ID", "canonicalId"},
+	{"Employee ID", "employeeId"},
+	{"Display Name", "displayName"},
+	{"Email", "email"},
+	{"Department", "department"},
+	{"Manager", "manager"},
+	{"Employment Status", "employmentStatus"},
+	{"System", "system"},
+	{"Role", "role"},
+	{"Entitlement", "entitlement"},
+	{"Last Login", "lastLogin"},
+	{"Account Status", "accountStatus"},
+	{"Match Type", "matchType"},
+	{"Account Class", "accountClass"},
+	{"Privilege Tier", "privilegeTier"},
+	{"Risk Level", "riskLevel"},
+	{"Risk Score", "riskScore"},
+	{"Findings", "findings"},
+	{"Reviewer", "reviewer"},
+	{"Review Action", "reviewAction"},
+	{"Review Note", "reviewNote"},
+	{"Source File", "sourceFile"},
+	{"Source Row", "sourceRow"},
+}
+
+// EntriesTable returns a table of report entries. Review columns are filled from the
+// report's campaign, when it has one.
+func EntriesTable(r *report.MasterReport, name string, entries []report.MasterReportEntry) Table {
+	items := reviewItems(r.Campaign)
+
+	t := Table{Name: name, Columns: entryColumns, Rows: make([][]Cell, 0, len(entries))}
+	for _, e := range entries {
+		var tier, reviewer, action, note string
+		if e.Privilege != nil {
+			tier = string(e.Privilege.Tier)
+		}
+		if item := items[entryItem{report.AccessKeyOf(e), e.SourceRow}]; item != nil && e.MatchType != "no_access" {
+			reviewer = item.Reviewer
+			if item.Decision != nil {
+				action = decisionActions(item.Decision)
+				note = item.Decision.Notes
+			}
+		}
+
+		t.Rows = append(t.Rows, []Cell{
+			text(e.CanonicalID),
+			text(e.EmployeeID),
+			text(e.DisplayName),
+			text(e.Email),
+			text(e.Department),
+			text(e.Manager),
+			text(e.EmploymentStatus),
+			text(e.System),
+			text(e.Role),
+			text(e.Entitlement),
+			text(e.LastLogin),
+			text(e.AccountStatus),
+			text(e.MatchType),
+			text(string(e.AccountClass)),
+			text(tier),
+			text(string(e.RiskLevel)),
+			number(e.RiskScore),
+			text(findingCodes(e.Findings)),
+			text(reviewer),
+			text(action),
+			text(note),
+			text(e.SourceFile),
+			number(e.SourceRow),
+		})
+	}
+	return t
+}
+
+// SummaryTable returns the report totals, risk levels, finding counts, and campaign
+// progress as metric and value rows.
+func SummaryTable(r *report.MasterReport) Table {
+	t := Table{
+		Name:    TableSummary,
+		Columns: []Column{{"Metric", "metric"}, {"Value", "value"}},
+	}
+	add := func(metric string, value int) {
+		t.Rows = append(t.Rows, []Cell{text(metric), number(value)})
+	}
+
+	add("Total Users", r.TotalUsers)
+	add("Matched Accounts", r.TotalMatched)
+	add("Orphan Accounts", r.TotalOrphans)
+	add("Non-Human Accounts", r.TotalNonHuman)
+	add("Users Without Access", r.TotalNoAccess)
+	add("Critical", r.RiskSummary.Critical)
+	add("High", r.RiskSummary.High)
+	add("Medium", r.RiskSummary.Medium)
+	add("Low", r.RiskSummary.Low)
+	add("Info", r.RiskSummary.Info)
+	add("Duplicate Identities", r.TotalDuplicates)
+	add("SoD Conflicts", r.TotalSoDConflicts)
+	add("Manager Findings", r.TotalOrgFindings)
+
+	addCounts := func(prefix string, counts map[string]int) {
+		keys := make([]string, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			add(prefix+key, counts[key])
+		}
+	}
+	addCounts("Finding: ", r.FindingCounts)
+	addCounts("SoD Rule: ", r.SoDFindingCounts)
+	addCounts("Manager Finding: ", r.OrgFindingCounts)
+
+	if c := r.Campaign; c != nil {
+		add("Review Items", c.Progress.Total)
+		add("Reviews Completed", c.Progress.Completed)
+		add("Reviews Pending", c.Progress.Pending)
+		add("Approved", c.Progress.Approved)
+		add("Revoked", c.Progress.Revoked)
+		add("Unassigned Review Items", len(c.Unassigned))
+	}
+	return t
+}
+
+// ConflictsTable returns one row per field conflict between a SoT record and a
+// matched account.
+func ConflictsTable(r *report.MasterReport) Table {
+	t := Table{
+		Name: TableConflicts,
+		Columns: []Column{
+			{"Canonical ID", "canonicalId"},
+			{"Display Name", "displayName"},
+			{"System", "system"},
+			{"Source Row", "sourceRow"},
+			{"Field", "field"},
+			{"SoT Value", "sotValue"},
+			{"Satellite Value", "satelliteValue"},
+			{"Severity", "severity"},
+			{"Resolution", "resolution"},
+			{"Resolved Value", "resolvedValue"},
+		},
+		Rows: make([][]Cell, 0),
+	}
+	for _, e := range r.AllEntries {
+		for _, c := range e.Conflicts {
+			t.Rows = append(t.Rows, []Cell{
+				text(e.CanonicalID),
+				text(e.DisplayName),
+				text(e.System),
+				number(e.SourceRow),
+				text(c.Field),
+				text(c.SoTValue),
+				text(c.SatelliteValue),
+				text(string(c.Severity)),
+				text(string(c.Resolution)),
+				text(c.ResolvedValue),
+			})
+		}
+	}
+	return t
+}
+
+// FindingsTable returns one row per finding: account findings in entry order, then
+// each user's SoD and manager findings in user order.
+func FindingsTable(r *report.MasterReport) Table {
+	t := Table{
+		Name: TableFindings,
+		Columns: []Column{
+			{"Scope", "scope"},
+			{"Canonical ID", "canonicalId"},
+			{"Display Name", "displayName"},
+			{"System", "system"},
+			{"Source Row", "sourceRow"},
+			{"Code", "code"},
+			{"Level", "level"},
+			{"Score", "score"},
+			{"Description", "description"},
+		},
+		Rows: make([][]Cell, 0),
+	}
+	add := func(scope, canonicalID, displayName, system string, sourceRow int, code string, level engine.RiskLevel, score int, description string) {
+		row := []Cell{text(scope), text(canonicalID), text(displayName), text(system), text(""), text(code), text(string(level)), number(score), text(description)}
+		if system != "" {
+			row[4] = number(sourceRow)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	for _, e := range r.AllEntries {
+		for _, f := range e.Findings {
+			add("account", e.CanonicalID, e.DisplayName, e.System, e.SourceRow, f.Code, f.Level, f.Score, f.Description)
+		}
+	}
+	for _, u := range r.Users {
+		for _, f := range u.SoDFindings {
+			add("sod", u.CanonicalID, u.DisplayName, "", 0, f.RuleID, f.Level, f.Score, f.Description)
+		}
+		for _, f := range u.OrgFindings {
+			add("manager", u.CanonicalID, u.DisplayName, "", 0, f.Code, f.Level, f.Score, f.Description)
+		}
+	}
+	return t
+}
+
+// MetadataTable returns what the report was computed from as property and value rows.
+func MetadataTable(r *report.MasterReport) Table {
+	m := r.Metadata
+	t := Table{
+		Name:    TableMetadata,
+		Columns: []Column{{"Property", "property"}, {"Value", "value"}},
+	}
+	add := func(property, value string) {
+		t.Rows = append(t.Rows, []Cell{text(property), text(value)})
+	}
+
+	add("Processing Timestamp", time.UnixMilli(m.ProcessingTimestamp).UTC().Format(time.RFC3339))
+	add("Input Hash", m.InputHash)
+	add("Join Hash", m.JoinHash)
+	add("Config Hash", m.ConfigHash)
+	add("Time Zone", m.RiskConfig.TimeZone)
+	add("Dormancy Days", strconv.Itoa(m.RiskConfig.DormancyDays))
+	add("Termination Grace Days", strconv.Itoa(m.RiskConfig.TerminationGraceDays))
+
+	systems := make([]string, 0, len(m.RiskConfig.SystemDormancyDays))
+	for system := range m.RiskConfig.SystemDormancyDays {
+		systems = append(systems, system)
+	}
+	sort.Strings(systems)
+	for _, system := range systems {
+		add("Dormancy Days: "+system, strconv.Itoa(m.RiskConfig.SystemDormancyDays[system]))
+	}
+
+	if catalog := m.RiskConfig.Privileges; catalog != nil {
+		systems = systems[:0]
+		for system := range catalog.Systems {
+			systems = append(systems, system)
+		}
+		sort.Strings(systems)
+		for _, system := range systems {
+			for _, rule := range catalog.Systems[system] {
+				add("Privilege Rule: "+system, fmt.Sprintf("%s %s %q (weight %d)", rule.Tier, rule.Match, rule.Value, rule.Weight))
+			}
+		}
+	}
+
+	if c := r.Campaign; c != nil {
+		add("Campaign", c.Name)
+		if c.CarryForward != nil {
+			add("Decisions Carried From", c.CarryForward.From.Campaign)
+		}
+	}
+	return t
+}
+
+// entryItem identifies the report entry a review item covers. Entries repeating an
+// AccessKey, such as one account's rows for several roles, differ by source row.
+type entryItem struct {
+	key       report.AccessKey
+	sourceRow int
+}
+
+// reviewItems indexes a campaign's review items by the entry they cover. A nil
+// campaign gives an empty index.
+func reviewItems(c *report.Campaign) map[entryItem]*report.ReviewItem {
+	items := make(map[entryItem]*report.ReviewItem)
+	if c == nil {
+		return items
+	}
+	for _, item := range c.ItemIndex() {
+		items[entryItem{item.Key, item.SourceRow}] = item
+	}
+	return items
+}
+
+// decisionActions formats a decision as its action, or its role actions as
+// "role:action; role:action" when it has any.
+func decisionActions(d *report.ReviewDecision) string {
+	if len(d.RoleActions) == 0 {
+		return string(d.Action)
+	}
+	parts := make([]string, 0, len(d.RoleActions))
+	for _, ra := range d.RoleActions {
+		parts = append(parts, ra.Role+":"+string(ra.Action))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// findingCodes joins the codes of an entry's findings.
+func findingCodes(findings []engine.RiskFinding) string {
+	codes := make([]string, 0, len(findings))
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+	return strings.Join(codes, "; ")
+}
@@ -0,0 +1,162 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"uar/pkg/report"
+)
+
+// XLSX package parts that do not depend on the report.
+const (
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	// xlsxStyles defines style 0 as the default, style 1 as bold for header rows, and
+	// style 2 as quote-prefixed text for values that start with a formula character.
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts><fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills><borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders><cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs><cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0" quotePrefix="1"/></cellXfs></styleSheet>`
+)
+
+// WriteXLSX writes the report as an XLSX workbook with one sheet per table from
+// Tables. Text that starts with a formula character is stored as it is with a quote
+// prefix style, and the output is byte-identical for the same report.
+func WriteXLSX(w io.Writer, r *report.MasterReport) error {
+	modified := time.UnixMilli(r.Metadata.ProcessingTimestamp).UTC()
+	if modified.Year() < 1980 {
+		// Zip timestamps start in 1980
+		modified = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return writeWorkbook(w, Tables(r), modified)
+}
+
+// writeWorkbook writes tables as the sheets of an XLSX package. Every part is stamped
+// with modified so the archive does not depend on the clock.
+func writeWorkbook(w io.Writer, tables []Table, modified time.Time) error {
+	zw := zip.NewWriter(w)
+	writePart := func(name, content string) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return fmt.Errorf("failed to write XLSX part %s: %w", name, err)
+		}
+		if _, err := io.WriteString(fw, content); err != nil {
+			return fmt.Errorf("failed to write XLSX part %s: %w", name, err)
+		}
+		return nil
+	}
+
+	var contentTypes, workbook, workbookRels bytes.Buffer
+	contentTypes.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i, t := range tables {
+		n := strconv.Itoa(i + 1)
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%s.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%s" r:id="rId%s"/>`, xmlEscape(t.Name), n, n)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%s.xml"/>`, n, n)
+	}
+	stylesID := strconv.Itoa(len(tables) + 1)
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, stylesID)
+
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	workbookRels.WriteString(`</Relationships>`)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, t := range tables {
+		parts = append(parts, struct{ name, content string }{
+			fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheetXML(t),
+		})
+	}
+	for _, p := range parts {
+		if err := writePart(p.name, p.content); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
+
+// worksheetXML renders a table as a worksheet with a bold, frozen header row and an
+// autofilter. Text is stored as inline strings, which are never evaluated, so values
+// keep their text; the quote prefix style only stops a formula from being entered
+// when a user edits the cell.
+func worksheetXML(t Table) string {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	for i, col := range t.Columns {
+		writeTextCell(&b, cellRef(i, 1), col.Header, 1)
+	}
+	b.WriteString(`</row>`)
+
+	for r, row := range t.Rows {
+		rowNum := r + 2
+		fmt.Fprintf(&b, `<row r="%d">`, rowNum)
+		for i, cell := range row {
+			if cell.Numeric {
+				fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, cellRef(i, rowNum), cell.Number)
+			} else if cell.Text != "" {
+				style := 0
+				if startsLikeFormula(cell.Text) {
+					style = 2
+				}
+				writeTextCell(&b, cellRef(i, rowNum), cell.Text, style)
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	if len(t.Columns) > 0 {
+		fmt.Fprintf(&b, `<autoFilter ref="A1:%s"/>`, cellRef(len(t.Columns)-1, len(t.Rows)+1))
+	}
+	b.WriteString(`</worksheet>`)
+	return b.String()
+}
+
+// writeTextCell writes an inline string cell with the given style.
+func writeTextCell(b *bytes.Buffer, ref, value string, style int) {
+	b.WriteString(`<c r="` + ref + `" t="inlineStr"`)
+	if style != 0 {
+		fmt.Fprintf(b, ` s="%d"`, style)
+	}
+	b.WriteString(`><is><t xml:space="preserve">` + xmlEscape(value) + `</t></is></c>`)
+}
+
+// cellRef returns the A1 reference of a zero-based column and one-based row.
+func cellRef(col, row int) string {
+	var name []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = append([]byte{byte('A' + (col-1)%26)}, name...)
+	}
+	return string(name) + strconv.Itoa(row)
+}
+
+// xmlEscape escapes text for XML, replacing characters XML cannot hold.
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
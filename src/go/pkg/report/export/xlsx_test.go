@@ -0,0 +1,71 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"uar/pkg/report"
+)
+
+func TestWorksheetXML(t *testing.T) {
+	table := Table{
+		Name:    "t",
+		Columns: []Column{{"Name", "name"}, {"Count", "count"}},
+		Rows:    [][]Cell{{text("=1+1"), number(2)}, {text("a<b"), number(3)}},
+	}
+	xml := worksheetXML(table)
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"bold header", `<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Name</t></is></c>`},
+		{"formula text kept with quote prefix style", `<c r="A2" t="inlineStr" s="2"><is><t xml:space="preserve">=1+1</t></is></c>`},
+		{"number", `<c r="B2"><v>2</v></c>`},
+		{"escaped text", `<c r="A3" t="inlineStr"><is><t xml:space="preserve">a&lt;b</t></is></c>`},
+		{"autofilter", `<autoFilter ref="A1:B3"/>`},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(xml, tt.want) {
+			t.Errorf("%s: worksheet does not contain %s", tt.name, tt.want)
+		}
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	tests := []struct {
+		col, row int
+		want     string
+	}{
+		{0, 1, "A1"},
+		{25, 2, "Z2"},
+		{26, 3, "AA3"},
+		{701, 4, "ZZ4"},
+		{702, 5, "AAA5"},
+	}
+	for _, tt := range tests {
+		if got := cellRef(tt.col, tt.row); got != tt.want {
+			t.Errorf("cellRef(%d, %d) = %q, want %q", tt.col, tt.row, got, tt.want)
+		}
+	}
+}
+
+func TestWriteXLSXIsDeterministic(t *testing.T) {
+	r := &report.MasterReport{
+		Metadata:      report.ReportMetadata{ProcessingTimestamp: 1767225600000},
+		AllEntries:    []report.MasterReportEntry{{CanonicalID: "jane", DisplayName: "=Jane", System: "okta", SourceRow: 1}},
+		FindingCounts: map[string]int{"orphan": 1, "dormant": 2},
+	}
+
+	var first, second bytes.Buffer
+	if err := WriteXLSX(&first, r); err != nil {
+		t.Fatalf("WriteXLSX() error = %v", err)
+	}
+	if err := WriteXLSX(&second, r); err != nil {
+		t.Fatalf("WriteXLSX() error = %v", err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("WriteXLSX() output differs between runs")
+	}
+}
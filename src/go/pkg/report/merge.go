@@ -1,6 +1,9 @@
 package report
 
 import (
+	"sort"
+	"strconv"
+
 	"uar/pkg/engine"
 	"uar/pkg/schema"
 )
@@ -15,6 +18,8 @@ type MasterReportEntry struct {
 	Department       string           `json:"department"`
 	Manager          string           `json:"manager"`
 	EmploymentStatus string           `json:"employmentStatus"`
+	// AdminInfo is the SoT's admin columns for the person, for matched entries.
+	AdminInfo        string           `json:"adminInfo,omitempty"`
 	System           string           `json:"system"`
 	Role             string           `json:"role"`
 	Entitlement      string           `json:"entitlement"`
@@ -24,6 +29,13 @@ type MasterReportEntry struct {
 	RiskLevel        engine.RiskLevel `json:"riskLevel"`
 	RiskScore        int              `json:"riskScore"`
 	Conflicts        []engine.FieldConflict `json:"conflicts,omitempty"`
+	Override         *engine.LinkOverride   `json:"override,omitempty"`
+	// Owner is the canonical ID of a non-human account's reviewer-confirmed owner.
+	Owner            string                 `json:"owner,omitempty"`
+	AccountClass     engine.AccountClass    `json:"accountClass,omitempty"`
+	Findings         []engine.RiskFinding   `json:"findings,omitempty"`
+	// Privilege is the account's most powerful grant, when elevated or higher.
+	Privilege        *engine.PrivilegeAssessment `json:"privilege,omitempty"`
 	SourceFile       string           `json:"sourceFile"`
 	SourceRow        int              `json:"sourceRow"`
 }
@@ -36,18 +48,74 @@ type UserSummary struct {
 	MaxRiskLevel engine.RiskLevel    `json:"maxRiskLevel"`
 	MaxRiskScore int                 `json:"maxRiskScore"`
 	Entries      []MasterReportEntry `json:"entries"`
+	// SoDFindings are segregation-of-duties conflicts across the user's active accounts.
+	SoDFindings []engine.SoDFinding `json:"sodFindings,omitempty"`
+	// OrgFindings are problems with the user's manager: missing, terminated, or a loop.
+	OrgFindings []engine.RiskFinding `json:"orgFindings,omitempty"`
+}
+
+// OrgRollup summarizes a manager's subtree: the manager and everyone reporting to them
+// directly or indirectly.
+type OrgRollup struct {
+	CanonicalID    string                `json:"canonicalId"`
+	DisplayName    string                `json:"displayName"`
+	LifecycleState schema.LifecycleState `json:"lifecycleState,omitempty"`
+	DirectReports  int                   `json:"directReports"`
+	// Headcount is the number of people in the subtree, including the manager.
+	Headcount int `json:"headcount"`
+	// Accounts is the number of satellite accounts held across the subtree.
+	Accounts     int              `json:"accounts"`
+	MaxRiskLevel engine.RiskLevel `json:"maxRiskLevel"`
+	MaxRiskScore int              `json:"maxRiskScore"`
+	// RiskSummary counts the subtree's report entries at each risk level.
+	RiskSummary RiskSummary `json:"riskSummary"`
+	// OrgFindings is the number of manager hierarchy findings in the subtree.
+	OrgFindings int `json:"orgFindings"`
 }
 
 // MasterReport is the final compiled report containing all users and their access.
+//
+// Output order is deterministic for the same inputs and configuration:
+//   - join results are processed sorted by system name
+//   - AllEntries holds matched, orphan, and non-human records in join order per system,
+//     followed by no_access records in SoT input order
+//   - Users are in order of first appearance in AllEntries
+//   - Duplicates hold SoT findings first, then satellite findings per system
+//   - OrgRollups are in SoT order of the managers
 type MasterReport struct {
+	Metadata        ReportMetadata      `json:"metadata"`
 	Users           []UserSummary       `json:"users"`
 	OrphanEntries   []MasterReportEntry `json:"orphanEntries"`
+	NonHumanEntries []MasterReportEntry `json:"nonHumanEntries"`
 	AllEntries      []MasterReportEntry `json:"allEntries"`
 	TotalUsers      int                 `json:"totalUsers"`
 	TotalMatched    int                 `json:"totalMatched"`
 	TotalOrphans    int                 `json:"totalOrphans"`
+	TotalNonHuman   int                 `json:"totalNonHuman"`
 	TotalNoAccess   int                 `json:"totalNoAccess"`
-	RiskSummary     RiskSummary         `json:"riskSummary"`
+	// RiskSummary counts entries at each risk level.
+	RiskSummary RiskSummary `json:"riskSummary"`
+	// FindingCounts is the number of entries carrying each finding code.
+	FindingCounts map[string]int `json:"findingCounts"`
+	// UserRiskSummary counts users' SoD and manager findings at each risk level.
+	// They belong to users rather than entries, so RiskSummary leaves them out.
+	UserRiskSummary RiskSummary `json:"userRiskSummary"`
+	// SoDFindingCounts is the number of users violating each SoD rule ID.
+	SoDFindingCounts map[string]int `json:"sodFindingCounts"`
+	// OrgFindingCounts is the number of users with each manager finding code.
+	OrgFindingCounts map[string]int `json:"orgFindingCounts"`
+	// Duplicates lists SoT key collisions, rehires, and satellite systems where
+	// several accounts resolve to the same person.
+	Duplicates      []engine.DuplicateFinding `json:"duplicates"`
+	TotalDuplicates int                       `json:"totalDuplicates"`
+	// TotalSoDConflicts is the number of SoD findings across all users.
+	TotalSoDConflicts int `json:"totalSoDConflicts"`
+	// OrgRollups summarize access and risk for each manager's subtree.
+	OrgRollups []OrgRollup `json:"orgRollups"`
+	// TotalOrgFindings is the number of manager hierarchy findings across all users.
+	TotalOrgFindings int `json:"totalOrgFindings"`
+	// Campaign is the review campaign built with BuildCampaign, with its decisions.
+	Campaign *Campaign `json:"campaign,omitempty"`
 }
 
 // RiskSummary contains counts of findings at each risk level.
@@ -59,37 +127,94 @@ type RiskSummary struct {
 	Info     int `json:"info"`
 }
 
+// MergeOptions configures MergeResults. The zero value scores with the default risk policy.
+type MergeOptions struct {
+	RiskPolicy *engine.RiskPolicy
+	// Risk holds the dormancy thresholds, privilege catalog, and time zone used for
+	// scoring. Its ProcessingTimestamp, when set, replaces MergeResults' argument.
+	Risk engine.RiskConfig
+	// SoDMatrix holds segregation-of-duties rules; nil skips SoD checks.
+	SoDMatrix *engine.SoDMatrix
+	// ConflictChecks detects field conflicts on transitively linked accounts. Pass the
+	// checks the join results were built with; nil uses engine.DefaultConflictChecks.
+	ConflictChecks []engine.ConflictCheck
+}
+
 // MergeResults compiles join results from all satellite systems into a unified master report.
-// It groups entries by canonicalId, computes per-user max risk, and identifies SoT users
-// with no satellite presence (NO_ACCESS).
+// It groups entries by canonicalId, computes per-user max risk, identifies SoT users
+// with no satellite presence (NO_ACCESS), and collects duplicate identity findings.
+// Managers are resolved with engine.BuildOrgHierarchy for manager findings and
+// per-manager rollups.
+// Orphans that reach a SoT person through another system's account are first linked
+// via engine.LinkTransitiveIdentities. Linking works on copies, so the caller's join
+// results are not modified. An error is returned only when the inputs cannot be
+// hashed for the report metadata.
 func MergeResults(
 	sotIndex *engine.SoTIndex,
 	joinResults []*engine.JoinResult,
 	processingTimestamp int64,
-) *MasterReport {
+	opts MergeOptions,
+) (*MasterReport, error) {
+	if opts.RiskPolicy == nil {
+		opts.RiskPolicy = engine.DefaultRiskPolicy()
+	}
+	opts.Risk = opts.Risk.WithDefaults(processingTimestamp)
+	if opts.Risk.Privileges == nil {
+		opts.Risk.Privileges = engine.DefaultPrivilegeCatalog()
+	}
+
+	metadata, err := newReportMetadata(sotIndex, joinResults, opts)
+	if err != nil {
+		return nil, err
+	}
+
 	report := &MasterReport{
-		Users:         make([]UserSummary, 0),
-		OrphanEntries: make([]MasterReportEntry, 0),
-		AllEntries:    make([]MasterReportEntry, 0),
+		Metadata:         metadata,
+		Users:            make([]UserSummary, 0),
+		OrphanEntries:    make([]MasterReportEntry, 0),
+		NonHumanEntries:  make([]MasterReportEntry, 0),
+		AllEntries:       make([]MasterReportEntry, 0),
+		Duplicates:       engine.DetectSoTDuplicates(sotIndex.Records),
+		FindingCounts:    make(map[string]int),
+		SoDFindingCounts: make(map[string]int),
+		OrgFindingCounts: make(map[string]int),
+	}
+
+	joinResults = sortJoinResults(joinResults)
+	engine.LinkTransitiveIdentities(joinResults, opts.ConflictChecks)
+	org := engine.BuildOrgHierarchy(sotIndex)
+
+	// Peer outlier findings, keyed by satellite account
+	peerFindings := make(map[string][]engine.RiskFinding)
+	if opts.Risk.PeerOutliers != nil {
+		for _, o := range engine.FindPeerOutliers(joinResults, *opts.Risk.PeerOutliers) {
+			key := entryKey(o.System, o.SourceRow)
+			peerFindings[key] = append(peerFindings[key], o.Finding())
+		}
 	}
 
 	// Track which SoT users have satellite presence
 	usersWithAccess := make(map[string]bool)
 
-	// Group entries by canonical ID
+	// Group entries by canonical ID, remembering first-seen order
 	userEntriesMap := make(map[string][]MasterReportEntry)
+	var userOrder []string
+	addUserEntry := func(canonicalID string, entry MasterReportEntry) {
+		if _, ok := userEntriesMap[canonicalID]; !ok {
+			userOrder = append(userOrder, canonicalID)
+		}
+		userEntriesMap[canonicalID] = append(userEntriesMap[canonicalID], entry)
+	}
 
 	// Process matched records from all join results
 	for _, jr := range joinResults {
+		report.Duplicates = append(report.Duplicates, engine.DetectSatelliteDuplicates(jr)...)
+
 		for _, matched := range jr.Matched {
-			riskLevel, riskScore := engine.ScoreRisk(
-				matched.SoT,
-				matched.Satellite,
-				matched.MatchType,
-				processingTimestamp,
-				90, // default dormancy days
-				nil, // default privileged keywords
-			)
+			findings := opts.RiskPolicy.Evaluate(matched.SoT, matched.Satellite, matched.MatchType, opts.Risk)
+			findings = append(findings, engine.ConflictFindings(matched.Conflicts)...)
+			findings = append(findings, peerFindings[entryKey(matched.Satellite.SourceFile, matched.Satellite.SourceRow)]...)
+			riskLevel, riskScore := opts.RiskPolicy.Aggregate(findings)
 
 			entry := MasterReportEntry{
 				CanonicalID:      matched.SoT.CanonicalID,
@@ -99,6 +224,7 @@ func MergeResults(
 				Department:       matched.SoT.Department,
 				Manager:          matched.SoT.Manager,
 				EmploymentStatus: matched.SoT.EmploymentStatus,
+				AdminInfo:        matched.SoT.AdminInfo,
 				System:           matched.Satellite.SourceFile,
 				Role:             matched.Satellite.Role,
 				Entitlement:      matched.Satellite.Entitlement,
@@ -108,6 +234,10 @@ func MergeResults(
 				RiskLevel:        riskLevel,
 				RiskScore:        riskScore,
 				Conflicts:        matched.Conflicts,
+				Override:         matched.Override,
+				AccountClass:     matched.Classification.Class,
+				Findings:         findings,
+				Privilege:        privilegeOf(opts.Risk.Privileges, matched.Satellite),
 				SourceFile:       matched.Satellite.SourceFile,
 				SourceRow:        matched.Satellite.SourceRow,
 			}
@@ -116,24 +246,17 @@ func MergeResults(
 			report.TotalMatched++
 			usersWithAccess[matched.SoT.CanonicalID] = true
 
-			userEntriesMap[matched.SoT.CanonicalID] = append(
-				userEntriesMap[matched.SoT.CanonicalID], entry,
-			)
+			addUserEntry(matched.SoT.CanonicalID, entry)
 
 			// Update risk summary
 			updateRiskSummary(&report.RiskSummary, riskLevel)
+			countFindings(report.FindingCounts, entry.Findings)
 		}
 
 		// Process orphan records
 		for _, orphan := range jr.Orphans {
-			riskLevel, riskScore := engine.ScoreRisk(
-				nil,
-				orphan.Satellite,
-				"orphan",
-				processingTimestamp,
-				90,
-				nil,
-			)
+			findings := opts.RiskPolicy.Evaluate(nil, orphan.Satellite, "orphan", opts.Risk)
+			riskLevel, riskScore := opts.RiskPolicy.Aggregate(findings)
 
 			entry := MasterReportEntry{
 				DisplayName:   orphan.Satellite.DisplayName,
@@ -146,6 +269,9 @@ func MergeResults(
 				MatchType:     "orphan",
 				RiskLevel:     riskLevel,
 				RiskScore:     riskScore,
+				AccountClass:  orphan.Classification.Class,
+				Findings:      findings,
+				Privilege:     privilegeOf(opts.Risk.Privileges, orphan.Satellite),
 				SourceFile:    orphan.Satellite.SourceFile,
 				SourceRow:     orphan.Satellite.SourceRow,
 			}
@@ -155,6 +281,39 @@ func MergeResults(
 			report.TotalOrphans++
 
 			updateRiskSummary(&report.RiskSummary, riskLevel)
+			countFindings(report.FindingCounts, entry.Findings)
+		}
+
+		// Process unmatched service, shared, and break-glass accounts
+		for _, nh := range jr.NonHuman {
+			finding := opts.RiskPolicy.ScoreNonHuman(nh, opts.Risk)
+
+			entry := MasterReportEntry{
+				DisplayName:   nh.Satellite.DisplayName,
+				Email:         nh.Satellite.Email,
+				System:        nh.Satellite.SourceFile,
+				Role:          nh.Satellite.Role,
+				Entitlement:   nh.Satellite.Entitlement,
+				LastLogin:     nh.Satellite.LastLogin,
+				AccountStatus: nh.Satellite.AccountStatus,
+				MatchType:     "non_human",
+				RiskLevel:     finding.Level,
+				RiskScore:     finding.Score,
+				Override:      nh.Override,
+				Owner:         ownerID(nh.Owner),
+				AccountClass:  nh.Classification.Class,
+				Findings:      []engine.RiskFinding{finding},
+				Privilege:     privilegeOf(opts.Risk.Privileges, nh.Satellite),
+				SourceFile:    nh.Satellite.SourceFile,
+				SourceRow:     nh.Satellite.SourceRow,
+			}
+
+			report.NonHumanEntries = append(report.NonHumanEntries, entry)
+			report.AllEntries = append(report.AllEntries, entry)
+			report.TotalNonHuman++
+
+			updateRiskSummary(&report.RiskSummary, finding.Level)
+			countFindings(report.FindingCounts, entry.Findings)
 		}
 	}
 
@@ -177,16 +336,15 @@ func MergeResults(
 			report.AllEntries = append(report.AllEntries, entry)
 			report.TotalNoAccess++
 
-			userEntriesMap[sotRec.CanonicalID] = append(
-				userEntriesMap[sotRec.CanonicalID], entry,
-			)
+			addUserEntry(sotRec.CanonicalID, entry)
 
 			updateRiskSummary(&report.RiskSummary, engine.RiskInfo)
 		}
 	}
 
 	// Build user summaries grouped by canonical ID
-	for canonicalID, entries := range userEntriesMap {
+	for _, canonicalID := range userOrder {
+		entries := userEntriesMap[canonicalID]
 		maxRiskLevel := engine.RiskInfo
 		maxRiskScore := 0
 		displayName := ""
@@ -205,6 +363,34 @@ func MergeResults(
 			}
 		}
 
+		// A SoD conflict raises the user's risk even though no single account is risky
+		sodFindings := opts.SoDMatrix.Evaluate(sodGrants(entries))
+		sodRules := make(map[string]bool, len(sodFindings))
+		for _, f := range sodFindings {
+			if f.Score > maxRiskScore {
+				maxRiskScore = f.Score
+				maxRiskLevel = f.Level
+			}
+			updateRiskSummary(&report.UserRiskSummary, f.Level)
+			if !sodRules[f.RuleID] {
+				sodRules[f.RuleID] = true
+				report.SoDFindingCounts[f.RuleID]++
+			}
+		}
+		report.TotalSoDConflicts += len(sodFindings)
+
+		// So does a broken reporting chain, which leaves nobody accountable for the access
+		orgFindings := org.Findings(canonicalID)
+		for _, f := range orgFindings {
+			if f.Score > maxRiskScore {
+				maxRiskScore = f.Score
+				maxRiskLevel = f.Level
+			}
+			updateRiskSummary(&report.UserRiskSummary, f.Level)
+		}
+		countFindings(report.OrgFindingCounts, orgFindings)
+		report.TotalOrgFindings += len(orgFindings)
+
 		report.Users = append(report.Users, UserSummary{
 			CanonicalID:  canonicalID,
 			DisplayName:  displayName,
@@ -212,41 +398,159 @@ func MergeResults(
 			MaxRiskLevel: maxRiskLevel,
 			MaxRiskScore: maxRiskScore,
 			Entries:      entries,
+			SoDFindings:  sodFindings,
+			OrgFindings:  orgFindings,
 		})
 	}
 
 	report.TotalUsers = len(report.Users)
+	report.OrgRollups = orgRollups(org, report.Users)
+
+	if report.Duplicates == nil {
+		report.Duplicates = make([]engine.DuplicateFinding, 0)
+	}
+	report.TotalDuplicates = len(report.Duplicates)
+
+	return report, nil
+}
+
+// orgRollups summarizes each manager's subtree from the user summaries.
+func orgRollups(org *engine.OrgHierarchy, users []UserSummary) []OrgRollup {
+	byID := make(map[string]*UserSummary, len(users))
+	for i := range users {
+		byID[users[i].CanonicalID] = &users[i]
+	}
+
+	rollups := make([]OrgRollup, 0)
+	for _, managerID := range org.Managers() {
+		manager := org.Record(managerID)
+		rollup := OrgRollup{
+			CanonicalID:    managerID,
+			DisplayName:    manager.DisplayName,
+			LifecycleState: manager.LifecycleState,
+			DirectReports:  len(org.DirectReports(managerID)),
+			MaxRiskLevel:   engine.RiskInfo,
+		}
+
+		for _, id := range org.Subtree(managerID) {
+			rollup.Headcount++
+			user := byID[id]
+			if user == nil {
+				continue
+			}
+			if user.MaxRiskScore > rollup.MaxRiskScore {
+				rollup.MaxRiskScore = user.MaxRiskScore
+				rollup.MaxRiskLevel = user.MaxRiskLevel
+			}
+			rollup.OrgFindings += len(user.OrgFindings)
+			for _, e := range user.Entries {
+				if e.MatchType != "no_access" {
+					rollup.Accounts++
+				}
+				updateRiskSummary(&rollup.RiskSummary, e.RiskLevel)
+			}
+		}
 
-	return report
+		rollups = append(rollups, rollup)
+	}
+	return rollups
 }
 
-// collectAllSoTRecords gathers all unique SoT records from the index.
+// collectAllSoTRecords gathers all unique SoT records from the index in input order.
+// When several records share a canonical ID, the first one wins.
 func collectAllSoTRecords(index *engine.SoTIndex) []*schema.SoTRecord {
 	seen := make(map[string]bool)
 	var records []*schema.SoTRecord
 
-	for _, rec := range index.ByEmail {
+	for _, rec := range index.Records {
 		if !seen[rec.CanonicalID] {
 			seen[rec.CanonicalID] = true
 			records = append(records, rec)
 		}
 	}
-	for _, rec := range index.ByEmployeeID {
-		if !seen[rec.CanonicalID] {
-			seen[rec.CanonicalID] = true
-			records = append(records, rec)
-		}
+
+	return records
+}
+
+// sortJoinResults returns copies of the join results ordered by system name, so the
+// report does not depend on the order in which satellite files finished processing.
+// The matched and orphan lists are copied too, since transitive linking rewrites them.
+func sortJoinResults(joinResults []*engine.JoinResult) []*engine.JoinResult {
+	sorted := make([]*engine.JoinResult, len(joinResults))
+	for i, jr := range joinResults {
+		c := *jr
+		c.Matched = append([]engine.MatchedRecord(nil), jr.Matched...)
+		c.Orphans = append([]engine.OrphanRecord(nil), jr.Orphans...)
+		sorted[i] = &c
 	}
-	for _, recs := range index.ByName {
-		for _, rec := range recs {
-			if !seen[rec.CanonicalID] {
-				seen[rec.CanonicalID] = true
-				records = append(records, rec)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].System < sorted[j].System
+	})
+	return sorted
+}
+
+// ownerID returns the canonical ID of a non-human account's owner, or "" when unknown.
+func ownerID(owner *schema.SoTRecord) string {
+	if owner == nil {
+		return ""
+	}
+	return owner.CanonicalID
+}
+
+// privilegeOf returns an account's privilege assessment, or nil for standard access.
+func privilegeOf(catalog *engine.PrivilegeCatalog, sat schema.SatelliteRecord) *engine.PrivilegeAssessment {
+	privilege := catalog.Lookup(sat.SourceFile, sat.Role, sat.Entitlement)
+	if !privilege.Privileged() {
+		return nil
+	}
+	return &privilege
+}
+
+// entryKey identifies a satellite account's report entry by system and source row.
+func entryKey(system string, sourceRow int) string {
+	return system + "\x00" + strconv.Itoa(sourceRow)
+}
+
+// sodGrants lists the individual role and entitlement values a user holds in active
+// accounts, for segregation-of-duties checks.
+func sodGrants(entries []MasterReportEntry) []engine.SoDGrant {
+	var grants []engine.SoDGrant
+	for _, e := range entries {
+		if e.MatchType == "no_access" || !engine.IsCurrentStatus(e.AccountStatus) {
+			continue
+		}
+		for _, value := range []string{e.Role, e.Entitlement} {
+			for _, g := range engine.SplitGrants(value) {
+				grants = append(grants, engine.SoDGrant{System: e.System, Grant: g, SourceRow: e.SourceRow})
 			}
 		}
 	}
+	return grants
+}
 
-	return records
+// countFindings counts each finding code once per entry.
+func countFindings(counts map[string]int, findings []engine.RiskFinding) {
+	seen := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		if !seen[f.Code] {
+			seen[f.Code] = true
+			counts[f.Code]++
+		}
+	}
+}
+
+// EntriesWithFinding returns the report entries carrying the given finding code.
+func (r *MasterReport) EntriesWithFinding(code string) []MasterReportEntry {
+	var entries []MasterReportEntry
+	for _, e := range r.AllEntries {
+		for _, f := range e.Findings {
+			if f.Code == code {
+				entries = append(entries, e)
+				break
+			}
+		}
+	}
+	return entries
 }
 
 // updateRiskSummary increments the appropriate counter in the risk summary.
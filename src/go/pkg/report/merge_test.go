@@ -0,0 +1,214 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"uar/pkg/engine"
+	"uar/pkg/schema"
+)
+
+func TestMergeResultsUserFindingCounts(t *testing.T) {
+	matrix, err := engine.ParseSoDMatrix([]byte(`
+rules:
+  - id: pay-approve
+    level: CRITICAL
+    left: {system: sap, pattern: "vendor.?create"}
+    right: {system: sap, pattern: "payment.?approve"}
+`))
+	if err != nil {
+		t.Fatalf("ParseSoDMatrix() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		manager      string
+		roles        []string
+		wantSoD      int
+		wantCritical int
+		wantMissing  int
+	}{
+		{name: "no user findings", roles: []string{"Vendor_Create"}},
+		{name: "SoD conflict", roles: []string{"Vendor_Create", "Payment Approve"}, wantSoD: 1, wantCritical: 1},
+		{name: "missing manager", manager: "nobody@acme.com", roles: []string{"Vendor_Create"}, wantMissing: 1},
+		{
+			name:         "both",
+			manager:      "nobody@acme.com",
+			roles:        []string{"Vendor_Create", "Payment Approve"},
+			wantSoD:      1,
+			wantCritical: 1,
+			wantMissing:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jane := &schema.SoTRecord{
+				CanonicalID:    "jane@acme.com",
+				EmployeeID:     "E1",
+				DisplayName:    "Jane Doe",
+				NormalizedName: "jane doe",
+				Email:          "jane@acme.com",
+				Manager:        tt.manager,
+				LifecycleState: schema.LifecycleActive,
+			}
+			jr := &engine.JoinResult{System: "sap"}
+			for i, role := range tt.roles {
+				jr.Matched = append(jr.Matched, engine.MatchedRecord{
+					SoT:       jane,
+					Satellite: schema.SatelliteRecord{Email: jane.Email, Role: role, SourceFile: "sap", SourceRow: i + 1},
+					MatchType: "exact_email",
+				})
+			}
+
+			report, err := MergeResults(engine.BuildSoTIndex([]*schema.SoTRecord{jane}), []*engine.JoinResult{jr}, 0, MergeOptions{SoDMatrix: matrix})
+			if err != nil {
+				t.Fatalf("MergeResults() error = %v", err)
+			}
+
+			if report.TotalSoDConflicts != tt.wantSoD || report.SoDFindingCounts["pay-approve"] != tt.wantSoD {
+				t.Errorf("SoD conflicts = %d, counted %d, want %d", report.TotalSoDConflicts, report.SoDFindingCounts["pay-approve"], tt.wantSoD)
+			}
+			if report.TotalOrgFindings != tt.wantMissing || report.OrgFindingCounts[engine.FindingManagerMissing] != tt.wantMissing {
+				t.Errorf("manager findings = %d, counted %d, want %d", report.TotalOrgFindings, report.OrgFindingCounts[engine.FindingManagerMissing], tt.wantMissing)
+			}
+			if _, ok := report.FindingCounts["pay-approve"]; ok {
+				t.Errorf("FindingCounts = %v, want SoD rule IDs kept out of the entry finding codes", report.FindingCounts)
+			}
+			if _, ok := report.FindingCounts[engine.FindingManagerMissing]; ok {
+				t.Errorf("FindingCounts = %v, want manager findings kept out of the entry finding codes", report.FindingCounts)
+			}
+
+			s := report.RiskSummary
+			if total := s.Critical + s.High + s.Medium + s.Low + s.Info; total != len(report.AllEntries) {
+				t.Errorf("summary %+v counts %d, want one per entry (%d)", s, total, len(report.AllEntries))
+			}
+			u := report.UserRiskSummary
+			if u.Critical != tt.wantCritical {
+				t.Errorf("user summary %+v, want %d critical", u, tt.wantCritical)
+			}
+			if total := u.Critical + u.High + u.Medium + u.Low + u.Info; total != report.TotalSoDConflicts+report.TotalOrgFindings {
+				t.Errorf("user summary %+v counts %d, want %d user findings", u, total, report.TotalSoDConflicts+report.TotalOrgFindings)
+			}
+		})
+	}
+}
+
+func TestMergeResultsOptions(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	later := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	policy, err := engine.ParseRiskPolicy([]byte(`
+rules:
+  - code: sap_access
+    level: LOW
+    score: 10
+    description: has SAP access
+    when:
+      - {field: satellite.system, op: equals, value: sap}
+`))
+	if err != nil {
+		t.Fatalf("ParseRiskPolicy() error = %v", err)
+	}
+	matrix, err := engine.ParseSoDMatrix([]byte(`
+rules:
+  - id: pay-approve
+    level: CRITICAL
+    left: {system: sap, pattern: "vendor.?create"}
+    right: {system: sap, pattern: "payment.?approve"}
+`))
+	if err != nil {
+		t.Fatalf("ParseSoDMatrix() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		opts          MergeOptions
+		wantCodes     map[string]int // entry finding counts
+		wantSoD       int
+		wantTimestamp int64
+		wantDormancy  int
+	}{
+		{
+			name:          "zero options use the defaults",
+			wantCodes:     map[string]int{},
+			wantTimestamp: now,
+			wantDormancy:  engine.DefaultDormancyDays,
+		},
+		{
+			name:          "risk policy scores the entries",
+			opts:          MergeOptions{RiskPolicy: policy},
+			wantCodes:     map[string]int{"sap_access": 2},
+			wantTimestamp: now,
+			wantDormancy:  engine.DefaultDormancyDays,
+		},
+		{
+			name:          "SoD matrix checks the user",
+			opts:          MergeOptions{SoDMatrix: matrix},
+			wantCodes:     map[string]int{},
+			wantSoD:       1,
+			wantTimestamp: now,
+			wantDormancy:  engine.DefaultDormancyDays,
+		},
+		{
+			name:          "risk config sets the thresholds and processing time",
+			opts:          MergeOptions{Risk: engine.RiskConfig{ProcessingTimestamp: later, DormancyDays: 30}},
+			wantCodes:     map[string]int{"dormant": 2},
+			wantTimestamp: later,
+			wantDormancy:  30,
+		},
+	}
+
+	var defaultConfigHash string
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jane := &schema.SoTRecord{
+				CanonicalID:    "jane@acme.com",
+				EmployeeID:     "E1",
+				DisplayName:    "Jane Doe",
+				NormalizedName: "jane doe",
+				Email:          "jane@acme.com",
+				LifecycleState: schema.LifecycleActive,
+			}
+			jr := &engine.JoinResult{System: "sap"}
+			for i, role := range []string{"Vendor_Create", "Payment Approve"} {
+				jr.Matched = append(jr.Matched, engine.MatchedRecord{
+					SoT:       jane,
+					Satellite: schema.SatelliteRecord{Email: jane.Email, Role: role, LastLogin: "2026-04-01", SourceFile: "sap", SourceRow: i + 1},
+					MatchType: "exact_email",
+				})
+			}
+
+			report, err := MergeResults(engine.BuildSoTIndex([]*schema.SoTRecord{jane}), []*engine.JoinResult{jr}, now, tt.opts)
+			if err != nil {
+				t.Fatalf("MergeResults() error = %v", err)
+			}
+
+			if len(report.FindingCounts) != len(tt.wantCodes) {
+				t.Errorf("FindingCounts = %v, want %v", report.FindingCounts, tt.wantCodes)
+			}
+			for code, n := range tt.wantCodes {
+				if report.FindingCounts[code] != n {
+					t.Errorf("FindingCounts[%s] = %d, want %d", code, report.FindingCounts[code], n)
+				}
+			}
+			if report.TotalSoDConflicts != tt.wantSoD {
+				t.Errorf("TotalSoDConflicts = %d, want %d", report.TotalSoDConflicts, tt.wantSoD)
+			}
+
+			meta := report.Metadata
+			if meta.ProcessingTimestamp != tt.wantTimestamp || meta.RiskConfig.ProcessingTimestamp != tt.wantTimestamp {
+				t.Errorf("processing timestamp = %d (config %d), want %d", meta.ProcessingTimestamp, meta.RiskConfig.ProcessingTimestamp, tt.wantTimestamp)
+			}
+			if meta.RiskConfig.DormancyDays != tt.wantDormancy {
+				t.Errorf("metadata dormancyDays = %d, want %d", meta.RiskConfig.DormancyDays, tt.wantDormancy)
+			}
+			if meta.RiskConfig.Privileges == nil {
+				t.Error("metadata privilege catalog = nil, want the default catalog")
+			}
+			if defaultConfigHash == "" {
+				defaultConfigHash = meta.ConfigHash
+			} else if meta.ConfigHash == defaultConfigHash {
+				t.Errorf("ConfigHash = %s, want it to differ from the default options", meta.ConfigHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"uar/pkg/engine"
+	"uar/pkg/schema"
+)
+
+// ReportMetadata identifies what a report was computed from. Two reports with the same
+// InputHash, JoinHash and ConfigHash are byte-identical.
+type ReportMetadata struct {
+	ProcessingTimestamp int64 `json:"processingTimestamp"`
+	// InputHash is the SHA-256 of the SoT records and all satellite records, in a
+	// canonical order that does not depend on file processing order.
+	InputHash string `json:"inputHash"`
+	// JoinHash is the SHA-256 of the join results in the same canonical order: each
+	// account's match type, SoT person, classification, conflicts, and evidence. It
+	// covers the configuration applied before the merge (override table, match rules,
+	// classifier, and suggestion limit), which only reaches the report through them.
+	JoinHash string `json:"joinHash"`
+	// ConfigHash is the SHA-256 of the scoring configuration.
+	ConfigHash string `json:"configHash"`
+	// RiskConfig is the effective risk configuration with defaults filled in,
+	// including the privilege catalog whose rules assigned the privilege tiers.
+	RiskConfig engine.RiskConfig `json:"riskConfig"`
+}
+
+// scoringConfig is the configuration covered by ConfigHash.
+type scoringConfig struct {
+	Risk           engine.RiskConfig      `json:"risk"`
+	RiskPolicy     *engine.RiskPolicy     `json:"riskPolicy"`
+	SoDMatrix      *engine.SoDMatrix      `json:"sodMatrix"`
+	ConflictChecks []engine.ConflictCheck `json:"conflictChecks"`
+}
+
+// newReportMetadata hashes the inputs and configuration of a merge. opts must already
+// have its defaults filled in, and it must run before join results are modified by
+// transitive linking.
+func newReportMetadata(sotIndex *engine.SoTIndex, joinResults []*engine.JoinResult, opts MergeOptions) (ReportMetadata, error) {
+	inputHash, err := hashInputs(sotIndex, joinResults)
+	if err != nil {
+		return ReportMetadata{}, err
+	}
+	joinHash, err := hashJSON(canonicalJoinResults(joinResults))
+	if err != nil {
+		return ReportMetadata{}, fmt.Errorf("hashing join results: %w", err)
+	}
+	configHash, err := hashJSON(scoringConfig{
+		Risk:           opts.Risk,
+		RiskPolicy:     opts.RiskPolicy,
+		SoDMatrix:      opts.SoDMatrix,
+		ConflictChecks: opts.ConflictChecks,
+	})
+	if err != nil {
+		return ReportMetadata{}, fmt.Errorf("hashing scoring configuration: %w", err)
+	}
+
+	return ReportMetadata{
+		ProcessingTimestamp: opts.Risk.ProcessingTimestamp,
+		InputHash:           inputHash,
+		JoinHash:            joinHash,
+		ConfigHash:          configHash,
+		RiskConfig:          opts.Risk,
+	}, nil
+}
+
+// hashInputs hashes the SoT records in input order followed by every satellite record
+// sorted by source file and row.
+func hashInputs(sotIndex *engine.SoTIndex, joinResults []*engine.JoinResult) (string, error) {
+	var satellites []schema.SatelliteRecord
+	for _, jr := range joinResults {
+		for _, m := range jr.Matched {
+			satellites = append(satellites, m.Satellite)
+		}
+		for _, o := range jr.Orphans {
+			satellites = append(satellites, o.Satellite)
+		}
+		for _, nh := range jr.NonHuman {
+			satellites = append(satellites, nh.Satellite)
+		}
+	}
+	sort.SliceStable(satellites, func(i, j int) bool {
+		return satelliteBefore(satellites[i], satellites[j])
+	})
+
+	hash, err := hashJSON(struct {
+		SoT        []*schema.SoTRecord      `json:"sot"`
+		Satellites []schema.SatelliteRecord `json:"satellites"`
+	}{sotIndex.Records, satellites})
+	if err != nil {
+		return "", fmt.Errorf("hashing inputs: %w", err)
+	}
+	return hash, nil
+}
+
+// canonicalJoinResults returns copies of the join results sorted by system, with each
+// result's records sorted by source file and row. The originals are not modified.
+func canonicalJoinResults(joinResults []*engine.JoinResult) []*engine.JoinResult {
+	sorted := make([]*engine.JoinResult, len(joinResults))
+	for i, jr := range joinResults {
+		c := *jr
+		c.Matched = append([]engine.MatchedRecord(nil), jr.Matched...)
+		sort.SliceStable(c.Matched, func(a, b int) bool {
+			return satelliteBefore(c.Matched[a].Satellite, c.Matched[b].Satellite)
+		})
+		c.Orphans = sortedOrphans(jr.Orphans)
+		c.NonHuman = sortedOrphans(jr.NonHuman)
+		sorted[i] = &c
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].System < sorted[j].System
+	})
+	return sorted
+}
+
+// sortedOrphans returns a copy of records sorted by source file and row.
+func sortedOrphans(records []engine.OrphanRecord) []engine.OrphanRecord {
+	sorted := append([]engine.OrphanRecord(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return satelliteBefore(sorted[i].Satellite, sorted[j].Satellite)
+	})
+	return sorted
+}
+
+// satelliteBefore orders satellite records by source file, then row.
+func satelliteBefore(a, b schema.SatelliteRecord) bool {
+	if a.SourceFile != b.SourceFile {
+		return a.SourceFile < b.SourceFile
+	}
+	return a.SourceRow < b.SourceRow
+}
+
+// hashJSON returns the hex SHA-256 of v's JSON encoding.
+func hashJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"math"
+	"testing"
+
+	"uar/pkg/engine"
+	"uar/pkg/schema"
+)
+
+func TestReportMetadataHashes(t *testing.T) {
+	jane := &schema.SoTRecord{CanonicalID: "jane@acme.com", EmployeeID: "E1", DisplayName: "Jane Doe", NormalizedName: "jane doe", Email: "jane@acme.com"}
+	sam := &schema.SoTRecord{CanonicalID: "sam@acme.com", EmployeeID: "E2", DisplayName: "Sam Ortiz", NormalizedName: "sam ortiz", Email: "sam@acme.com"}
+	index := engine.BuildSoTIndex([]*schema.SoTRecord{jane, sam})
+
+	okta := []schema.SatelliteRecord{
+		{Email: "jane@acme.com", DisplayName: "Jane Doe", SourceFile: "okta", SourceRow: 1},
+		{Email: "sam@acme.com", DisplayName: "Sam Ortiz", SourceFile: "okta", SourceRow: 2},
+		{Email: "ghost@acme.com", DisplayName: "Ghost User", SourceFile: "okta", SourceRow: 3},
+	}
+	github := []schema.SatelliteRecord{
+		{UserId: "E2", DisplayName: "Sam Ortiz", SourceFile: "github", SourceRow: 1},
+		{Email: "svc-deploy@acme.com", DisplayName: "svc-deploy", SourceFile: "github", SourceRow: 2},
+	}
+	reversed := func(records []schema.SatelliteRecord) []schema.SatelliteRecord {
+		out := make([]schema.SatelliteRecord, 0, len(records))
+		for i := len(records) - 1; i >= 0; i-- {
+			out = append(out, records[i])
+		}
+		return out
+	}
+	ghostOverride := engine.JoinOptions{Overrides: engine.NewOverrideTable([]engine.LinkOverride{
+		{System: "okta", SatelliteKey: "ghost@acme.com", Kind: engine.OverridePerson, CanonicalID: "jane@acme.com", ConfirmedBy: "r@acme.com", ConfirmedAt: "2026-01-02T00:00:00Z"},
+	})}
+
+	merge := func(files [][]schema.SatelliteRecord, opts engine.JoinOptions) ReportMetadata {
+		t.Helper()
+		var results []*engine.JoinResult
+		for _, records := range files {
+			results = append(results, engine.JoinAgainstSoT(index, records, records[0].SourceFile, opts))
+		}
+		report, err := MergeResults(index, results, 1770422400000, MergeOptions{})
+		if err != nil {
+			t.Fatalf("MergeResults() error = %v", err)
+		}
+		return report.Metadata
+	}
+
+	base := merge([][]schema.SatelliteRecord{okta, github}, engine.JoinOptions{})
+	if base.InputHash == "" || base.JoinHash == "" || base.ConfigHash == "" {
+		t.Fatalf("metadata = %+v, want every hash set", base)
+	}
+
+	shuffled := merge([][]schema.SatelliteRecord{reversed(github), reversed(okta)}, engine.JoinOptions{})
+	if shuffled.InputHash != base.InputHash || shuffled.JoinHash != base.JoinHash || shuffled.ConfigHash != base.ConfigHash {
+		t.Errorf("shuffled inputs hashed to %+v, want the same hashes as %+v", shuffled, base)
+	}
+
+	overridden := merge([][]schema.SatelliteRecord{okta, github}, ghostOverride)
+	if overridden.InputHash != base.InputHash {
+		t.Errorf("an override changed InputHash; want only JoinHash to change")
+	}
+	if overridden.JoinHash == base.JoinHash {
+		t.Errorf("an override left JoinHash unchanged; want join-time configuration covered")
+	}
+}
+
+func TestHashJSONError(t *testing.T) {
+	if _, err := hashJSON(math.NaN()); err == nil {
+		t.Errorf("hashJSON(NaN) error = nil, want the marshal error")
+	}
+}
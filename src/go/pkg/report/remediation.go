@@ -0,0 +1,204 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"uar/pkg/engine"
+)
+
+// RemediationStatus is the outcome of a revoke decision.
+type RemediationStatus string
+
+const (
+	// RemediationRemoved is revoked access that is gone or disabled.
+	RemediationRemoved RemediationStatus = "revoked_removed"
+	// RemediationStillPresent is revoked access that is still active.
+	RemediationStillPresent RemediationStatus = "revoked_still_present"
+	// RemediationRegranted is revoked access that was verified removed and is back.
+	RemediationRegranted RemediationStatus = "revoked_regranted"
+)
+
+// Remediation finding codes.
+const (
+	FindingRevocationOverdue = "revocation_overdue"
+	FindingAccessRegranted   = "revoked_access_regranted"
+)
+
+// Remediation finding scores. Re-granted access went through deprovisioning and came
+// back, so the revocation is being actively undone.
+const (
+	revocationOverdueScore = 75
+	accessRegrantedScore   = 90
+)
+
+// RemediationReport verifies the previous cycle's revoke decisions against the
+// current exports.
+type RemediationReport struct {
+	ProcessingTimestamp int64              `json:"processingTimestamp"`
+	Items               []RemediationItem  `json:"items"`
+	Summary             RemediationSummary `json:"summary"`
+}
+
+// RemediationItem is the outcome of one revoke decision, or of one revoked role in
+// a decision's role actions.
+type RemediationItem struct {
+	Status      RemediationStatus `json:"status"`
+	Key         AccessKey         `json:"key"`
+	DisplayName string            `json:"displayName"`
+	// Role is the revoked role for a role action; empty means the whole account.
+	Role string `json:"role,omitempty"`
+	// Origin links to the review cycle and item the decision was made on.
+	Origin   DecisionOrigin     `json:"origin"`
+	Decision ReviewDecision     `json:"decision"`
+	Previous *MasterReportEntry `json:"previous"`
+	Current  *MasterReportEntry `json:"current,omitempty"`
+	// AgeDays is how long ago the decision was made, for access still present.
+	AgeDays int                 `json:"ageDays,omitempty"`
+	Finding *engine.RiskFinding `json:"finding,omitempty"`
+}
+
+// RemediationSummary counts remediation items by status.
+type RemediationSummary struct {
+	Removed      int `json:"removed"`
+	StillPresent int `json:"stillPresent"`
+	Regranted    int `json:"regranted"`
+}
+
+// VerifyRemediation checks every revoke decision in the previous report's campaign,
+// including revoked role actions, against the current report. Accounts are paired as
+// in Diff. Revoked access is removed when its account is gone or no longer active,
+// or for a role action when the account no longer holds the role.
+//
+// prior is an earlier verification of the same decisions, or nil. Access it found
+// removed that is present again is re-granted; otherwise present access is still
+// present and overdue by the days since the decision (or since the previous cycle,
+// for decisions without a time). Items are in Diff entry order.
+func VerifyRemediation(previous, current *MasterReport, prior *RemediationReport) *RemediationReport {
+	rr := &RemediationReport{
+		ProcessingTimestamp: current.Metadata.ProcessingTimestamp,
+		Items:               make([]RemediationItem, 0),
+	}
+
+	removedBefore := make(map[string]bool)
+	if prior != nil {
+		for _, item := range prior.Items {
+			if item.Status != RemediationStillPresent {
+				removedBefore[item.remediationID()] = true
+			}
+		}
+	}
+
+	var campaignName string
+	var cycleTimestamp int64
+	if previous.Campaign != nil {
+		campaignName = previous.Campaign.Name
+		cycleTimestamp = previous.Campaign.ProcessingTimestamp
+	}
+
+	previousIDs := entryItemIDs(previous)
+	for _, d := range Diff(previous, current).Entries {
+		if d.Decision == nil || d.Previous == nil {
+			continue
+		}
+
+		var roles []string
+		if d.Decision.Action == DecisionRevoke {
+			roles = []string{""}
+		} else {
+			for _, ra := range d.Decision.RoleActions {
+				if ra.Action == DecisionRevoke {
+					roles = append(roles, ra.Role)
+				}
+			}
+		}
+
+		origin := DecisionOrigin{
+			Campaign:            campaignName,
+			ProcessingTimestamp: cycleTimestamp,
+			ItemID:              previousIDs[d.Previous],
+		}
+		if d.Decision.Origin != nil {
+			origin = *d.Decision.Origin
+		}
+
+		for _, role := range roles {
+			item := RemediationItem{
+				Key:         d.Key,
+				DisplayName: d.DisplayName,
+				Role:        role,
+				Origin:      origin,
+				Decision:    *d.Decision,
+				Previous:    d.Previous,
+				Current:     d.Current,
+			}
+
+			if !stillHolds(d.Current, role) {
+				item.Status = RemediationRemoved
+				rr.Summary.Removed++
+				rr.Items = append(rr.Items, item)
+				continue
+			}
+
+			decidedAt := d.Decision.DecidedAt
+			if decidedAt == 0 {
+				decidedAt = origin.ProcessingTimestamp
+			}
+			item.AgeDays = max(0, int(time.UnixMilli(rr.ProcessingTimestamp).Sub(time.UnixMilli(decidedAt)).Hours()/24))
+
+			what := "access"
+			if role != "" {
+				what = fmt.Sprintf("role %q", role)
+			}
+			if removedBefore[item.remediationID()] {
+				item.Status = RemediationRegranted
+				item.Finding = &engine.RiskFinding{
+					Code:        FindingAccessRegranted,
+					Level:       engine.RiskCritical,
+					Score:       accessRegrantedScore,
+					Description: fmt.Sprintf("%s in %s was revoked and removed, and has been granted again", what, d.Key.System),
+					GapDays:     item.AgeDays,
+				}
+				rr.Summary.Regranted++
+			} else {
+				item.Status = RemediationStillPresent
+				item.Finding = &engine.RiskFinding{
+					Code:        FindingRevocationOverdue,
+					Level:       engine.RiskHigh,
+					Score:       revocationOverdueScore,
+					Description: fmt.Sprintf("%s in %s is still active %d days after it was revoked", what, d.Key.System, item.AgeDays),
+					GapDays:     item.AgeDays,
+				}
+				rr.Summary.StillPresent++
+			}
+			rr.Items = append(rr.Items, item)
+		}
+	}
+
+	return rr
+}
+
+// stillHolds reports whether a current account is active and, for a role action,
+// still holds the role.
+func stillHolds(cur *MasterReportEntry, role string) bool {
+	if cur == nil || !engine.IsCurrentStatus(cur.AccountStatus) {
+		return false
+	}
+	if role == "" {
+		return true
+	}
+	for _, value := range []string{cur.Role, cur.Entitlement} {
+		for _, g := range engine.SplitGrants(value) {
+			if strings.EqualFold(g, strings.TrimSpace(role)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// remediationID identifies an item's decision and role across verifications.
+func (item RemediationItem) remediationID() string {
+	return fmt.Sprintf("%s\x00%d\x00%s\x00%s", item.Origin.Campaign, item.Origin.ProcessingTimestamp, item.Origin.ItemID, strings.ToLower(item.Role))
+}
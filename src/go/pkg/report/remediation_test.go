@@ -0,0 +1,127 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyRemediation(t *testing.T) {
+	day := int64(24 * time.Hour / time.Millisecond)
+	decidedAt := 100 * day
+	now := decidedAt + 10*day
+
+	revoke := map[string]ReviewDecision{janeOkta: {Action: DecisionRevoke, DecidedAt: decidedAt}}
+	revokeAdmin := map[string]ReviewDecision{janeOkta: {
+		Action:      DecisionApprove,
+		DecidedAt:   decidedAt,
+		RoleActions: []RoleAction{{Role: "admin", Action: DecisionRevoke}, {Role: "user", Action: DecisionApprove}},
+	}}
+	disabled := testEntry("jane", "okta", 1, "user")
+	disabled.AccountStatus = "disabled"
+
+	tests := []struct {
+		name        string
+		previous    []MasterReportEntry
+		current     []MasterReportEntry
+		decisions   map[string]ReviewDecision
+		prior       bool // verify once with the account gone first
+		wantStatus  []RemediationStatus
+		wantRole    string
+		wantFinding string
+		wantAge     int
+	}{
+		{
+			name:       "account gone",
+			previous:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:  revoke,
+			wantStatus: []RemediationStatus{RemediationRemoved},
+		},
+		{
+			name:       "account disabled",
+			previous:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:    []MasterReportEntry{disabled},
+			decisions:  revoke,
+			wantStatus: []RemediationStatus{RemediationRemoved},
+		},
+		{
+			name:        "account still active",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:   revoke,
+			wantStatus:  []RemediationStatus{RemediationStillPresent},
+			wantFinding: FindingRevocationOverdue,
+			wantAge:     10,
+		},
+		{
+			name:        "revoked role still held",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user, admin")},
+			current:     []MasterReportEntry{testEntry("jane", "okta", 1, "User; Admin")},
+			decisions:   revokeAdmin,
+			wantStatus:  []RemediationStatus{RemediationStillPresent},
+			wantRole:    "admin",
+			wantFinding: FindingRevocationOverdue,
+			wantAge:     10,
+		},
+		{
+			name:       "revoked role removed",
+			previous:   []MasterReportEntry{testEntry("jane", "okta", 1, "user, admin")},
+			current:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:  revokeAdmin,
+			wantStatus: []RemediationStatus{RemediationRemoved},
+			wantRole:   "admin",
+		},
+		{
+			name:        "removed access granted again",
+			previous:    []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:     []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions:   revoke,
+			prior:       true,
+			wantStatus:  []RemediationStatus{RemediationRegranted},
+			wantFinding: FindingAccessRegranted,
+			wantAge:     10,
+		},
+		{
+			name:      "approvals are not verified",
+			previous:  []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			current:   []MasterReportEntry{testEntry("jane", "okta", 1, "user")},
+			decisions: map[string]ReviewDecision{janeOkta: {Action: DecisionApprove, DecidedAt: decidedAt}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := testReport(decidedAt, tt.previous, tt.decisions)
+			var prior *RemediationReport
+			if tt.prior {
+				prior = VerifyRemediation(previous, testReport(now-day, nil, nil), nil)
+			}
+			rr := VerifyRemediation(previous, testReport(now, tt.current, nil), prior)
+
+			if len(rr.Items) != len(tt.wantStatus) {
+				t.Fatalf("got %d items %+v, want %v", len(rr.Items), rr.Items, tt.wantStatus)
+			}
+			if len(rr.Items) == 0 {
+				return
+			}
+			item := rr.Items[0]
+			if item.Status != tt.wantStatus[0] || item.Role != tt.wantRole {
+				t.Errorf("item = %s role %q, want %s role %q", item.Status, item.Role, tt.wantStatus[0], tt.wantRole)
+			}
+			if item.Origin.ItemID != janeOkta || item.Origin.Campaign != "Q1" {
+				t.Errorf("origin = %+v, want Q1 %s", item.Origin, janeOkta)
+			}
+
+			var code string
+			if item.Finding != nil {
+				code = item.Finding.Code
+			}
+			if code != tt.wantFinding || item.AgeDays != tt.wantAge {
+				t.Errorf("finding %q age %d, want %q age %d", code, item.AgeDays, tt.wantFinding, tt.wantAge)
+			}
+
+			s := rr.Summary
+			if s.Removed+s.StillPresent+s.Regranted != len(rr.Items) {
+				t.Errorf("summary %+v does not count %d items", s, len(rr.Items))
+			}
+		})
+	}
+}
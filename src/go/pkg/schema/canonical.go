@@ -9,8 +9,15 @@ type SoTRecord struct {
 	Email            string `json:"email"`
 	Department       string `json:"department"`
 	Manager          string `json:"manager"`
+	Title            string `json:"title"`
 	EmploymentStatus string `json:"employmentStatus"`
-	AdminInfo        string `json:"adminInfo"`
+	// LifecycleState is EmploymentStatus mapped to a lifecycle state; empty when
+	// the status is not recognized.
+	LifecycleState  LifecycleState `json:"lifecycleState"`
+	StartDate       string         `json:"startDate"`
+	TerminationDate string         `json:"terminationDate"`
+	ContractEndDate string         `json:"contractEndDate"`
+	AdminInfo       string         `json:"adminInfo"`
 }
 
 // SatelliteRecord represents a record from a satellite system (e.g., Okta, AWS, SAP).
@@ -18,10 +25,16 @@ type SatelliteRecord struct {
 	Email         string `json:"email"`
 	UserId        string `json:"userId"`
 	DisplayName   string `json:"displayName"`
+	Department    string `json:"department"`
+	Manager       string `json:"manager"`
+	Title         string `json:"title"`
 	Role          string `json:"role"`
 	Entitlement   string `json:"entitlement"`
 	LastLogin     string `json:"lastLogin"`
 	AccountStatus string `json:"accountStatus"`
+	AccountType   string `json:"accountType"`
+	DisabledDate  string `json:"disabledDate"`
+	CreatedDate   string `json:"createdDate"`
 	SourceFile    string `json:"sourceFile"`
 	SourceRow     int    `json:"sourceRow"`
 }
@@ -0,0 +1,61 @@
+package schema
+
+import "strings"
+
+// LifecycleState is where a person is in the employment lifecycle.
+type LifecycleState string
+
+const (
+	LifecyclePreHire    LifecycleState = "pre_hire"
+	LifecycleActive     LifecycleState = "active"
+	LifecycleLeave      LifecycleState = "leave"
+	LifecycleSuspended  LifecycleState = "suspended"
+	LifecycleTerminated LifecycleState = "terminated"
+)
+
+// lifecycleStatuses maps normalized HR employment status values to lifecycle states.
+// Worker types such as "contractor" are active; their contract end date is checked
+// separately.
+var lifecycleStatuses = map[string]LifecycleState{
+	"pre hire":             LifecyclePreHire,
+	"prehire":              LifecyclePreHire,
+	"pending":              LifecyclePreHire,
+	"pending start":        LifecyclePreHire,
+	"pending hire":         LifecyclePreHire,
+	"new hire":             LifecyclePreHire,
+	"onboarding":           LifecyclePreHire,
+	"offer accepted":       LifecyclePreHire,
+	"active":               LifecycleActive,
+	"employed":             LifecycleActive,
+	"current":              LifecycleActive,
+	"contractor":           LifecycleActive,
+	"contract":             LifecycleActive,
+	"temp":                 LifecycleActive,
+	"intern":               LifecycleActive,
+	"leave":                LifecycleLeave,
+	"on leave":             LifecycleLeave,
+	"loa":                  LifecycleLeave,
+	"leave of absence":     LifecycleLeave,
+	"parental leave":       LifecycleLeave,
+	"maternity leave":      LifecycleLeave,
+	"medical leave":        LifecycleLeave,
+	"sabbatical":           LifecycleLeave,
+	"suspended":            LifecycleSuspended,
+	"suspension":           LifecycleSuspended,
+	"administrative leave": LifecycleSuspended,
+	"terminated":           LifecycleTerminated,
+	"termed":               LifecycleTerminated,
+	"separated":            LifecycleTerminated,
+	"former":               LifecycleTerminated,
+	"resigned":             LifecycleTerminated,
+	"retired":              LifecycleTerminated,
+	"deceased":             LifecycleTerminated,
+}
+
+// LifecycleStateOf maps an employment status value to a lifecycle state, ignoring
+// case, hyphens, and underscores. Unknown and empty values give "".
+func LifecycleStateOf(status string) LifecycleState {
+	s := strings.ToLower(status)
+	s = strings.NewReplacer("-", " ", "_", " ").Replace(s)
+	return lifecycleStatuses[strings.Join(strings.Fields(s), " ")]
+}
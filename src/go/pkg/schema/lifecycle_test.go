@@ -0,0 +1,31 @@
+package schema
+
+import "testing"
+
+func TestLifecycleStateOf(t *testing.T) {
+	tests := []struct {
+		status string
+		want   LifecycleState
+	}{
+		{"Active", LifecycleActive},
+		{"  ACTIVE  ", LifecycleActive},
+		{"Contractor", LifecycleActive},
+		{"Pre-Hire", LifecyclePreHire},
+		{"pending_start", LifecyclePreHire},
+		{"Leave of  Absence", LifecycleLeave},
+		{"LOA", LifecycleLeave},
+		{"Administrative Leave", LifecycleSuspended},
+		{"Suspended", LifecycleSuspended},
+		{"Terminated", LifecycleTerminated},
+		{"retired", LifecycleTerminated},
+		{"", ""},
+		{"furloughed", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := LifecycleStateOf(tt.status); got != tt.want {
+				t.Errorf("LifecycleStateOf(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
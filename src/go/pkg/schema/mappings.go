@@ -53,6 +53,13 @@ var HeaderMappings = map[string]string{
 	"supervisor":   "manager",
 	"reportsto":    "manager",
 
+	// Title
+	"title":       "title",
+	"jobtitle":    "title",
+	"job_title":   "title",
+	"position":    "title",
+	"designation": "title",
+
 	// Status
 	"status":           "accountStatus",
 	"accountstatus":    "accountStatus",
@@ -60,6 +67,52 @@ var HeaderMappings = map[string]string{
 	"active":           "accountStatus",
 	"employmentstatus": "employmentStatus",
 	"empstatus":        "employmentStatus",
+	"workerstatus":     "employmentStatus",
+	"lifecyclestatus":  "employmentStatus",
+	"lifecyclestate":   "employmentStatus",
+
+	// Termination / Disable Dates
+	"terminationdate":   "terminationDate",
+	"termination_date":  "terminationDate",
+	"termdate":          "terminationDate",
+	"term_date":         "terminationDate",
+	"separationdate":    "terminationDate",
+	"lastworkingday":    "terminationDate",
+	"startdate":         "startDate",
+	"start_date":        "startDate",
+	"hiredate":          "startDate",
+	"hire_date":         "startDate",
+	"dateofhire":        "startDate",
+	"joindate":          "startDate",
+	"joiningdate":       "startDate",
+	"contractenddate":   "contractEndDate",
+	"contract_end_date": "contractEndDate",
+	"contractend":       "contractEndDate",
+	"contractexpiry":    "contractEndDate",
+	"contractexpiration": "contractEndDate",
+	"engagementenddate": "contractEndDate",
+	"disableddate":      "disabledDate",
+	"disabled_date":     "disabledDate",
+	"disabledon":        "disabledDate",
+	"deactivateddate":   "disabledDate",
+	"deactivationdate":  "disabledDate",
+	"deprovisioneddate": "disabledDate",
+	"createddate":       "createdDate",
+	"created_date":      "createdDate",
+	"createdat":         "createdDate",
+	"created_at":        "createdDate",
+	"createdon":         "createdDate",
+	"creationdate":      "createdDate",
+	"datecreated":       "createdDate",
+	"whencreated":       "createdDate",
+
+	// Account Type
+	"accounttype":   "accountType",
+	"account_type":  "accountType",
+	"usertype":      "accountType",
+	"user_type":     "accountType",
+	"principaltype": "accountType",
+	"identitytype":  "accountType",
 
 	// Role / Entitlement
 	"role":        "role",
@@ -107,9 +160,31 @@ var substringMappings = []struct {
 	{"manager", "manager"},
 	{"supervisor", "manager"},
 	{"reportsto", "manager"},
+	{"jobtitle", "title"},
+	{"position", "title"},
+	{"terminationdate", "terminationDate"},
+	{"termdate", "terminationDate"},
+	{"separationdate", "terminationDate"},
+	{"startdate", "startDate"},
+	{"hiredate", "startDate"},
+	{"contractend", "contractEndDate"},
+	{"contractexpir", "contractEndDate"},
+	{"disableddate", "disabledDate"},
+	{"deactivat", "disabledDate"},
+	{"deprovision", "disabledDate"},
+	{"createddate", "createdDate"},
+	{"createdat", "createdDate"},
+	{"createdon", "createdDate"},
+	{"creationdate", "createdDate"},
+	{"whencreated", "createdDate"},
 	{"employmentstatus", "employmentStatus"},
 	{"empstatus", "employmentStatus"},
+	{"workerstatus", "employmentStatus"},
+	{"lifecycle", "employmentStatus"},
 	{"accountstatus", "accountStatus"},
+	{"accounttype", "accountType"},
+	{"usertype", "accountType"},
+	{"principaltype", "accountType"},
 	{"status", "accountStatus"},
 	{"enabled", "accountStatus"},
 	{"entitlement", "entitlement"},
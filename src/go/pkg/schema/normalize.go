@@ -127,12 +127,29 @@ func NormalizeSoT(records []map[string]string, columnMapJSON string) []*SoTRecor
 			Email:            email,
 			Department:       strings.TrimSpace(mapped["department"]),
 			Manager:          strings.TrimSpace(mapped["manager"]),
+			Title:            strings.TrimSpace(mapped["title"]),
 			EmploymentStatus: strings.TrimSpace(strings.ToLower(mapped["employmentStatus"])),
+			LifecycleState:   LifecycleStateOf(mapped["employmentStatus"]),
+			StartDate:        strings.TrimSpace(mapped["startDate"]),
+			TerminationDate:  strings.TrimSpace(mapped["terminationDate"]),
+			ContractEndDate:  strings.TrimSpace(mapped["contractEndDate"]),
 			AdminInfo:        collectAdminValues(record),
 		}
 		result = append(result, sotRecord)
 	}
 
+	startDates := make([]*string, len(result))
+	terminationDates := make([]*string, len(result))
+	contractEndDates := make([]*string, len(result))
+	for i := range result {
+		startDates[i] = &result[i].StartDate
+		terminationDates[i] = &result[i].TerminationDate
+		contractEndDates[i] = &result[i].ContractEndDate
+	}
+	normalizeTimestampColumn(startDates)
+	normalizeTimestampColumn(terminationDates)
+	normalizeTimestampColumn(contractEndDates)
+
 	return result
 }
 
@@ -157,16 +174,36 @@ func NormalizeSatellite(records []map[string]string, systemName string, columnMa
 			Email:         strings.TrimSpace(strings.ToLower(mapped["email"])),
 			UserId:        strings.TrimSpace(mapped["userId"]),
 			DisplayName:   strings.TrimSpace(mapped["displayName"]),
+			Department:    strings.TrimSpace(mapped["department"]),
+			Manager:       strings.TrimSpace(mapped["manager"]),
+			Title:         strings.TrimSpace(mapped["title"]),
 			Role:          role,
 			Entitlement:   strings.TrimSpace(mapped["entitlement"]),
 			LastLogin:     strings.TrimSpace(mapped["lastLogin"]),
 			AccountStatus: strings.TrimSpace(strings.ToLower(mapped["accountStatus"])),
+			AccountType:   strings.TrimSpace(strings.ToLower(mapped["accountType"])),
+			DisabledDate:  strings.TrimSpace(mapped["disabledDate"]),
+			CreatedDate:   strings.TrimSpace(mapped["createdDate"]),
 			SourceFile:    systemName,
 			SourceRow:     i + 1, // 1-indexed
 		}
 		result = append(result, sat)
 	}
 
+	// Normalize each date column as a whole so dd/mm vs mm/dd is inferred per column
+	columns := []func(*SatelliteRecord) *string{
+		func(r *SatelliteRecord) *string { return &r.LastLogin },
+		func(r *SatelliteRecord) *string { return &r.DisabledDate },
+		func(r *SatelliteRecord) *string { return &r.CreatedDate },
+	}
+	for _, column := range columns {
+		values := make([]*string, len(result))
+		for i := range result {
+			values[i] = column(&result[i])
+		}
+		normalizeTimestampColumn(values)
+	}
+
 	return result
 }
 
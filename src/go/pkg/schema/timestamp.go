@@ -0,0 +1,251 @@
+package schema
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateOrder is how an ambiguous numeric date such as 03/04/2026 is read.
+type DateOrder string
+
+const (
+	DateOrderMDY DateOrder = "mdy" // 03/04/2026 is March 4
+	DateOrderDMY DateOrder = "dmy" // 03/04/2026 is 3 April
+)
+
+// TimestampKind classifies a raw timestamp value.
+type TimestampKind string
+
+const (
+	TimestampEmpty   TimestampKind = "empty"
+	TimestampValid   TimestampKind = "valid"
+	TimestampNever   TimestampKind = "never" // an explicit "never logged in" marker
+	TimestampInvalid TimestampKind = "invalid"
+)
+
+// textLayouts are tried in order for values that are not numeric dates or epochs.
+var textLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"02-Jan-2006",
+	"2-Jan-2006",
+	"02 Jan 2006",
+	"Mon Jan 2 15:04:05 2006",
+	"Mon, 02 Jan 2006 15:04:05 MST",
+	"20060102",
+}
+
+// numericDateRe matches day/month/year dates with /, - or . separators and an
+// optional time of day.
+var numericDateRe = regexp.MustCompile(`^(\d{1,2})[/.-](\d{1,2})[/.-](\d{4})(?:[ T](\d{1,2}):(\d{2})(?::(\d{2}))?)?$`)
+
+// epochRe matches integer or fractional epoch values.
+var epochRe = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// neverValues are the markers systems export for accounts that never logged in.
+var neverValues = map[string]bool{
+	"never":           true,
+	"never logged in": true,
+	"never used":      true,
+	"none":            true,
+	"-":               true,
+	"0":               true,
+	// AD lastLogonTimestamp for "never": 0 and the largest FILETIME value
+	"9223372036854775807": true,
+	"1601-01-01":          true,
+}
+
+// Epoch magnitude ranges used to tell the units of an all-digit value apart.
+// Seconds cover 1973-5138, milliseconds 1973-5138, and FILETIME ticks 1633-2262.
+const (
+	epochSecondsMin  = 1e8
+	epochMillisMin   = 1e11
+	epochMillisMax   = 1e14
+	fileTimeMin      = 1e16
+	fileTimeUnixDiff = 116444736000000000 // FILETIME ticks between 1601-01-01 and 1970-01-01
+	// fileTimeMax is the last FILETIME whose Unix nanoseconds fit in an int64.
+	fileTimeMax = fileTimeUnixDiff + math.MaxInt64/100
+)
+
+// InferDateOrder decides whether a column's numeric dates are month-first or
+// day-first by looking at every value: a first part above 12 means day-first, a
+// second part above 12 means month-first. When the column gives no evidence, or
+// contradicts itself, month-first is assumed.
+func InferDateOrder(values []string) DateOrder {
+	dayFirst, monthFirst := false, false
+	for _, v := range values {
+		m := numericDateRe.FindStringSubmatch(strings.TrimSpace(v))
+		if m == nil {
+			continue
+		}
+		a, _ := strconv.Atoi(m[1])
+		b, _ := strconv.Atoi(m[2])
+		if a > 12 {
+			dayFirst = true
+		}
+		if b > 12 {
+			monthFirst = true
+		}
+	}
+	if dayFirst && !monthFirst {
+		return DateOrderDMY
+	}
+	return DateOrderMDY
+}
+
+// ParseTimestamp parses a date or timestamp in any supported form: ISO 8601 and
+// common text layouts, numeric dates in the given order, Unix epoch seconds or
+// milliseconds, and Active Directory FILETIME ticks. Results are in UTC unless the
+// value carries its own offset.
+func ParseTimestamp(value string, order DateOrder) (time.Time, TimestampKind) {
+	return ParseTimestampIn(value, order, time.UTC)
+}
+
+// ParseTimestampIn is ParseTimestamp with dates and times that carry no offset read
+// in loc. Epoch and FILETIME values are absolute and ignore loc.
+func ParseTimestampIn(value string, order DateOrder, loc *time.Location) (time.Time, TimestampKind) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	v := strings.TrimSpace(value)
+	if v == "" {
+		return time.Time{}, TimestampEmpty
+	}
+	if neverValues[strings.ToLower(v)] {
+		return time.Time{}, TimestampNever
+	}
+
+	for _, layout := range textLayouts {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return t, TimestampValid
+		}
+	}
+
+	if m := numericDateRe.FindStringSubmatch(v); m != nil {
+		if t, ok := numericDate(m, order, loc); ok {
+			return t, TimestampValid
+		}
+		return time.Time{}, TimestampInvalid
+	}
+
+	if epochRe.MatchString(v) {
+		if t, ok := epochTime(v); ok {
+			return t, TimestampValid
+		}
+	}
+
+	return time.Time{}, TimestampInvalid
+}
+
+// NormalizeTimestamp rewrites numeric dates, epochs, and FILETIME values as ISO 8601
+// so later stages never guess the format again. Numeric dates become YYYY-MM-DD, or
+// YYYY-MM-DDTHH:MM:SS without an offset so the scoring time zone still applies;
+// epochs become RFC 3339 in UTC. "Never" markers become empty. Values already in a text layout, and
+// values that cannot be parsed, are returned unchanged.
+func NormalizeTimestamp(value string, order DateOrder) string {
+	v := strings.TrimSpace(value)
+	t, kind := ParseTimestamp(v, order)
+	switch kind {
+	case TimestampNever:
+		return ""
+	case TimestampValid:
+	default:
+		return v
+	}
+
+	for _, layout := range textLayouts {
+		if _, err := time.Parse(layout, v); err == nil {
+			return v
+		}
+	}
+
+	if m := numericDateRe.FindStringSubmatch(v); m != nil {
+		if m[4] == "" {
+			return t.Format("2006-01-02")
+		}
+		return t.Format("2006-01-02T15:04:05")
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// normalizeTimestampColumn infers the date order of one column and normalizes
+// every value in it.
+func normalizeTimestampColumn(values []*string) {
+	raw := make([]string, len(values))
+	for i, v := range values {
+		raw[i] = *v
+	}
+	order := InferDateOrder(raw)
+	for _, v := range values {
+		*v = NormalizeTimestamp(*v, order)
+	}
+}
+
+// numericDate builds a date from a numericDateRe match.
+func numericDate(m []string, order DateOrder, loc *time.Location) (time.Time, bool) {
+	first, _ := strconv.Atoi(m[1])
+	second, _ := strconv.Atoi(m[2])
+	year, _ := strconv.Atoi(m[3])
+
+	month, day := first, second
+	if order == DateOrderDMY {
+		month, day = second, first
+	}
+	// An unambiguous value overrides the column order, e.g. 25/12 in a mm/dd column
+	if month > 12 && day <= 12 {
+		month, day = day, month
+	}
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+
+	hour, minute, sec := 0, 0, 0
+	if m[4] != "" {
+		hour, _ = strconv.Atoi(m[4])
+		minute, _ = strconv.Atoi(m[5])
+		if m[6] != "" {
+			sec, _ = strconv.Atoi(m[6])
+		}
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, sec, 0, loc)
+	if t.Day() != day {
+		return time.Time{}, false // e.g. 31/02
+	}
+	return t, true
+}
+
+// epochTime reads an all-digit value as Unix seconds, Unix milliseconds, or
+// FILETIME ticks depending on its magnitude.
+func epochTime(v string) (time.Time, bool) {
+	if !strings.Contains(v, ".") {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			switch {
+			case n >= fileTimeMin && n <= fileTimeMax:
+				ticks := n - fileTimeUnixDiff
+				return time.Unix(ticks/1e7, ticks%1e7*100).UTC(), true
+			case n >= epochMillisMin && n < epochMillisMax:
+				return time.UnixMilli(n).UTC(), true
+			case n >= epochSecondsMin && n < epochMillisMin:
+				return time.Unix(n, 0).UTC(), true
+			}
+			return time.Time{}, false
+		}
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < epochSecondsMin || f >= epochMillisMin {
+		return time.Time{}, false
+	}
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC(), true
+}
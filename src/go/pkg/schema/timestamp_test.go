@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	jan1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		order    DateOrder
+		want     time.Time
+		wantKind TimestampKind
+	}{
+		{"empty", "  ", DateOrderMDY, time.Time{}, TimestampEmpty},
+		{"never marker", "Never Logged In", DateOrderMDY, time.Time{}, TimestampNever},
+		{"AD never", "9223372036854775807", DateOrderMDY, time.Time{}, TimestampNever},
+		{"ISO date", "2026-01-01", DateOrderMDY, jan1, TimestampValid},
+		{"RFC 3339 with offset", "2026-01-01T02:00:00+02:00", DateOrderMDY, jan1, TimestampValid},
+		{"text month", "Jan 1, 2026", DateOrderMDY, jan1, TimestampValid},
+		{"month first", "03/04/2026", DateOrderMDY, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), TimestampValid},
+		{"day first", "03/04/2026", DateOrderDMY, time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), TimestampValid},
+		{"unambiguous day overrides order", "25/12/2026", DateOrderMDY, time.Date(2026, 12, 25, 0, 0, 0, 0, time.UTC), TimestampValid},
+		{"numeric date with time", "12.31.2025 23:59", DateOrderMDY, time.Date(2025, 12, 31, 23, 59, 0, 0, time.UTC), TimestampValid},
+		{"impossible day", "02/31/2026", DateOrderMDY, time.Time{}, TimestampInvalid},
+		{"epoch seconds", "1767225600", DateOrderMDY, jan1, TimestampValid},
+		{"epoch milliseconds", "1767225600000", DateOrderMDY, jan1, TimestampValid},
+		{"fractional epoch seconds", "1767225600.5", DateOrderMDY, jan1.Add(500 * time.Millisecond), TimestampValid},
+		{"FILETIME", "134116992000000000", DateOrderMDY, jan1, TimestampValid},
+		{"last representable FILETIME", "208678456368547758", DateOrderMDY, time.Unix(0, 9223372036854775800).UTC(), TimestampValid},
+		{"FILETIME past int64 nanoseconds", "208678456368547759", DateOrderMDY, time.Time{}, TimestampInvalid},
+		{"small number", "12345", DateOrderMDY, time.Time{}, TimestampInvalid},
+		{"text", "last tuesday", DateOrderMDY, time.Time{}, TimestampInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, kind := ParseTimestamp(tt.value, tt.order)
+			if kind != tt.wantKind {
+				t.Fatalf("ParseTimestamp(%q) kind = %s, want %s", tt.value, kind, tt.wantKind)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTimestamp(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestampIn(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{"date without offset uses the zone", "2026-01-01", time.Date(2026, 1, 1, 5, 0, 0, 0, time.UTC)},
+		{"offset wins over the zone", "2026-01-01T00:00:00Z", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"epoch ignores the zone", "1767225600", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, kind := ParseTimestampIn(tt.value, DateOrderMDY, ny)
+			if kind != TimestampValid || !got.Equal(tt.want) {
+				t.Errorf("ParseTimestampIn(%q) = %v, %s, want %v", tt.value, got, kind, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferDateOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   DateOrder
+	}{
+		{"no evidence", []string{"01/02/2026", "2026-03-01", ""}, DateOrderMDY},
+		{"day above 12 first", []string{"01/02/2026", "25/02/2026"}, DateOrderDMY},
+		{"day above 12 second", []string{"02/25/2026"}, DateOrderMDY},
+		{"contradictory", []string{"25/02/2026", "02/25/2026"}, DateOrderMDY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InferDateOrder(tt.values); got != tt.want {
+				t.Errorf("InferDateOrder(%v) = %s, want %s", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTimestamp(t *testing.T) {
+	tests := []struct {
+		value string
+		order DateOrder
+		want  string
+	}{
+		{"03/04/2026", DateOrderDMY, "2026-04-03"},
+		{"03/04/2026 08:15", DateOrderMDY, "2026-03-04T08:15:00"},
+		{"1767225600000", DateOrderMDY, "2026-01-01T00:00:00Z"},
+		{"never", DateOrderMDY, ""},
+		{"Jan 1, 2026", DateOrderMDY, "Jan 1, 2026"},
+		{"garbage", DateOrderMDY, "garbage"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTimestamp(tt.value, tt.order); got != tt.want {
+			t.Errorf("NormalizeTimestamp(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
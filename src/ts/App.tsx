@@ -203,6 +203,9 @@ export default function App() {
                 }
                 reportHook.setReport(state.report);
                 workerPool.restoreCachedSotIndex(state.cachedSotIndex);
+                if (state.overrideTable) {
+                    workerPool.restoreOverrideTable(state.overrideTable);
+                }
                 workerPool.restoreSotStats(state.sotStats);
                 setProcessedFileIds(new Set(state.processedFileIds));
                 setScreen('report');
@@ -217,7 +220,7 @@ export default function App() {
         // eslint-disable-next-line react-hooks/exhaustive-deps
     }, []);
 
-    // Debounced save (1s) when report or SoT index changes
+    // Debounced save (1s) when report, SoT index, or override table changes
     useEffect(() => {
         if (isRestoring) return;
         if (reportHook.report.length === 0) return;
@@ -228,6 +231,7 @@ export default function App() {
             saveAppState({
                 report: reportHook.report,
                 cachedSotIndex: workerPool.cachedSotIndex!,
+                overrideTable: workerPool.overrideTable,
                 processedFileIds: Array.from(processedFileIds),
                 sotStats: workerPool.sotStats!,
             }).catch(() => {
@@ -236,7 +240,7 @@ export default function App() {
         }, 1000);
 
         return () => clearTimeout(saveTimerRef.current);
-    }, [isRestoring, reportHook.report, workerPool.cachedSotIndex, workerPool.sotStats, processedFileIds]);
+    }, [isRestoring, reportHook.report, workerPool.cachedSotIndex, workerPool.overrideTable, workerPool.sotStats, processedFileIds]);
 
     const handleNewSession = useCallback(() => {
         clearAppState().then(() => {
@@ -280,7 +284,7 @@ export default function App() {
                 })),
                 totalSoTUsers: workerPool.sotStats?.totalRecords ?? 0,
                 totalMatched: reportHook.report.filter(
-                    (r) => r.matchType !== 'orphan' && r.matchType !== 'no_access'
+                    (r) => r.matchType !== 'orphan' && r.matchType !== 'non_human' && r.matchType !== 'no_access'
                 ).length,
                 totalOrphans: reportHook.report.filter(
                     (r) => r.matchType === 'orphan'
@@ -468,7 +472,7 @@ export default function App() {
         if (screen === 'report' && incrementalProcessing) {
             setIncrementalProcessing(false);
             if (workerPool.joinResults.size > 0) {
-                const allRecords = mergeJoinResults(workerPool.joinResults, fileQueue.files);
+                const allRecords = mergeJoinResults(workerPool.joinResults, workerPool.masterReport, fileQueue.files);
                 reportHook.appendRecords(allRecords);
                 // Mark all satellite files as processed
                 setProcessedFileIds(new Set(
@@ -481,7 +485,7 @@ export default function App() {
         // Initial processing completed on processing screen
         if (screen === 'processing') {
             if (workerPool.joinResults.size > 0) {
-                const canonicalRecords = mergeJoinResults(workerPool.joinResults, fileQueue.files);
+                const canonicalRecords = mergeJoinResults(workerPool.joinResults, workerPool.masterReport, fileQueue.files);
                 reportHook.setReport(canonicalRecords);
                 setProcessedFileIds(new Set(
                     fileQueue.files.filter((f) => !f.isSoT).map((f) => f.id)
@@ -489,7 +493,7 @@ export default function App() {
                 transitionToScreen('report');
             }
         }
-    }, [screen, workerPool.isProcessing, workerPool.joinResults, fileQueue.files, reportHook, transitionToScreen, incrementalProcessing]);
+    }, [screen, workerPool.isProcessing, workerPool.joinResults, workerPool.masterReport, fileQueue.files, reportHook, transitionToScreen, incrementalProcessing]);
 
     // -----------------------------------------------------------------------
     // Global undo/redo keyboard shortcuts
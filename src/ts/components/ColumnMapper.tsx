@@ -13,8 +13,15 @@ const TARGET_FIELDS = [
     'displayName',
     'department',
     'manager',
+    'title',
     'employmentStatus',
+    'startDate',
+    'terminationDate',
+    'contractEndDate',
     'accountStatus',
+    'accountType',
+    'disabledDate',
+    'createdDate',
     'role',
     'entitlement',
     'lastLogin',
@@ -5,6 +5,8 @@
  *   Phase 1: Spawn a SoT worker, parse the SoT CSV, build the SoT index
  *   Phase 2: Spawn N satellite workers in parallel, broadcast the SoT index
  *            to each, then parse satellite CSVs and join against the index
+ *   Phase 3: Spawn a SoT worker to score all join results into the Go
+ *            master report (uarMergeResults)
  *
  * Handles worker crash detection (via error events and progress timeouts),
  * progress reporting, and abort/cleanup.
@@ -13,7 +15,7 @@
  */
 
 import {useCallback, useRef, useState} from 'react';
-import type {FileEntry, IndexStats, JoinResult} from '../types/schema';
+import type {FileEntry, IndexStats, JoinResult, LinkOverride, MasterReport, OverrideTable} from '../types/schema';
 import type {WorkerInMessage, WorkerOutMessage} from '../types/messages';
 
 // ---------------------------------------------------------------------------
@@ -69,6 +71,12 @@ export interface UseWorkerPoolReturn {
     /** Accumulated join results from all satellite workers, keyed by fileId. */
     joinResults: Map<string, JoinResult>;
 
+    /**
+     * Go master report scored from all of joinResults. Null until the first
+     * merge completes, or if the merge failed.
+     */
+    masterReport: MasterReport | null;
+
     /** Whether a cached SoT index is available for incremental processing. */
     hasCachedSotIndex: boolean;
 
@@ -78,6 +86,21 @@ export interface UseWorkerPoolReturn {
     /** Restore a previously persisted SoT index. */
     restoreCachedSotIndex: (serialized: string) => void;
 
+    /**
+     * Reviewer-confirmed link overrides passed to every satellite join, for
+     * persistence alongside the SoT index.
+     */
+    overrideTable: OverrideTable;
+
+    /**
+     * Record a reviewer-confirmed link. Replaces any override for the same
+     * system and satellite key; applies to satellites processed afterwards.
+     */
+    recordOverride: (override: LinkOverride) => void;
+
+    /** Restore a previously persisted override table. */
+    restoreOverrideTable: (table: OverrideTable) => void;
+
     /** Restore previously persisted SoT stats. */
     restoreSotStats: (stats: IndexStats) => void;
 
@@ -88,6 +111,15 @@ export interface UseWorkerPoolReturn {
 /** Duration in ms after which a worker with no progress is considered crashed. */
 const WORKER_TIMEOUT_MS = 15_000;
 
+/**
+ * Duration in ms allowed for the report merge. Scoring is a single WASM call
+ * that posts no progress, so it gets a longer budget than a streaming parse.
+ */
+const MERGE_TIMEOUT_MS = 120_000;
+
+/** Timeout map key of the report merge worker. */
+const MERGE_TIMEOUT_KEY = '__merge__';
+
 // NOTE: Worker URLs must be inline in `new Worker(new URL(...), ...)` calls
 // for Vite to statically detect and bundle them. Do NOT extract into variables.
 
@@ -108,7 +140,11 @@ export function useWorkerPool(): UseWorkerPoolReturn {
     const [joinResults, setJoinResults] = useState<Map<string, JoinResult>>(
         () => new Map()
     );
+    const [masterReport, setMasterReport] = useState<MasterReport | null>(null);
     const [cachedSotIndex, setCachedSotIndex] = useState<string | null>(null);
+    const [overrideTable, setOverrideTable] = useState<OverrideTable>(
+        () => ({overrides: []})
+    );
     const [logs, setLogs] = useState<LogEntry[]>([]);
 
     /** Append a timestamped log entry. */
@@ -284,6 +320,7 @@ export function useWorkerPool(): UseWorkerPoolReturn {
                                         buffer,
                                         systemName: fileEntry.systemName,
                                         columnMap: fileEntry.columnMapping ?? undefined,
+                                        overrideTable: overrideTable.overrides.length > 0 ? overrideTable : undefined,
                                         fileId: fileEntry.id,
                                     });
                                     break;
@@ -353,12 +390,92 @@ export function useWorkerPool(): UseWorkerPoolReturn {
                     });
             });
         },
-        [waitForReady, postToWorker, resetWorkerTimeout, addLog]
+        [waitForReady, postToWorker, resetWorkerTimeout, overrideTable, addLog]
+    );
+
+    /**
+     * Score join results into the master report using a dedicated SoT worker.
+     *
+     * @param serializedIndex - The serialized SoT index the results were joined against
+     * @param results         - Join results of every processed satellite file
+     * @returns The master report, or null if the merge failed
+     */
+    const mergeReport = useCallback(
+        (
+            serializedIndex: string,
+            results: Map<string, JoinResult>
+        ): Promise<MasterReport | null> => {
+            return new Promise((resolve) => {
+                if (abortedRef.current) {
+                    resolve(null);
+                    return;
+                }
+
+                addLog('Scoring risk and building report...');
+                waitForReady(
+                    new Worker(new URL('../workers/sot.worker.ts', import.meta.url), {type: 'module'})
+                ).then((worker) => {
+                    /** Stop the merge timeout and the worker, then settle. */
+                    const finish = (report: MasterReport | null) => {
+                        const existing = timeoutMapRef.current.get(MERGE_TIMEOUT_KEY);
+                        if (existing !== undefined) {
+                            clearTimeout(existing);
+                            timeoutMapRef.current.delete(MERGE_TIMEOUT_KEY);
+                        }
+                        worker.terminate();
+                        resolve(report);
+                    };
+
+                    // Set up crash detection timeout
+                    timeoutMapRef.current.set(MERGE_TIMEOUT_KEY, setTimeout(() => {
+                        addLog('Error: report merge worker stopped responding (timeout)');
+                        finish(null);
+                    }, MERGE_TIMEOUT_MS));
+
+                    worker.addEventListener(
+                        'message',
+                        (event: MessageEvent<WorkerOutMessage>) => {
+                            const msg = event.data;
+
+                            switch (msg.type) {
+                                case 'MERGE_RESULT':
+                                    addLog(`Report built — ${msg.report.totalUsers} users, ${msg.report.allEntries.length} entries`);
+                                    finish(msg.report);
+                                    break;
+
+                                case 'ERROR':
+                                    addLog(`Error: report merge — ${msg.error}`);
+                                    finish(null);
+                                    break;
+                            }
+                        }
+                    );
+
+                    worker.addEventListener('error', (event) => {
+                        addLog(`Error: report merge worker crashed — ${event.message || 'Unknown error'}`);
+                        finish(null);
+                    });
+
+                    postToWorker(worker, {
+                        type: 'MERGE_RESULTS',
+                        serializedIndex,
+                        joinResults: [...results.values()],
+                        processingTimestamp: Date.now(),
+                    });
+                })
+                    .catch((err: Error) => {
+                        addLog(`Error: failed to spawn report merge worker — ${err.message}`);
+                        resolve(null);
+                    });
+            });
+        },
+        [waitForReady, postToWorker, addLog]
     );
 
     /**
      * Process additional satellite files using the cached SoT index.
-     * Appends results to the existing joinResults map without resetting state.
+     * Appends results to the existing joinResults map without resetting state,
+     * then rebuilds the master report from all results.
      */
     const processAdditionalSatellites = useCallback(
         async (
@@ -402,16 +519,22 @@ export function useWorkerPool(): UseWorkerPoolReturn {
                 }
 
                 // Append new results to existing joinResults map
-                setJoinResults((prev) => {
-                    const next = new Map(prev);
-                    for (let i = 0; i < satellites.length; i++) {
-                        const result = results[i];
-                        if (result !== null) {
-                            next.set(satellites[i].id, result);
-                        }
+                const next = new Map(joinResults);
+                for (let i = 0; i < satellites.length; i++) {
+                    const result = results[i];
+                    if (result !== null) {
+                        next.set(satellites[i].id, result);
                     }
-                    return next;
-                });
+                }
+
+                const merged = await mergeReport(cachedSotIndex, next);
+                if (abortedRef.current) {
+                    setIsProcessing(false);
+                    return;
+                }
+
+                setJoinResults(next);
+                setMasterReport(merged);
             } catch (err) {
                 const message =
                     err instanceof Error ? err.message : 'Unknown processing error';
@@ -427,7 +550,7 @@ export function useWorkerPool(): UseWorkerPoolReturn {
                 setIsProcessing(false);
             }
         },
-        [cachedSotIndex, processSatellite, clearAllTimeouts]
+        [cachedSotIndex, joinResults, processSatellite, mergeReport, clearAllTimeouts]
     );
 
     /**
@@ -446,6 +569,7 @@ export function useWorkerPool(): UseWorkerPoolReturn {
             setIsProcessing(true);
             setSotStats(null);
             setJoinResults(new Map());
+            setMasterReport(null);
             setLogs([]);
 
             try {
@@ -610,7 +734,20 @@ export function useWorkerPool(): UseWorkerPoolReturn {
                     }
                 }
 
+                // -------------------------------------------------------------------
+                // Phase 3: Score and merge in Go
+                // -------------------------------------------------------------------
+                const merged = resultMap.size > 0
+                    ? await mergeReport(sotResult.serializedIndex, resultMap)
+                    : null;
+
+                if (abortedRef.current) {
+                    setIsProcessing(false);
+                    return;
+                }
+
                 setJoinResults(resultMap);
+                setMasterReport(merged);
                 addLog('Processing complete');
             } catch (err) {
                 const message =
@@ -632,6 +769,7 @@ export function useWorkerPool(): UseWorkerPoolReturn {
             waitForReady,
             postToWorker,
             processSatellite,
+            mergeReport,
             resetWorkerTimeout,
             clearAllTimeouts,
             addLog,
@@ -646,6 +784,19 @@ export function useWorkerPool(): UseWorkerPoolReturn {
         setSotStats(stats);
     }, []);
 
+    const recordOverride = useCallback((override: LinkOverride) => {
+        const sameKey = (o: LinkOverride) =>
+            o.system === override.system
+            && o.satelliteKey.toLowerCase() === override.satelliteKey.toLowerCase();
+        setOverrideTable((prev) => ({
+            overrides: [...prev.overrides.filter((o) => !sameKey(o)), override],
+        }));
+    }, []);
+
+    const restoreOverrideTable = useCallback((table: OverrideTable) => {
+        setOverrideTable(table);
+    }, []);
+
     return {
         startProcessing,
         processAdditionalSatellites,
@@ -653,9 +804,13 @@ export function useWorkerPool(): UseWorkerPoolReturn {
         isProcessing,
         sotStats,
         joinResults,
+        masterReport,
         hasCachedSotIndex: cachedSotIndex !== null,
         cachedSotIndex,
         restoreCachedSotIndex,
+        overrideTable,
+        recordOverride,
+        restoreOverrideTable,
         restoreSotStats,
         logs,
     };
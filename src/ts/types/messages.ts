@@ -8,7 +8,7 @@
  * Worker -> Main Thread:  WorkerOutMessage
  */
 
-import type {ColumnMapping, IndexStats, JoinResult} from './schema';
+import type {ColumnMapping, IndexStats, JoinResult, MasterReport, OverrideTable} from './schema';
 
 // ---------------------------------------------------------------------------
 // Main Thread -> Worker Messages
@@ -19,6 +19,7 @@ export type WorkerInMessage =
     | ParseSoTMessage
     | LoadSoTIndexMessage
     | ParseSatelliteMessage
+    | MergeResultsMessage
     | AbortMessage;
 
 /** Instruct the SoT worker to parse a CSV file and build the SoT index. */
@@ -51,10 +52,26 @@ export interface ParseSatelliteMessage {
     systemName: string;
     /** Optional user-defined column mapping overrides. */
     columnMap?: ColumnMapping;
+    /** Reviewer-confirmed link overrides, applied before any automatic match. */
+    overrideTable?: OverrideTable;
     /** Unique identifier of the file being processed. */
     fileId: string;
 }
 
+/**
+ * Instruct the SoT worker to score all join results and build the master report.
+ * Sent once every satellite worker has returned its JOIN_RESULT.
+ */
+export interface MergeResultsMessage {
+    type: 'MERGE_RESULTS';
+    /** JSON-serialized SoT index from SOT_INDEX_READY. */
+    serializedIndex: string;
+    /** Join results of every processed satellite file. */
+    joinResults: JoinResult[];
+    /** Review "now" in Unix milliseconds, used for dormancy and termination gaps. */
+    processingTimestamp: number;
+}
+
 /** Instruct the worker to abort all current operations and terminate. */
 export interface AbortMessage {
     type: 'ABORT';
@@ -70,6 +87,7 @@ export type WorkerOutMessage =
     | SoTIndexReadyMessage
     | SoTIndexLoadedMessage
     | JoinResultMessage
+    | MergeResultMessage
     | ProgressMessage
     | ErrorMessage;
 
@@ -105,6 +123,12 @@ export interface JoinResultMessage {
     fileId: string;
 }
 
+/** Posted by the SoT worker with the master report built by uarMergeResults. */
+export interface MergeResultMessage {
+    type: 'MERGE_RESULT';
+    report: MasterReport;
+}
+
 /** Posted periodically by a worker to report processing progress. */
 export interface ProgressMessage {
     type: 'PROGRESS';
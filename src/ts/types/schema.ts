@@ -19,19 +19,27 @@ export type ReviewAction = 'approve' | 'revoke' | 'flag';
 /**
  * How a satellite record was matched to a SoT record.
  *
+ * - manual_override: Reviewer-confirmed link from the override table
  * - exact_email:     Primary key match on normalized email
  * - exact_id:        Secondary match on employee/user ID
  * - fuzzy_name:      Normalized name match (Levenshtein >= 0.85, clear winner)
  * - fuzzy_ambiguous: Multiple fuzzy candidates within 0.10 similarity spread
+ * - reviewer_selected: Fuzzy/ambiguous match re-resolved to a reviewer-chosen candidate
+ * - transitive:      Linked to SoT through another system's account (identity graph)
  * - orphan:          Satellite record with no SoT match
+ * - non_human:       Unmatched service, shared/generic, or break-glass account
  * - no_access:       SoT record with no satellite presence
  */
 export type MatchType =
+    | 'manual_override'
     | 'exact_email'
     | 'exact_id'
     | 'fuzzy_name'
     | 'fuzzy_ambiguous'
+    | 'reviewer_selected'
+    | 'transitive'
     | 'orphan'
+    | 'non_human'
     | 'no_access';
 
 /**
@@ -107,7 +115,16 @@ export interface SoTRecord {
     email: string;
     department: string;
     manager: string;
+    title: string;
     employmentStatus: string;
+    /** employmentStatus mapped to a lifecycle state; empty when not recognized. */
+    lifecycleState: '' | 'pre_hire' | 'active' | 'leave' | 'suspended' | 'terminated';
+    /** Start (hire) date from the SoT, if mapped. */
+    startDate: string;
+    /** Termination date from the SoT, if mapped. */
+    terminationDate: string;
+    /** Contract end date for contractors and fixed-term workers, if mapped. */
+    contractEndDate: string;
     adminInfo: string;
 }
 
@@ -116,11 +133,20 @@ export interface SatelliteRecord {
     email: string;
     userId: string;
     displayName: string;
+    department: string;
+    manager: string;
+    title: string;
     role: string;
     entitlement: string;
     /** ISO 8601 date string. */
     lastLogin: string;
     accountStatus: string;
+    /** Value of the account-type column, if mapped (e.g. "service", "user"). */
+    accountType: string;
+    /** Date the account was disabled, if the export carries one. */
+    disabledDate: string;
+    /** Date the account was created, if the export carries one. */
+    createdDate: string;
     sourceFile: string;
     sourceRow: number;
 }
@@ -131,8 +157,12 @@ export interface SatelliteRecord {
 
 /** Complete result of joining a satellite file against the SoT index. */
 export interface JoinResult {
+    /** Satellite system name the records were joined from. */
+    system: string;
     matched: MatchedRecord[];
     orphans: OrphanRecord[];
+    /** Unmatched service, shared/generic, and break-glass accounts. */
+    nonHuman: OrphanRecord[];
     stats: JoinStats;
 }
 
@@ -143,13 +173,109 @@ export interface MatchedRecord {
     /** exact_email | exact_id | fuzzy_name */
     matchType: string;
     conflicts: FieldConflict[];
+    /** SoT records considered by the fuzzy name step, best score first. */
+    candidates?: MatchCandidate[];
+    /** How the join cascade reached this match, one entry per step. */
+    evidence: MatchEvidence[];
+    /** Accounts traversed for a transitive match, starting with this one. */
+    path?: IdentityHop[];
+    /** Reviewer-confirmed link behind a manual_override match. */
+    override?: LinkOverride;
+    classification: AccountClassification;
+}
+
+/** Account class assigned by the Go classifier. Mirrors Go AccountClassification JSON. */
+export interface AccountClassification {
+    class: 'human' | 'service' | 'shared' | 'break_glass';
+    reason?: string;
+}
+
+/**
+ * A reviewer-confirmed decision about one satellite account, carried across
+ * review cycles. Mirrors Go LinkOverride JSON.
+ */
+export interface LinkOverride {
+    system: string;
+    /** Compared case-insensitively against the account's email, then user ID. */
+    satelliteKey: string;
+    kind: 'person' | 'service_account' | 'not_a_person';
+    /** The linked person, or the owner of a service account. */
+    canonicalId?: string;
+    confirmedBy: string;
+    /** RFC 3339 timestamp. */
+    confirmedAt: string;
+    note?: string;
+}
+
+/**
+ * Reviewer-confirmed link overrides, persisted with the SoT index and passed
+ * to every satellite join. Mirrors Go OverrideTable JSON.
+ */
+export interface OverrideTable {
+    overrides: LinkOverride[];
+}
+
+/** One account on a transitive identity path. Mirrors Go IdentityHop JSON. */
+export interface IdentityHop {
+    system: string;
+    account: string;
+    /** Identifier shared with the previous hop; absent for the starting account. */
+    via?: string;
+}
+
+/** A SoT record considered during fuzzy name matching. Mirrors Go MatchCandidate JSON. */
+export interface MatchCandidate {
+    canonicalId: string;
+    employeeId: string;
+    displayName: string;
+    department: string;
+    /** Similarity of the best key (0.0 - 1.0). */
+    score: number;
+    /** Key that nearly matched, for orphan suggestions: name | emailLocal | userId. */
+    key?: string;
 }
 
 /** A satellite record with no SoT match. */
 export interface OrphanRecord {
     satellite: SatelliteRecord;
-    /** Keys that were attempted for matching (email, userId, normalized name). */
-    attemptedMatches: string[];
+    /** Evidence for each join cascade step (email, employeeId, name). */
+    attemptedMatches: MatchEvidence[];
+    /** Set when a reviewer confirmed the account is not a person, or is a service account. */
+    override?: LinkOverride;
+    /** SoT person a service_account override names as owner, when in the SoT. */
+    owner?: SoTRecord;
+    classification: AccountClassification;
+    /** SoT records that fell just below the match thresholds, best first. */
+    suggestions?: MatchCandidate[];
+}
+
+/** One step of the join cascade for a satellite record. Mirrors Go MatchEvidence JSON. */
+export interface MatchEvidence {
+    /** override | email | employeeId | name | reviewer | identity_graph */
+    key: string;
+    satelliteValue?: string;
+    satelliteNormalized?: string;
+    sotValue?: string;
+    sotNormalized?: string;
+    score: number;
+    /**
+     * Normalization rules applied, e.g. email_case_fold, email_alias_domain,
+     * employee_id_normalize, name_normalize, nickname.
+     */
+    rulesApplied?: string[];
+    /** matched | ambiguous | no_match | skipped */
+    outcome: string;
+    reason?: string;
+}
+
+/** Optional normalization rules for the join cascade. */
+export interface MatchRules {
+    /** Email domain -> the domain it is an alias of. */
+    aliasDomains?: Record<string, string>;
+    /** Nickname -> formal first name. */
+    nicknames?: Record<string, string>;
+    /** Compare employee IDs ignoring case, separators, and leading zeros. */
+    normalizeIds?: boolean;
 }
 
 /** A field where SoT and satellite values diverge. */
@@ -157,18 +283,413 @@ export interface FieldConflict {
     field: string;
     sotValue: string;
     satelliteValue: string;
-    /** Always "sot_wins" -- SoT is authoritative. */
+    /** exact | case_insensitive | normalized_name | fuzzy | status */
+    mode: string;
+    severity: RiskLevel;
+    /** sot_wins | satellite_wins | flag_only */
     resolution: string;
+    /** Value that wins under the resolution; absent for flag_only. */
+    resolvedValue?: string;
 }
 
 /** Aggregate statistics for a single join operation. */
 export interface JoinStats {
     totalProcessed: number;
+    manualOverride: number;
     exactEmail: number;
     exactId: number;
     fuzzyName: number;
     ambiguous: number;
+    transitive: number;
     orphans: number;
+    nonHuman: number;
+}
+
+// ---------------------------------------------------------------------------
+// Master Report Types (TypeScript mirrors of Go report.MasterReport)
+// ---------------------------------------------------------------------------
+
+/** The report produced by uarMergeResults. Mirrors Go MasterReport JSON. */
+export interface MasterReport {
+    metadata: ReportMetadata;
+    users: UserSummary[];
+    orphanEntries: MasterReportEntry[];
+    nonHumanEntries: MasterReportEntry[];
+    /** Matched, orphan, and non-human entries per system, then no_access entries. */
+    allEntries: MasterReportEntry[];
+    totalUsers: number;
+    totalMatched: number;
+    totalOrphans: number;
+    totalNonHuman: number;
+    totalNoAccess: number;
+    riskSummary: RiskSummary;
+    /** Number of entries carrying each finding code. */
+    findingCounts: Record<string, number>;
+    /** Users' SoD and manager findings per risk level; not part of riskSummary. */
+    userRiskSummary: RiskSummary;
+    /** Number of users violating each SoD rule ID. */
+    sodFindingCounts: Record<string, number>;
+    /** Number of users with each manager finding code. */
+    orgFindingCounts: Record<string, number>;
+    duplicates: DuplicateFinding[];
+    totalDuplicates: number;
+    totalSoDConflicts: number;
+    /** One rollup per manager, in SoT order. */
+    orgRollups: OrgRollup[];
+    totalOrgFindings: number;
+    /** Set by uarBuildCampaign. */
+    campaign?: Campaign;
+}
+
+/** What a report was computed from. Mirrors Go ReportMetadata JSON. */
+export interface ReportMetadata {
+    processingTimestamp: number;
+    inputHash: string;
+    /** Covers join-time settings: overrides, match rules, classifier, suggestion limit. */
+    joinHash: string;
+    configHash: string;
+    riskConfig: RiskConfig;
+}
+
+/** Scoring settings loaded with uarLoadRiskConfig. Mirrors Go RiskConfig JSON. */
+export interface RiskConfig {
+    /** Unix milliseconds. */
+    processingTimestamp?: number;
+    /** IANA time zone for dates without an offset; defaults to UTC. */
+    timeZone?: string;
+    dormancyDays?: number;
+    /** Dormancy threshold per system name, e.g. {aws_prod: 30}. */
+    systemDormancyDays?: Record<string, number>;
+    terminationGraceDays?: number;
+    /** Privilege rules per system; recorded in report metadata. */
+    privileges?: PrivilegeCatalog;
+    /** Enables peer-group outlier findings when set. */
+    peerOutliers?: PeerOutlierConfig;
+}
+
+/** Privilege rules keyed by system; "*" applies to every system. Mirrors Go PrivilegeCatalog JSON. */
+export interface PrivilegeCatalog {
+    systems: Record<string, PrivilegeRule[]>;
+}
+
+/** Maps grants matching value to a tier. Mirrors Go PrivilegeRule JSON. */
+export interface PrivilegeRule {
+    match: 'exact' | 'regex' | 'keyword';
+    value: string;
+    tier: PrivilegeAssessment['tier'];
+    weight: number;
+}
+
+/** Peer outlier settings. Mirrors Go PeerOutlierConfig JSON. */
+export interface PeerOutlierConfig {
+    /** Prevalence (0-1) below which access is an outlier; 0 means 0.1. */
+    threshold: number;
+    /** Smallest peer group analyzed; 0 means 5. */
+    minGroupSize: number;
+}
+
+/** One user-system row of the master report. Mirrors Go MasterReportEntry JSON. */
+export interface MasterReportEntry {
+    canonicalId: string;
+    employeeId: string;
+    displayName: string;
+    email: string;
+    department: string;
+    manager: string;
+    employmentStatus: string;
+    /** SoT admin columns for the person, on matched entries. */
+    adminInfo?: string;
+    system: string;
+    role: string;
+    entitlement: string;
+    lastLogin: string;
+    accountStatus: string;
+    /** A join match type, or orphan | non_human | no_access. */
+    matchType: string;
+    riskLevel: RiskLevel;
+    riskScore: number;
+    conflicts?: FieldConflict[];
+    override?: LinkOverride;
+    /** Canonical ID of a non-human account's reviewer-confirmed owner. */
+    owner?: string;
+    accountClass?: AccountClassification['class'];
+    findings?: RiskFinding[];
+    privilege?: PrivilegeAssessment;
+    sourceFile: string;
+    sourceRow: number;
+}
+
+/** A single risk rule that fired for an entry. Mirrors Go RiskFinding JSON. */
+export interface RiskFinding {
+    code: string;
+    level: RiskLevel;
+    score: number;
+    description: string;
+    gapDays?: number;
+    peerGroupSize?: number;
+    prevalence?: number;
+}
+
+/** The most powerful grant of an account. Mirrors Go PrivilegeAssessment JSON. */
+export interface PrivilegeAssessment {
+    /** standard | elevated | admin | super_admin */
+    tier: string;
+    weight?: number;
+    grant?: string;
+    rule?: string;
+}
+
+/** All report entries for one person. Mirrors Go UserSummary JSON. */
+export interface UserSummary {
+    canonicalId: string;
+    displayName: string;
+    email: string;
+    maxRiskLevel: RiskLevel;
+    maxRiskScore: number;
+    entries: MasterReportEntry[];
+    sodFindings?: SoDFinding[];
+    /** manager_missing | manager_ambiguous | manager_terminated | manager_cycle */
+    orgFindings?: RiskFinding[];
+}
+
+/** Access and risk across a manager's subtree. Mirrors Go OrgRollup JSON. */
+export interface OrgRollup {
+    canonicalId: string;
+    displayName: string;
+    lifecycleState?: SoTRecord['lifecycleState'];
+    directReports: number;
+    /** People in the subtree, including the manager. */
+    headcount: number;
+    accounts: number;
+    maxRiskLevel: RiskLevel;
+    maxRiskScore: number;
+    riskSummary: RiskSummary;
+    orgFindings: number;
+}
+
+/** A person holding both sides of a segregation-of-duties rule. Mirrors Go SoDFinding JSON. */
+export interface SoDFinding {
+    ruleId: string;
+    level: RiskLevel;
+    score: number;
+    description: string;
+    left: SoDGrant;
+    right: SoDGrant;
+}
+
+/** One role or entitlement value held in one system. Mirrors Go SoDGrant JSON. */
+export interface SoDGrant {
+    system: string;
+    grant: string;
+    sourceRow: number;
+}
+
+/** Entry counts per risk level. Mirrors Go RiskSummary JSON. */
+export interface RiskSummary {
+    critical: number;
+    high: number;
+    medium: number;
+    low: number;
+    info: number;
+}
+
+/** Per-reviewer work packages built by uarBuildCampaign. Mirrors Go Campaign JSON. */
+export interface Campaign {
+    name: string;
+    processingTimestamp: number;
+    workPackages: WorkPackage[];
+    /** Accounts no owner, manager, or fallback reviewer could take. */
+    unassigned: ReviewItem[];
+    progress: CampaignProgress;
+    /** Set by uarCarryForward. */
+    carryForward?: CarryForwardSummary;
+}
+
+/** Decisions carried from the previous cycle. Mirrors Go CarryForwardSummary JSON. */
+export interface CarryForwardSummary {
+    from: DecisionOrigin;
+    carried: number;
+    reReview: number;
+}
+
+/** Everything one reviewer has to review. Mirrors Go WorkPackage JSON. */
+export interface WorkPackage {
+    /** Reviewer canonical ID. */
+    reviewer: string;
+    reviewerName: string;
+    reviewerEmail: string;
+    items: ReviewItem[];
+    progress: CampaignProgress;
+}
+
+/** Review item counts by decision. Mirrors Go CampaignProgress JSON. */
+export interface CampaignProgress {
+    total: number;
+    completed: number;
+    pending: number;
+    approved: number;
+    revoked: number;
+}
+
+export type ReviewerSource = 'entitlement_owner' | 'system_owner' | 'manager' | 'fallback';
+
+/** One account to review. Mirrors Go ReviewItem JSON. */
+export interface ReviewItem {
+    /** system:identity:entitlement from the AccessKey, with "#n" for the nth repeat of a key. */
+    id: string;
+    key: AccessKey;
+    canonicalId?: string;
+    displayName: string;
+    email: string;
+    system: string;
+    role: string;
+    entitlement: string;
+    accountStatus: string;
+    matchType: string;
+    riskLevel: RiskLevel;
+    riskScore: number;
+    sourceRow: number;
+    reviewer?: string;
+    source?: ReviewerSource;
+    reassignments?: Reassignment[];
+    decision?: ReviewDecision;
+    carryForward?: 'carried' | 're_review';
+    /** role | accountStatus | privilege | riskLevel | revoke_not_actioned */
+    reReviewReasons?: string[];
+}
+
+/** A candidate reviewer who was passed over. Mirrors Go Reassignment JSON. */
+export interface Reassignment {
+    source: ReviewerSource;
+    /** Candidate canonical ID, or the unresolved owner value. */
+    from: string;
+    reason: 'reviewer_terminated' | 'self_review' | 'reviewer_not_found';
+}
+
+/** A reviewer's verdict. Mirrors Go ReviewDecision JSON. */
+export interface ReviewDecision {
+    action: 'approve' | 'revoke';
+    reviewer: string;
+    /** Unix milliseconds. */
+    decidedAt: number;
+    notes?: string;
+    roleActions?: RoleAction[];
+    /** The cycle a carried decision was made in. */
+    origin?: DecisionOrigin;
+}
+
+/** A verdict on one role of an account. Mirrors Go RoleAction JSON. */
+export interface RoleAction {
+    role: string;
+    action: 'approve' | 'revoke';
+}
+
+/** The review cycle and item a decision was made on. Mirrors Go DecisionOrigin JSON. */
+export interface DecisionOrigin {
+    campaign: string;
+    processingTimestamp: number;
+    itemId?: string;
+}
+
+/** Changes between two review cycles, from uarDiffReports. Mirrors Go ReportDiff JSON. */
+export interface ReportDiff {
+    previousTimestamp: number;
+    currentTimestamp: number;
+    /** Current accounts in report order, then removed accounts. */
+    entries: EntryDiff[];
+    newlyTerminated: UserChange[];
+    summary: DiffSummary;
+}
+
+export type DiffKind = 'added' | 'removed' | 'unchanged' | 'changed' | 'revoke_not_actioned';
+
+/** One account's classification between two cycles. Mirrors Go EntryDiff JSON. */
+export interface EntryDiff {
+    kind: DiffKind;
+    key: AccessKey;
+    displayName: string;
+    /** role | accountStatus */
+    changes?: string[];
+    newPrivilege?: boolean;
+    previous?: MasterReportEntry;
+    current?: MasterReportEntry;
+    /** The previous cycle's decision on the account. */
+    decision?: ReviewDecision;
+}
+
+/** Identifies an account across cycles. Mirrors Go AccessKey JSON. */
+export interface AccessKey {
+    /** Canonical ID, else lowercase email, else normalized display name. */
+    identity: string;
+    system: string;
+    entitlement: string;
+}
+
+/** A person terminated since the previous cycle. Mirrors Go UserChange JSON. */
+export interface UserChange {
+    canonicalId: string;
+    displayName: string;
+    previousStatus: string;
+    currentStatus: string;
+    activeAccounts: number;
+}
+
+/** Counts per classification. Mirrors Go DiffSummary JSON. */
+export interface DiffSummary {
+    added: number;
+    removed: number;
+    unchanged: number;
+    changed: number;
+    revokeNotActioned: number;
+    newPrivileged: number;
+    newlyTerminated: number;
+}
+
+/** Outcomes of the previous cycle's revoke decisions, from uarVerifyRemediation. Mirrors Go RemediationReport JSON. */
+export interface RemediationReport {
+    processingTimestamp: number;
+    items: RemediationItem[];
+    summary: RemediationSummary;
+}
+
+export type RemediationStatus = 'revoked_removed' | 'revoked_still_present' | 'revoked_regranted';
+
+/** One revoke decision or revoked role and what became of it. Mirrors Go RemediationItem JSON. */
+export interface RemediationItem {
+    status: RemediationStatus;
+    key: AccessKey;
+    displayName: string;
+    /** The revoked role for a role action; absent for the whole account. */
+    role?: string;
+    /** The review cycle and item the decision was made on. */
+    origin: DecisionOrigin;
+    decision: ReviewDecision;
+    previous: MasterReportEntry;
+    current?: MasterReportEntry;
+    /** Days since the decision, for access still present. */
+    ageDays?: number;
+    /** revocation_overdue | revoked_access_regranted */
+    finding?: RiskFinding;
+}
+
+/** Remediation item counts by status. Mirrors Go RemediationSummary JSON. */
+export interface RemediationSummary {
+    removed: number;
+    stillPresent: number;
+    regranted: number;
+}
+
+/** A duplicate identity in the SoT or a satellite. Mirrors Go DuplicateFinding JSON. */
+export interface DuplicateFinding {
+    kind: 'sot_key_collision' | 'satellite_multiple_accounts' | 'rehire' | 'possible_rehire';
+    /** email | employeeId | name | canonicalId */
+    key: string;
+    value: string;
+    system?: string;
+    riskLevel: RiskLevel;
+    description: string;
+    sotRecords?: SoTRecord[];
+    satelliteRecords?: SatelliteRecord[];
 }
 
 // ---------------------------------------------------------------------------
@@ -257,7 +257,10 @@ export async function exportXLSX(report: MasterReport): Promise<void> {
         orphan.satellite.role,
         orphan.satellite.accountStatus,
         orphan.satellite.lastLogin,
-        orphan.attemptedMatches.join('; '),
+        orphan.attemptedMatches
+            .filter((step) => step.outcome !== 'skipped')
+            .map((step) => `${step.key}:${step.satelliteNormalized ?? ''}`)
+            .join('; '),
     ]);
     const orphanSheet = XLSX.utils.aoa_to_sheet([orphanHeaders, ...orphanRows]);
     XLSX.utils.book_append_sheet(workbook, orphanSheet, 'Orphan Accounts');
@@ -1,16 +1,13 @@
 /**
  * Merge join results into canonical records for the report.
  *
- * Converts Go WASM JoinResult objects (keyed by file ID) into the flat
- * CanonicalRecord[] array consumed by the report viewer.
+ * Converts the Go master report, plus the Go WASM JoinResult objects (keyed by
+ * file ID) it was built from, into the flat CanonicalRecord[] array consumed
+ * by the report viewer.
  *
- * Risk scoring follows the design document Section 8:
- *   CRITICAL (95): Terminated employee with active system access
- *   HIGH     (75): Orphan record (no SoT match)
- *   HIGH     (70): Fuzzy ambiguous match (multiple candidates)
- *   MEDIUM   (50): Fuzzy name match (single candidate, threshold met)
- *   LOW      (25): Exact employee/user ID match (secondary key)
- *   INFO     (10): Exact email match (primary key, clean)
+ * Rows are built from the Go master report (uarMergeResults), which links
+ * orphans across systems and applies the configured risk policy (design
+ * document Section 8) to every entry. Nothing is matched or scored here.
  *
  * See design document Sections 4.3, 8.1.
  */
@@ -19,42 +16,47 @@ import type {
     CanonicalRecord,
     FileEntry,
     JoinResult,
-    MatchedRecord,
+    MasterReport,
     MatchType,
     RiskLevel,
+    SatelliteRecord,
 } from '../types/schema';
 
 // ---------------------------------------------------------------------------
-// Risk scoring
+// Satellite lookup
 // ---------------------------------------------------------------------------
 
+/** Key of a satellite account in the master report: system plus source row. */
+function entryKey(system: string, sourceRow: number): string {
+    return `${system}::${sourceRow}`;
+}
+
 /**
- * Compute the risk level and numeric score for a matched record.
- *
- * Terminated employees with active access always get CRITICAL regardless
- * of match type. Otherwise, the match type determines the base risk.
+ * Index every satellite account in the join results by system and source row,
+ * so report entries can be joined back to fields the report does not carry.
  */
-function scoreMatched(matched: MatchedRecord): { riskLevel: RiskLevel; riskScore: number } {
-    // Highest priority: terminated employee with active access
-    if (
-        matched.sot.employmentStatus.toLowerCase() === 'terminated' &&
-        matched.satellite.accountStatus.toLowerCase() === 'active'
-    ) {
-        return {riskLevel: 'CRITICAL', riskScore: 95};
+function indexSatellites(joinResults: Map<string, JoinResult>): Map<string, SatelliteRecord> {
+    const satellites = new Map<string, SatelliteRecord>();
+    for (const joinResult of joinResults.values()) {
+        const records = [
+            ...joinResult.matched,
+            ...joinResult.orphans,
+            ...(joinResult.nonHuman ?? []),
+        ];
+        for (const {satellite} of records) {
+            satellites.set(entryKey(satellite.sourceFile, satellite.sourceRow), satellite);
+        }
     }
+    return satellites;
+}
 
-    switch (matched.matchType) {
-        case 'fuzzy_ambiguous':
-            return {riskLevel: 'HIGH', riskScore: 70};
-        case 'fuzzy_name':
-            return {riskLevel: 'MEDIUM', riskScore: 50};
-        case 'exact_id':
-            return {riskLevel: 'LOW', riskScore: 25};
-        case 'exact_email':
-            return {riskLevel: 'INFO', riskScore: 10};
-        default:
-            return {riskLevel: 'MEDIUM', riskScore: 50};
-    }
+/** Review note for an account the Go engine did not score. */
+const UNSCORED_NOTE = 'Risk not scored: the report merge failed or did not include this account';
+
+/** Role column of a matched account, with the SoT admin columns appended. */
+function roleWithAdminInfo(role: string, adminInfo: string | undefined): string {
+    if (!adminInfo) return role;
+    return role ? role + '; ' + adminInfo : adminInfo;
 }
 
 // ---------------------------------------------------------------------------
@@ -193,65 +195,125 @@ function deduplicateRecords(records: CanonicalRecord[]): CanonicalRecord[] {
 // ---------------------------------------------------------------------------
 
 /**
- * Merge all join results into a flat array of canonical records.
- *
- * After building records from all join results, deduplicates entries where
- * the same user appears multiple times in the same system (e.g. duplicate
- * rows in a satellite CSV). Roles and entitlements are merged; the highest
- * risk score is kept.
- *
- * @param joinResults - Map from file ID to JoinResult (from worker pool)
- * @param files       - File entries (to resolve file ID -> systemName)
- * @returns Canonical records ready for the report hook
+ * Build canonical records from the Go master report's entries, so match types,
+ * transitive links, and risk all come from the engine. Users without any
+ * access (no_access) are not listed.
  */
-export function mergeJoinResults(
+function recordsFromReport(
+    report: MasterReport,
+    joinResults: Map<string, JoinResult>
+): CanonicalRecord[] {
+    const satellites = indexSatellites(joinResults);
+    const records: CanonicalRecord[] = [];
+
+    for (const entry of report.allEntries) {
+        if (entry.matchType === 'no_access') continue;
+        const satellite = satellites.get(entryKey(entry.sourceFile, entry.sourceRow));
+
+        const base = {
+            system: entry.system,
+            entitlement: entry.entitlement,
+            lastLogin: entry.lastLogin,
+            accountStatus: entry.accountStatus,
+            sourceFile: entry.sourceFile,
+            sourceRowNumber: entry.sourceRow,
+            matchType: entry.matchType as MatchType,
+            riskLevel: entry.riskLevel,
+            riskScore: entry.riskScore,
+        };
+
+        // Matched records: SoT identity + satellite access
+        if (entry.canonicalId) {
+            const fillFromSatellite = !entry.displayName.trim() && !!satellite?.displayName?.trim();
+
+            records.push({
+                ...base,
+                canonicalId: `${entry.canonicalId}::${entry.system}::${entry.sourceRow}`,
+                employeeId: entry.employeeId,
+                displayName: fillFromSatellite ? satellite!.displayName : entry.displayName,
+                email: entry.email,
+                department: entry.department,
+                manager: entry.manager,
+                employmentStatus: entry.employmentStatus,
+                role: roleWithAdminInfo(entry.role, entry.adminInfo),
+                reviewAction: fillFromSatellite ? 'flag' : null,
+                reviewNote: fillFromSatellite
+                    ? `Display name sourced from satellite (${entry.system}), not present in SoT`
+                    : '',
+            });
+            continue;
+        }
+
+        // Orphan and non-human records: satellite-only, no SoT match
+        records.push({
+            ...base,
+            canonicalId: `orphan::${entry.system}::${entry.sourceRow}`,
+            employeeId: satellite?.userId ?? '',
+            displayName: entry.displayName,
+            email: entry.email,
+            department: '',
+            manager: '',
+            employmentStatus: '',
+            role: entry.role,
+            reviewAction: null,
+            reviewNote: '',
+        });
+    }
+
+    return records;
+}
+
+/**
+ * Build canonical records straight from the join results when no master
+ * report is available (the merge failed). Every record is unscored and
+ * flagged for review instead of looking clean.
+ */
+function recordsFromJoinResults(
     joinResults: Map<string, JoinResult>,
     files: FileEntry[]
 ): CanonicalRecord[] {
     const fileMap = new Map(files.map((f) => [f.id, f]));
     const records: CanonicalRecord[] = [];
+    const unscored = {
+        riskLevel: 'INFO' as RiskLevel,
+        riskScore: 0,
+        reviewAction: 'flag' as const,
+        reviewNote: UNSCORED_NOTE,
+    };
 
     for (const [fileId, joinResult] of joinResults) {
         const fileEntry = fileMap.get(fileId);
         const systemName = fileEntry?.systemName ?? fileId;
 
-        // Matched records: SoT identity + satellite access
         for (const matched of joinResult.matched) {
-            const {riskLevel, riskScore} = scoreMatched(matched);
-
             const sotNameEmpty = !matched.sot.displayName?.trim();
             const satelliteHasName = !!matched.satellite.displayName?.trim();
-            const fillFromSatellite = sotNameEmpty && satelliteHasName;
 
             records.push({
                 canonicalId: `${matched.sot.canonicalId}::${systemName}::${matched.satellite.sourceRow}`,
                 employeeId: matched.sot.employeeId,
-                displayName: fillFromSatellite ? matched.satellite.displayName : matched.sot.displayName,
+                displayName: sotNameEmpty && satelliteHasName ? matched.satellite.displayName : matched.sot.displayName,
                 email: matched.sot.email,
                 department: matched.sot.department,
                 manager: matched.sot.manager,
                 employmentStatus: matched.sot.employmentStatus,
                 system: systemName,
-                role: matched.sot.adminInfo
-                    ? (matched.satellite.role
-                        ? matched.satellite.role + '; ' + matched.sot.adminInfo
-                        : matched.sot.adminInfo)
-                    : matched.satellite.role,
+                role: roleWithAdminInfo(matched.satellite.role, matched.sot.adminInfo),
                 entitlement: matched.satellite.entitlement,
                 lastLogin: matched.satellite.lastLogin,
                 accountStatus: matched.satellite.accountStatus,
-                reviewAction: fillFromSatellite ? 'flag' : null,
-                reviewNote: fillFromSatellite ? `Display name sourced from satellite (${systemName}), not present in SoT` : '',
                 sourceFile: matched.satellite.sourceFile,
                 sourceRowNumber: matched.satellite.sourceRow,
                 matchType: matched.matchType as MatchType,
-                riskLevel,
-                riskScore,
+                ...unscored,
             });
         }
 
-        // Orphan records: satellite-only, no SoT match
-        for (const orphan of joinResult.orphans) {
+        const unmatched = [
+            ...joinResult.orphans.map((orphan) => ({orphan, matchType: 'orphan' as const})),
+            ...(joinResult.nonHuman ?? []).map((orphan) => ({orphan, matchType: 'non_human' as const})),
+        ];
+        for (const {orphan, matchType} of unmatched) {
             records.push({
                 canonicalId: `orphan::${systemName}::${orphan.satellite.sourceRow}`,
                 employeeId: orphan.satellite.userId,
@@ -265,16 +327,41 @@ export function mergeJoinResults(
                 entitlement: orphan.satellite.entitlement,
                 lastLogin: orphan.satellite.lastLogin,
                 accountStatus: orphan.satellite.accountStatus,
-                reviewAction: null,
-                reviewNote: '',
                 sourceFile: orphan.satellite.sourceFile,
                 sourceRowNumber: orphan.satellite.sourceRow,
-                matchType: 'orphan',
-                riskLevel: 'HIGH',
-                riskScore: 75,
+                matchType,
+                ...unscored,
             });
         }
     }
 
+    return records;
+}
+
+/**
+ * Merge all join results into a flat array of canonical records.
+ *
+ * Records are built from the Go master report, which has already linked
+ * orphans through the identity graph and scored every account. The join
+ * results supply satellite fields the report does not carry. Without a
+ * report, records are built from the join results and flagged as unscored.
+ *
+ * After building records, deduplicates entries where the same user appears
+ * multiple times in the same system (e.g. duplicate rows in a satellite CSV).
+ * Roles and entitlements are merged; the highest risk score is kept.
+ *
+ * @param joinResults  - Map from file ID to JoinResult (from worker pool)
+ * @param masterReport - Go master report scored from the same join results
+ * @param files        - File entries (to resolve file ID -> systemName)
+ * @returns Canonical records ready for the report hook
+ */
+export function mergeJoinResults(
+    joinResults: Map<string, JoinResult>,
+    masterReport: MasterReport | null,
+    files: FileEntry[]
+): CanonicalRecord[] {
+    const records = masterReport
+        ? recordsFromReport(masterReport, joinResults)
+        : recordsFromJoinResults(joinResults, files);
     return deduplicateRecords(records);
 }
@@ -1,13 +1,13 @@
 /**
  * IndexedDB persistence for UAR Tool app state.
  *
- * Persists report data, review actions, SoT index, and processing metadata
- * so that the report survives page refreshes.
+ * Persists report data, review actions, SoT index, link overrides, and
+ * processing metadata so that the report survives page refreshes.
  *
  * Uses a single object store with a fixed key for simplicity.
  */
 
-import type {CanonicalRecord, IndexStats} from '../types/schema';
+import type {CanonicalRecord, IndexStats, OverrideTable} from '../types/schema';
 
 // ---------------------------------------------------------------------------
 // Persisted State Shape
@@ -16,6 +16,8 @@ import type {CanonicalRecord, IndexStats} from '../types/schema';
 export interface PersistedAppState {
     report: CanonicalRecord[];
     cachedSotIndex: string;
+    /** Reviewer-confirmed links; absent in state saved by older versions. */
+    overrideTable?: OverrideTable;
     processedFileIds: string[];
     sotStats: IndexStats;
 }
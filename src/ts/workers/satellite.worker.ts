@@ -35,7 +35,12 @@ declare const Go: {
 // Declare the WASM-registered global functions
 declare function uarLoadSoTIndex(serializedIndex: string): string;
 
-declare function uarParseSatellite(csvBytes: Uint8Array, systemName: string, columnMapJSON: string): string;
+declare function uarParseSatellite(
+    csvBytes: Uint8Array,
+    systemName: string,
+    columnMapJSON: string,
+    overridesJSON?: string
+): string;
 
 // ---------------------------------------------------------------------------
 // WASM Initialization
@@ -182,7 +187,7 @@ function handleLoadSoTIndex(msg: { serializedIndex: string }): void {
  *
  * Flow:
  *   1. Post PROGRESS 10% (started)
- *   2. Call globalThis.uarParseSatellite(uint8Array, systemName, columnMapJSON)
+ *   2. Call globalThis.uarParseSatellite(uint8Array, systemName, columnMapJSON, overridesJSON)
  *   3. Post PROGRESS 90% (WASM processing complete)
  *   4. Parse result JSON
  *   5. If error key in result -> post ERROR
@@ -196,9 +201,10 @@ function handleParseSatellite(msg: {
         direct: Record<string, string>;
         concat: Array<{ sourceColumns: string[]; separator: string; targetField: string }>
     };
+    overrideTable?: { overrides: unknown[] };
     fileId: string;
 }): void {
-    const {buffer, systemName, columnMap, fileId} = msg;
+    const {buffer, systemName, columnMap, overrideTable, fileId} = msg;
 
     try {
         // Step 1: Signal processing start
@@ -210,11 +216,15 @@ function handleParseSatellite(msg: {
         // Serialize column mapping to JSON (empty object if not provided)
         const columnMapJSON = columnMap ? JSON.stringify(columnMap) : '{}';
 
+        // Serialize the override table (empty string if there are no overrides)
+        const overridesJSON = overrideTable ? JSON.stringify(overrideTable) : '';
+
         // Step 2: Call the Go WASM function
         const resultJSON = (globalThis as any).uarParseSatellite(
             csvBytes,
             systemName,
-            columnMapJSON
+            columnMapJSON,
+            overridesJSON
         ) as string;
 
         // Step 3: WASM processing complete
@@ -3,13 +3,14 @@
  *
  * Dedicated worker that loads the Go WASM engine, parses the SoT CSV,
  * builds the canonical identity index, and returns the serialized index
- * plus statistics to the main thread.
+ * plus statistics to the main thread. Once all satellites are joined, a
+ * fresh SoT worker scores the join results into the master report.
  *
  * Lifecycle:
  *   1. Load wasm_exec.js (Go WASM glue) via importScripts
  *   2. Instantiate uar_engine.wasm and run Go main()
  *   3. Post WASM_READY when JS-callable functions are registered
- *   4. Handle PARSE_SOT messages
+ *   4. Handle PARSE_SOT and MERGE_RESULTS messages
  *   5. Handle ABORT to self-terminate
  *
  * This is a classic (non-module) worker because wasm_exec.js uses
@@ -32,6 +33,12 @@ declare const Go: {
 
 // Declare the WASM-registered global functions
 declare function uarParseSoT(csvBytes: Uint8Array, columnMapJSON: string): string;
+declare function uarMergeResults(
+    serializedIndex: string,
+    joinResultsJSON: string,
+    processingTimestamp: number,
+    conflictChecksJSON?: string
+): string;
 
 // ---------------------------------------------------------------------------
 // WASM Initialization
@@ -110,6 +117,10 @@ self.onmessage = function (event: MessageEvent) {
             handleParseSoT(msg);
             break;
 
+        case 'MERGE_RESULTS':
+            handleMergeResults(msg);
+            break;
+
         case 'ABORT':
             self.close();
             break;
@@ -193,6 +204,45 @@ function handleParseSoT(msg: {
     }
 }
 
+// ---------------------------------------------------------------------------
+// MERGE_RESULTS Handler
+// ---------------------------------------------------------------------------
+
+/**
+ * Score all join results and build the master report in Go, so risk levels
+ * come from the same engine and policy as every other finding.
+ *
+ * Posts MERGE_RESULT with the report, or ERROR if the engine rejects the input.
+ */
+function handleMergeResults(msg: {
+    serializedIndex: string;
+    joinResults: unknown[];
+    processingTimestamp: number;
+}): void {
+    try {
+        const resultJSON = (globalThis as any).uarMergeResults(
+            msg.serializedIndex,
+            JSON.stringify(msg.joinResults),
+            msg.processingTimestamp
+        ) as string;
+
+        const result = JSON.parse(resultJSON);
+        if (result.error) {
+            self.postMessage({type: 'ERROR', fileId: '', error: result.error});
+            return;
+        }
+
+        self.postMessage({type: 'MERGE_RESULT', report: result});
+    } catch (err) {
+        const errorMessage = err instanceof Error ? err.message : String(err);
+        self.postMessage({
+            type: 'ERROR',
+            fileId: '',
+            error: `Report merge failed: ${errorMessage}`,
+        });
+    }
+}
+
 // ---------------------------------------------------------------------------
 // Bootstrap
 // ---------------------------------------------------------------------------